@@ -1,37 +1,82 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
+// CutToACommand is the Command sent by CutToA.
+type CutToACommand struct{}
+
+func (c CutToACommand) Encode() string {
+	return "U0"
+}
+
+func (c CutToACommand) Validate() error {
+	return nil
+}
+
 // CutToA cuts the A/B mixer immediately to the A input of the mixer.
 func (o *Oxtel) CutToA() error {
-	return o.sendCommand("U0")
+	return o.CutToAContext(context.Background())
+}
+
+// CutToAContext is like CutToA but uses ctx for cancellation and deadlines.
+func (o *Oxtel) CutToAContext(ctx context.Context) error {
+	return o.SendContext(ctx, CutToACommand{})
 }
 
 // CutToA_AsString returns the command string used to cut the A/B mixer immediately to the A input of the mixer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a CutToACommand instead.
 func CutToA_AsString() string {
-	return "U0"
+	return CutToACommand{}.Encode()
+}
+
+// CutToBCommand is the Command sent by CutToB.
+type CutToBCommand struct{}
+
+func (c CutToBCommand) Encode() string {
+	return "U1"
+}
+
+func (c CutToBCommand) Validate() error {
+	return nil
 }
 
 // CutToB cuts the A/B mixer immediately to the B input of the mixer.
 func (o *Oxtel) CutToB() error {
-	return o.sendCommand("U1")
+	return o.CutToBContext(context.Background())
+}
+
+// CutToBContext is like CutToB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) CutToBContext(ctx context.Context) error {
+	return o.SendContext(ctx, CutToBCommand{})
 }
 
 // CutToB_AsString returns the command string used to cut the A/B mixer immediately to the B input of the mixer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a CutToBCommand instead.
 func CutToB_AsString() string {
-	return "U1"
+	return CutToBCommand{}.Encode()
 }
 
-// FadeToA fades the A/B mixer to the A input of the mixer over the specified number of fields (interlaces) or frames (progressive).
-func (o *Oxtel) FadeToA(duration uint16) error {
-	if duration > 999 {
+// FadeToACommand is the Command sent by FadeToA.
+type FadeToACommand struct {
+	Duration uint16
+}
+
+func (c FadeToACommand) Encode() string {
+	return fmt.Sprintf("U2%03x", c.Duration)
+}
+
+func (c FadeToACommand) Validate() error {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000 fields/frames",
@@ -39,59 +84,111 @@ func (o *Oxtel) FadeToA(duration uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("U2%03x", duration)
+	return nil
+}
+
+// FadeToA fades the A/B mixer to the A input of the mixer over the specified number of fields (interlaces) or frames (progressive).
+func (o *Oxtel) FadeToA(duration uint16) error {
+	return o.FadeToAContext(context.Background(), duration)
+}
 
-	return o.sendCommand(msg)
+// FadeToAContext is like FadeToA but uses ctx for cancellation and deadlines.
+func (o *Oxtel) FadeToAContext(ctx context.Context, duration uint16) error {
+	return o.SendContext(ctx, FadeToACommand{Duration: duration})
 }
 
 // FadeToA_AsString returns the command string used to fade the A/B mixer to the A input of the mixer over the specified number
 // of fields (interlaces) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a FadeToACommand instead.
 func FadeToA_AsString(duration uint16) string {
-	return fmt.Sprintf("U2%03x", duration)
+	return FadeToACommand{Duration: duration}.Encode()
+}
 
+// FadeToBCommand is the Command sent by FadeToB.
+type FadeToBCommand struct {
+	Duration uint16
 }
 
-// FadeToB fades the A/B mixer to the B input of the mixer over the specified number of fields (interlaces) or frames (progressive).
-func (o *Oxtel) FadeToB(duration uint16) error {
-	if duration > 999 {
+func (c FadeToBCommand) Encode() string {
+	return fmt.Sprintf("U3%03x", c.Duration)
+}
+
+func (c FadeToBCommand) Validate() error {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000 fields/frames",
 			},
 		}
 	}
-	msg := fmt.Sprintf("U3%03x", duration)
 
-	return o.sendCommand(msg)
+	return nil
+}
+
+// FadeToB fades the A/B mixer to the B input of the mixer over the specified number of fields (interlaces) or frames (progressive).
+func (o *Oxtel) FadeToB(duration uint16) error {
+	return o.FadeToBContext(context.Background(), duration)
+}
+
+// FadeToBContext is like FadeToB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) FadeToBContext(ctx context.Context, duration uint16) error {
+	return o.SendContext(ctx, FadeToBCommand{Duration: duration})
 }
 
 // FadeToB_AsString returns the command string used to fade the A/B mixer to the B input of the mixer over the specified number
 // of fields (interlaces) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a FadeToBCommand instead.
 func FadeToB_AsString(duration uint16) string {
-	return fmt.Sprintf("U3%03x", duration)
+	return FadeToBCommand{Duration: duration}.Encode()
+}
 
+// CutABCommand is the Command sent by CutAB.
+type CutABCommand struct{}
+
+func (c CutABCommand) Encode() string {
+	return "U4"
+}
+
+func (c CutABCommand) Validate() error {
+	return nil
 }
 
 // CutAB cuts the A/B mixer to the opposite input form the one currently visible.
 func (o *Oxtel) CutAB() error {
-	return o.sendCommand("U4")
+	return o.CutABContext(context.Background())
+}
+
+// CutABContext is like CutAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) CutABContext(ctx context.Context) error {
+	return o.SendContext(ctx, CutABCommand{})
 }
 
 // CutAB_AsString returns the command string used to cut the A/B mixer to the opposite input form the one currently visible.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a CutABCommand instead.
 func CutAB_AsString() string {
-	return "U4"
+	return CutABCommand{}.Encode()
 }
 
-// FadeAB fades the A/B mixer to the opposite input from the one currently visible over the specified number of
-// fields (interlaces) or frames (progressive).
-func (o *Oxtel) FadeAB(duration uint16) error {
-	if duration > 999 {
+// FadeABCommand is the Command sent by FadeAB.
+type FadeABCommand struct {
+	Duration uint16
+}
+
+func (c FadeABCommand) Encode() string {
+	return fmt.Sprintf("U5%03x", c.Duration)
+}
+
+func (c FadeABCommand) Validate() error {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000 fields/frames",
@@ -99,38 +196,75 @@ func (o *Oxtel) FadeAB(duration uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("U5%03x", duration)
+	return nil
+}
+
+// FadeAB fades the A/B mixer to the opposite input from the one currently visible over the specified number of
+// fields (interlaces) or frames (progressive).
+func (o *Oxtel) FadeAB(duration uint16) error {
+	return o.FadeABContext(context.Background(), duration)
+}
 
-	return o.sendCommand(msg)
+// FadeABContext is like FadeAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) FadeABContext(ctx context.Context, duration uint16) error {
+	return o.SendContext(ctx, FadeABCommand{Duration: duration})
 }
 
 // FadeAB_AsString returns the command string used to fade the A/B mixer to the opposite input from the one currently visible
 // over the specified number of fields (interlaces) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a FadeABCommand instead.
 func FadeAB_AsString(duration uint16) string {
-	return fmt.Sprintf("U5%03x", duration)
+	return FadeABCommand{Duration: duration}.Encode()
+}
+
+// SetTransitionTypeCommand is the Command sent by SetTransitionType.
+type SetTransitionTypeCommand struct {
+	TransitionType OxtelTransitionType
+}
+
+func (c SetTransitionTypeCommand) Encode() string {
+	return fmt.Sprintf("U6%02x", c.TransitionType)
+}
+
+func (c SetTransitionTypeCommand) Validate() error {
+	return nil
 }
 
 // SetTransitionType selects the A/B mixer transition type used in the FadeToA, FadeToB, FadeAB and AsymmetricTransition commands.
 func (o *Oxtel) SetTransitionType(transitionType OxtelTransitionType) error {
-	msg := fmt.Sprintf("U6%02x", transitionType)
+	return o.SetTransitionTypeContext(context.Background(), transitionType)
+}
 
-	return o.sendCommand(msg)
+// SetTransitionTypeContext is like SetTransitionType but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetTransitionTypeContext(ctx context.Context, transitionType OxtelTransitionType) error {
+	return o.SendContext(ctx, SetTransitionTypeCommand{TransitionType: transitionType})
 }
 
 // SetTransitionType_AsString returns the command string used to select the A/B mixer transition type used in the
 // FadeToA, FadeToB, FadeAB and AsymmetricTransition commands.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetTransitionTypeCommand instead.
 func SetTransitionType_AsString(transitionType OxtelTransitionType) string {
-	return fmt.Sprintf("U6%02x", transitionType)
+	return SetTransitionTypeCommand{TransitionType: transitionType}.Encode()
 }
 
-// AsymmetricVFadeAB instructs the A/B mixer to V-Fade from the current input to the other through the V-Fade color (black)
-// over the specified number of fields (interlaces) or frames (progressive).
-func (o *Oxtel) AsymmetricVFadeAB(downDuration uint16, upDuration uint16) error {
-	if downDuration > 999 {
+// AsymmetricVFadeABCommand is the Command sent by AsymmetricVFadeAB.
+type AsymmetricVFadeABCommand struct {
+	DownDuration uint16
+	UpDuration   uint16
+}
+
+func (c AsymmetricVFadeABCommand) Encode() string {
+	return fmt.Sprintf("U8%03x%03x", c.DownDuration, c.UpDuration)
+}
+
+func (c AsymmetricVFadeABCommand) Validate() error {
+	if c.DownDuration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "downDuration must be less than 1000 fields/frames",
@@ -138,7 +272,7 @@ func (o *Oxtel) AsymmetricVFadeAB(downDuration uint16, upDuration uint16) error
 		}
 	}
 
-	if upDuration > 999 {
+	if c.UpDuration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "upDuration must be less than 1000 fields/frames",
@@ -146,24 +280,41 @@ func (o *Oxtel) AsymmetricVFadeAB(downDuration uint16, upDuration uint16) error
 		}
 	}
 
-	msg := fmt.Sprintf("U8%03x%03x", downDuration, upDuration)
+	return nil
+}
+
+// AsymmetricVFadeAB instructs the A/B mixer to V-Fade from the current input to the other through the V-Fade color (black)
+// over the specified number of fields (interlaces) or frames (progressive).
+func (o *Oxtel) AsymmetricVFadeAB(downDuration uint16, upDuration uint16) error {
+	return o.AsymmetricVFadeABContext(context.Background(), downDuration, upDuration)
+}
 
-	return o.sendCommand(msg)
+// AsymmetricVFadeABContext is like AsymmetricVFadeAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AsymmetricVFadeABContext(ctx context.Context, downDuration uint16, upDuration uint16) error {
+	return o.SendContext(ctx, AsymmetricVFadeABCommand{DownDuration: downDuration, UpDuration: upDuration})
 }
 
 // AsymmetricVFadeAB_AsString returns the command string used to instruct the A/B mixer to V-Fade from the current input
 // to the other through the V-Fade color (black) over the specified number of fields (interlaces) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AsymmetricVFadeABCommand instead.
 func AsymmetricVFadeAB_AsString(downDuration uint16, upDuration uint16) string {
-	return fmt.Sprintf("U8%03x%03x", downDuration, upDuration)
+	return AsymmetricVFadeABCommand{DownDuration: downDuration, UpDuration: upDuration}.Encode()
 }
 
-// SetAbsoluteMix sets the A/B mix position to the specified absolute value.
-//
-// The absolute mix value can range from 0 (A = 100%, B = 0%) to 512 (A = 0%, B = 100%).
-func (o *Oxtel) SetAbsoluteMix(mix uint16) error {
-	if mix > 512 {
+// SetAbsoluteMixCommand is the Command sent by SetAbsoluteMix.
+type SetAbsoluteMixCommand struct {
+	Mix uint16
+}
+
+func (c SetAbsoluteMixCommand) Encode() string {
+	return fmt.Sprintf("U9%03x", c.Mix)
+}
+
+func (c SetAbsoluteMixCommand) Validate() error {
+	if c.Mix > 512 {
 		return &InvalidMixError{
 			BaseError: BaseError{
 				Message: "Absolute mix must be less than 513",
@@ -171,23 +322,43 @@ func (o *Oxtel) SetAbsoluteMix(mix uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("U9%03x", mix)
+	return nil
+}
 
-	return o.sendCommand(msg)
+// SetAbsoluteMix sets the A/B mix position to the specified absolute value.
+//
+// The absolute mix value can range from 0 (A = 100%, B = 0%) to 512 (A = 0%, B = 100%).
+func (o *Oxtel) SetAbsoluteMix(mix uint16) error {
+	return o.SetAbsoluteMixContext(context.Background(), mix)
+}
+
+// SetAbsoluteMixContext is like SetAbsoluteMix but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAbsoluteMixContext(ctx context.Context, mix uint16) error {
+	return o.SendContext(ctx, SetAbsoluteMixCommand{Mix: mix})
 }
 
 // SetAbsoluteMix_AsString returns the command string used to set the A/B mix position to the specified absolute value.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAbsoluteMixCommand instead.
 func SetAbsoluteMix_AsString(mix uint16) string {
-	return fmt.Sprintf("U9%03x", mix)
+	return SetAbsoluteMixCommand{Mix: mix}.Encode()
 }
 
-// AsymmetricTransition performs an A/B mixer transition to the destination specified.
-//
-// The transitionType used is defined by the SetTransitionType command. For Cut transitions, the duration parameter is ignored.
-func (o *Oxtel) AsymmetricTransition(destination OxtelMixerInput, downDuration uint16, upDuration uint16) error {
-	if downDuration > 999 {
+// AsymmetricTransitionCommand is the Command sent by AsymmetricTransition.
+type AsymmetricTransitionCommand struct {
+	Destination  OxtelMixerInput
+	DownDuration uint16
+	UpDuration   uint16
+}
+
+func (c AsymmetricTransitionCommand) Encode() string {
+	return fmt.Sprintf("UA%x%03x%03x", c.Destination, c.DownDuration, c.UpDuration)
+}
+
+func (c AsymmetricTransitionCommand) Validate() error {
+	if c.DownDuration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "DownDuration must be less than 1000 fields/frames",
@@ -195,32 +366,50 @@ func (o *Oxtel) AsymmetricTransition(destination OxtelMixerInput, downDuration u
 		}
 	}
 
-	if upDuration > 999 {
+	if c.UpDuration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
-				Message: "PpDuration must be less than 1000 fields/frames",
+				Message: "UpDuration must be less than 1000 fields/frames",
 			},
 		}
 	}
 
-	msg := fmt.Sprintf("UA%x%03x%03x", destination, downDuration, upDuration)
+	return nil
+}
+
+// AsymmetricTransition performs an A/B mixer transition to the destination specified.
+//
+// The transitionType used is defined by the SetTransitionType command. For Cut transitions, the duration parameter is ignored.
+func (o *Oxtel) AsymmetricTransition(destination OxtelMixerInput, downDuration uint16, upDuration uint16) error {
+	return o.AsymmetricTransitionContext(context.Background(), destination, downDuration, upDuration)
+}
 
-	return o.sendCommand(msg)
+// AsymmetricTransitionContext is like AsymmetricTransition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AsymmetricTransitionContext(ctx context.Context, destination OxtelMixerInput, downDuration uint16, upDuration uint16) error {
+	return o.SendContext(ctx, AsymmetricTransitionCommand{Destination: destination, DownDuration: downDuration, UpDuration: upDuration})
 }
 
 // AsymmetricTransition_AsString returns the command string used to perform an A/B mixer transition to the destination specified.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AsymmetricTransitionCommand instead.
 func AsymmetricTransition_AsString(destination OxtelMixerInput, downDuration uint16, upDuration uint16) string {
-	return fmt.Sprintf("UA%x%03x%03x", destination, downDuration, upDuration)
+	return AsymmetricTransitionCommand{Destination: destination, DownDuration: downDuration, UpDuration: upDuration}.Encode()
 }
 
-// FadeToSpecificPosition transitions the A/B mixer to the specified position over the given number of fields (interlaced)
-// or frames (progressive).
-//
-// The position can be from 0x000 to 0x200 and all values in-between.
-func (o *Oxtel) FadeToSpecificPosition(destination uint16, duration uint16) error {
-	if destination > 512 {
+// FadeToSpecificPositionCommand is the Command sent by FadeToSpecificPosition.
+type FadeToSpecificPositionCommand struct {
+	Destination uint16
+	Duration    uint16
+}
+
+func (c FadeToSpecificPositionCommand) Encode() string {
+	return fmt.Sprintf("UC%03x%03x", c.Destination, c.Duration)
+}
+
+func (c FadeToSpecificPositionCommand) Validate() error {
+	if c.Destination > 512 {
 		return &InvalidMixError{
 			BaseError: BaseError{
 				Message: "Destination must be less than 513 fields/frames",
@@ -228,7 +417,7 @@ func (o *Oxtel) FadeToSpecificPosition(destination uint16, duration uint16) erro
 		}
 	}
 
-	if duration > 999 {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000 fields/frames",
@@ -236,17 +425,48 @@ func (o *Oxtel) FadeToSpecificPosition(destination uint16, duration uint16) erro
 		}
 	}
 
-	msg := fmt.Sprintf("UC%03x%03x", destination, duration)
+	return nil
+}
+
+// FadeToSpecificPosition transitions the A/B mixer to the specified position over the given number of fields (interlaced)
+// or frames (progressive).
+//
+// The position can be from 0x000 to 0x200 and all values in-between.
+func (o *Oxtel) FadeToSpecificPosition(destination uint16, duration uint16) error {
+	return o.FadeToSpecificPositionContext(context.Background(), destination, duration)
+}
 
-	return o.sendCommand(msg)
+// FadeToSpecificPositionContext is like FadeToSpecificPosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) FadeToSpecificPositionContext(ctx context.Context, destination uint16, duration uint16) error {
+	return o.SendContext(ctx, FadeToSpecificPositionCommand{Destination: destination, Duration: duration})
 }
 
 // FadeToSpecificPosition_AsString returns the command string used to transition the A/B mixer to the specified position
 // over the given number of fields (interlaced) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a FadeToSpecificPositionCommand instead.
 func FadeToSpecificPosition_AsString(destination uint16, duration uint16) string {
-	return fmt.Sprintf("UC%03x%03x", destination, duration)
+	return FadeToSpecificPositionCommand{Destination: destination, Duration: duration}.Encode()
+}
+
+// SelectMixerInputCommand is the Command sent by SelectMixerInput. A nil ARC resets the aspect ratio conversion to the default.
+type SelectMixerInputCommand struct {
+	Input  OxtelMixerInput
+	Source OxtelVideoSource
+	ARC    *OxtelARC
+}
+
+func (c SelectMixerInputCommand) Encode() string {
+	if c.ARC == nil {
+		return fmt.Sprintf("UE %x %x", c.Input, c.Source)
+	}
+	return fmt.Sprintf("UE %x %x %x", c.Input, c.Source, *c.ARC)
+}
+
+func (c SelectMixerInputCommand) Validate() error {
+	return nil
 }
 
 // SelectMixerInput routes video sources into the A and B input of the A/B mixer.
@@ -260,35 +480,50 @@ func FadeToSpecificPosition_AsString(destination uint16, duration uint16) string
 // This ARC mode overrides all other settings (including ARC inferred from AFD and specified on the player at clip attach time).
 // The is currently no way of querying the ARC mode.
 func (o *Oxtel) SelectMixerInput(input OxtelMixerInput, source OxtelVideoSource, arc *OxtelARC) error {
-	msg := ""
-	if arc == nil {
-		msg = fmt.Sprintf("UE %x %x", input, source)
-	} else {
-		msg = fmt.Sprintf("UE %x %x %x", input, source, *arc)
-	}
+	return o.SelectMixerInputContext(context.Background(), input, source, arc)
+}
 
-	return o.sendCommand(msg)
+// SelectMixerInputContext is like SelectMixerInput but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SelectMixerInputContext(ctx context.Context, input OxtelMixerInput, source OxtelVideoSource, arc *OxtelARC) error {
+	return o.SendContext(ctx, SelectMixerInputCommand{Input: input, Source: source, ARC: arc})
 }
 
 // SelectMixerInput_AsString returns the command string used to route video sources into the A and B input of the A/B mixer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SelectMixerInputCommand instead.
 func SelectMixerInput_AsString(input OxtelMixerInput, source OxtelVideoSource, arc *OxtelARC) string {
-	msg := ""
-	if arc == nil {
-		msg = fmt.Sprintf("UE %x %x", input, source)
-	} else {
-		msg = fmt.Sprintf("UE %x %x %x", input, source, *arc)
-	}
+	return SelectMixerInputCommand{Input: input, Source: source, ARC: arc}.Encode()
+}
+
+// EnquireMixerInputCommand is the Enquiry sent by EnquireMixerInput.
+type EnquireMixerInputCommand struct {
+	Input OxtelMixerInput
+}
 
-	return msg
+func (c EnquireMixerInputCommand) Encode() string {
+	return fmt.Sprintf("UE %x", c.Input)
+}
+
+func (c EnquireMixerInputCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireMixerInputCommand) ResponsePrefix() string {
+	return "UE"
 }
 
 // EnquireMixerInput queries for the current video source for the specified mixer input.
 //
 // Response is a MixerInputResponse.
 func (o *Oxtel) EnquireMixerInput(input OxtelMixerInput) (MixerInputResponse, error) {
-	val, err := o.sendCommandExpectResponse("UE", fmt.Sprintf(" %x", input))
+	return o.EnquireMixerInputContext(context.Background(), input)
+}
+
+// EnquireMixerInputContext is like EnquireMixerInput but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireMixerInputContext(ctx context.Context, input OxtelMixerInput) (MixerInputResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireMixerInputCommand{Input: input})
 	if err != nil {
 		return MixerInputResponse{}, err
 	}
@@ -311,15 +546,37 @@ func (o *Oxtel) EnquireMixerInput(input OxtelMixerInput) (MixerInputResponse, er
 // EnquireMixerInput_AsString returns the command string used to query for the current video source for the specified mixer input.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireMixerInputCommand instead.
 func EnquireMixerInput_AsString(input OxtelMixerInput) string {
-	return fmt.Sprintf("UE %x", input)
+	return EnquireMixerInputCommand{Input: input}.Encode()
+}
+
+// EnquireMixModeCommand is the Enquiry sent by EnquireMixMode.
+type EnquireMixModeCommand struct{}
+
+func (c EnquireMixModeCommand) Encode() string {
+	return "Ua"
+}
+
+func (c EnquireMixModeCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireMixModeCommand) ResponsePrefix() string {
+	return "Ua"
 }
 
 // EnquireMixMode returns the status of the A/B mixer parameters.
 //
 // Response is a MixModeResponse.
 func (o *Oxtel) EnquireMixMode() (MixModeResponse, error) {
-	val, err := o.sendCommandExpectResponse("Ua", "")
+	return o.EnquireMixModeContext(context.Background())
+}
+
+// EnquireMixModeContext is like EnquireMixMode but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireMixModeContext(ctx context.Context) (MixModeResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireMixModeCommand{})
 	if err != nil {
 		return MixModeResponse{}, err
 	}
@@ -358,28 +615,73 @@ func (o *Oxtel) EnquireMixMode() (MixModeResponse, error) {
 // EnquireMixMode_AsString returns the command string used to return the status of the A/B mixer parameters.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireMixModeCommand instead.
 func EnquireMixMode_AsString() string {
-	return "Ua"
+	return EnquireMixModeCommand{}.Encode()
+}
+
+// SetColorGeneratorColorCommand is the Command sent by SetColorGeneratorColor.
+type SetColorGeneratorColorCommand struct {
+	Unit  uint8
+	Red   uint8
+	Green uint8
+	Blue  uint8
+}
+
+func (c SetColorGeneratorColorCommand) Encode() string {
+	return fmt.Sprintf("UZ%x%02x%02x%02x", c.Unit, c.Red, c.Green, c.Blue)
+}
+
+func (c SetColorGeneratorColorCommand) Validate() error {
+	return nil
 }
 
 // SetColorGeneratorColor sets the color of the specified Color Generator unit to the specified RGB value.
 func (o *Oxtel) SetColorGeneratorColor(colorGeneratorUnit uint8, red uint8, green uint8, blue uint8) error {
-	msg := fmt.Sprintf("UZ%x%02x%02x%02x", colorGeneratorUnit, red, green, blue)
+	return o.SetColorGeneratorColorContext(context.Background(), colorGeneratorUnit, red, green, blue)
+}
 
-	return o.sendCommand(msg)
+// SetColorGeneratorColorContext is like SetColorGeneratorColor but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetColorGeneratorColorContext(ctx context.Context, colorGeneratorUnit uint8, red uint8, green uint8, blue uint8) error {
+	return o.SendContext(ctx, SetColorGeneratorColorCommand{Unit: colorGeneratorUnit, Red: red, Green: green, Blue: blue})
 }
 
 // SetColorGeneratorColor_AsString returns the string used to set the color of the specified Color Generator unit to
 // the specified RGB value.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetColorGeneratorColorCommand instead.
 func SetColorGeneratorColor_AsString(colorGeneratorUnit uint8, red uint8, green uint8, blue uint8) string {
-	return fmt.Sprintf("UZ%x%02x%02x%02x", colorGeneratorUnit, red, green, blue)
+	return SetColorGeneratorColorCommand{Unit: colorGeneratorUnit, Red: red, Green: green, Blue: blue}.Encode()
+}
+
+// EnquireColorGeneratorColorCommand is the Enquiry sent by EnquireColorGeneratorColor.
+type EnquireColorGeneratorColorCommand struct {
+	Unit uint8
+}
+
+func (c EnquireColorGeneratorColorCommand) Encode() string {
+	return fmt.Sprintf("UZ%d", c.Unit)
+}
+
+func (c EnquireColorGeneratorColorCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireColorGeneratorColorCommand) ResponsePrefix() string {
+	return "UZ"
 }
 
 // EnquireColorGeneratorColor queries the color generator color from the specified Color Generator unit.
 //
 // Response is a ColorGeneratorResponse.
 func (o *Oxtel) EnquireColorGeneratorColor(colorGeneratorUnit uint8) (ColorGeneratorResponse, error) {
-	val, err := o.sendCommandExpectResponse("UZ", fmt.Sprintf("%d", colorGeneratorUnit))
+	return o.EnquireColorGeneratorColorContext(context.Background(), colorGeneratorUnit)
+}
+
+// EnquireColorGeneratorColorContext is like EnquireColorGeneratorColor but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireColorGeneratorColorContext(ctx context.Context, colorGeneratorUnit uint8) (ColorGeneratorResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireColorGeneratorColorCommand{Unit: colorGeneratorUnit})
 	if err != nil {
 		return ColorGeneratorResponse{}, err
 	}
@@ -417,29 +719,74 @@ func (o *Oxtel) EnquireColorGeneratorColor(colorGeneratorUnit uint8) (ColorGener
 // specified Color Generator unit.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireColorGeneratorColorCommand instead.
 func EnquireColorGeneratorColor_AsString(colorGeneratorUnit uint8) string {
-	return fmt.Sprintf("UZ%d", colorGeneratorUnit)
+	return EnquireColorGeneratorColorCommand{Unit: colorGeneratorUnit}.Encode()
+}
+
+// EnableVideoTalliesCommand is the Command sent by EnableVideoTallies.
+type EnableVideoTalliesCommand struct {
+	Enable bool
+}
+
+func (c EnableVideoTalliesCommand) Encode() string {
+	return fmt.Sprintf("Y6%x", boolToInt(c.Enable))
+}
+
+func (c EnableVideoTalliesCommand) Validate() error {
+	return nil
 }
 
 // EnableVideoTallies enables/disables the Video Tally, Image Video Tally, Image Preload Tally, and Keyer Position Tally on
 // the connection on which the command was received.
 func (o *Oxtel) EnableVideoTallies(enable bool) error {
-	msg := fmt.Sprintf("Y6%x", boolToInt(enable))
+	return o.EnableVideoTalliesContext(context.Background(), enable)
+}
 
-	return o.sendCommand(msg)
+// EnableVideoTalliesContext is like EnableVideoTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableVideoTalliesContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, EnableVideoTalliesCommand{Enable: enable})
 }
 
 // EnableVideoTallies_AsString returns the command string used to  enable/disable the Video Tally, Image Video Tally,
 // Image Preload Tally, and Keyer Position Tally on the connection on which the command was received.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableVideoTalliesCommand instead.
 func EnableVideoTallies_AsString(enable bool) string {
-	return fmt.Sprintf("Y6%x", boolToInt(enable))
+	return EnableVideoTalliesCommand{Enable: enable}.Encode()
+}
+
+// EnquireVideoTalliesCommand is the Enquiry sent by EnquireVideoTallies.
+type EnquireVideoTalliesCommand struct{}
+
+func (c EnquireVideoTalliesCommand) Encode() string {
+	return "Y6"
+}
+
+func (c EnquireVideoTalliesCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireVideoTalliesCommand) ResponsePrefix() string {
+	return "Y6"
+}
+
+// The response is a single flag, where a VideoTally carries the mixer and keyer states.
+func (c EnquireVideoTalliesCommand) matchesResponse(data string) bool {
+	return len(data) == 1
 }
 
 // EnquireVideoTallies queries the state of the Video Tallies set using the EnableVideoTallies command.
 //
 // Response is a boolean representing if video tallies are enabled.
 func (o *Oxtel) EnquireVideoTallies() (bool, error) {
-	val, err := o.sendCommandExpectResponse("Y6", "")
+	return o.EnquireVideoTalliesContext(context.Background())
+}
+
+// EnquireVideoTalliesContext is like EnquireVideoTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireVideoTalliesContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquireVideoTalliesCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -452,6 +799,8 @@ func (o *Oxtel) EnquireVideoTallies() (bool, error) {
 // the EnableVideoTallies command.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireVideoTalliesCommand instead.
 func EnquireVideoTallies_AsString() string {
-	return "Y6"
+	return EnquireVideoTalliesCommand{}.Encode()
 }
@@ -1,20 +1,53 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
+// StartAnimationCommand is the Command sent by StartAnimation.
+type StartAnimationCommand struct {
+	Layer OxtelLayer
+}
+
+func (c StartAnimationCommand) Encode() string {
+	return fmt.Sprintf("S0%x", c.Layer)
+}
+
+func (c StartAnimationCommand) Validate() error {
+	return nil
+}
+
 // StartAnimation starts/resumes the animation of the specified layer.
 func (o *Oxtel) StartAnimation(layer OxtelLayer) error {
-	msg := fmt.Sprintf("S0%x", layer)
+	return o.StartAnimationContext(context.Background(), layer)
+}
 
-	return o.sendCommand(msg)
+// StartAnimationContext is like StartAnimation but uses ctx for cancellation and deadlines.
+func (o *Oxtel) StartAnimationContext(ctx context.Context, layer OxtelLayer) error {
+	return o.SendContext(ctx, StartAnimationCommand{Layer: layer})
 }
 
 // StartAnimation_AsString returns the command string used to start/resume the animation of the specified layer.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a StartAnimationCommand instead.
 func StartAnimation_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("S0%x", layer)
+	return StartAnimationCommand{Layer: layer}.Encode()
+}
+
+// StopAnimationCommand is the Command sent by StopAnimation.
+type StopAnimationCommand struct {
+	Layer     OxtelLayer
+	Immediate bool
+}
+
+func (c StopAnimationCommand) Encode() string {
+	return fmt.Sprintf("S1%x%x", c.Layer, boolToInt(c.Immediate))
+}
+
+func (c StopAnimationCommand) Validate() error {
+	return nil
 }
 
 // StopAnimation stops the animation of the specified player.
@@ -22,16 +55,35 @@ func StartAnimation_AsString(layer OxtelLayer) string {
 // If immediate is true, the animation halts immediately at the current frame. If it is set to false, the animation
 // completes before stopping.
 func (o *Oxtel) StopAnimation(layer OxtelLayer, immediate bool) error {
-	msg := fmt.Sprintf("S1%x%x", layer, boolToInt(immediate))
+	return o.StopAnimationContext(context.Background(), layer, immediate)
+}
 
-	return o.sendCommand(msg)
+// StopAnimationContext is like StopAnimation but uses ctx for cancellation and deadlines.
+func (o *Oxtel) StopAnimationContext(ctx context.Context, layer OxtelLayer, immediate bool) error {
+	return o.SendContext(ctx, StopAnimationCommand{Layer: layer, Immediate: immediate})
 }
 
 // StopAnimation_AsString returns the command string used to stop the animation of the specified player.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a StopAnimationCommand instead.
 func StopAnimation_AsString(layer OxtelLayer, immediate bool) string {
-	return fmt.Sprintf("S1%x%x", layer, boolToInt(immediate))
+	return StopAnimationCommand{Layer: layer, Immediate: immediate}.Encode()
+}
+
+// SelectionAnimationFrameCommand is the Command sent by SelectionAnimationFrame.
+type SelectionAnimationFrameCommand struct {
+	Layer OxtelLayer
+	Frame uint32
+}
+
+func (c SelectionAnimationFrameCommand) Encode() string {
+	return fmt.Sprintf("S2%x%04x", c.Layer, c.Frame)
+}
+
+func (c SelectionAnimationFrameCommand) Validate() error {
+	return nil
 }
 
 // SelectionAnimationFrame sets the template animation frame on the specified layer.
@@ -42,16 +94,34 @@ func StopAnimation_AsString(layer OxtelLayer, immediate bool) string {
 // For templates authored completely in JavaScript, it is the developer's responsibility to implement this functionality
 // by overriding necessary JavaScript functions.
 func (o *Oxtel) SelectionAnimationFrame(layer OxtelLayer, frame uint32) error {
-	msg := fmt.Sprintf("S2%x%04x", layer, frame)
+	return o.SelectionAnimationFrameContext(context.Background(), layer, frame)
+}
 
-	return o.sendCommand(msg)
+// SelectionAnimationFrameContext is like SelectionAnimationFrame but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SelectionAnimationFrameContext(ctx context.Context, layer OxtelLayer, frame uint32) error {
+	return o.SendContext(ctx, SelectionAnimationFrameCommand{Layer: layer, Frame: frame})
 }
 
 // SelectionAnimationFrame_AsString returns the command string used to set the template animation frame on the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SelectionAnimationFrameCommand instead.
 func SelectionAnimationFrame_AsString(layer OxtelLayer, frame uint32) string {
-	return fmt.Sprintf("S2%x%04x", layer, frame)
+	return SelectionAnimationFrameCommand{Layer: layer, Frame: frame}.Encode()
+}
+
+// RestartAnimationCommand is the Command sent by RestartAnimation.
+type RestartAnimationCommand struct {
+	Layer OxtelLayer
+}
+
+func (c RestartAnimationCommand) Encode() string {
+	return fmt.Sprintf("S4%x", c.Layer)
+}
+
+func (c RestartAnimationCommand) Validate() error {
+	return nil
 }
 
 // RestartAnimation restarts the template animation from the beginning on the specified layer.
@@ -62,17 +132,35 @@ func SelectionAnimationFrame_AsString(layer OxtelLayer, frame uint32) string {
 // For templates authored completely in JavaScript, it is the developer's responsibility to implement this functionality
 // by overriding necessary JavaScript functions.
 func (o *Oxtel) RestartAnimation(layer OxtelLayer) error {
-	msg := fmt.Sprintf("S4%x", layer)
+	return o.RestartAnimationContext(context.Background(), layer)
+}
 
-	return o.sendCommand(msg)
+// RestartAnimationContext is like RestartAnimation but uses ctx for cancellation and deadlines.
+func (o *Oxtel) RestartAnimationContext(ctx context.Context, layer OxtelLayer) error {
+	return o.SendContext(ctx, RestartAnimationCommand{Layer: layer})
 }
 
 // RestartAnimation_AsString returns the command string used to restart the template animation from the beginning on
 // the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a RestartAnimationCommand instead.
 func RestartAnimation_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("S4%x", layer)
+	return RestartAnimationCommand{Layer: layer}.Encode()
+}
+
+// EnablePlayStateTallyCommand is the Command sent by EnablePlayStateTally.
+type EnablePlayStateTallyCommand struct {
+	Enable bool
+}
+
+func (c EnablePlayStateTallyCommand) Encode() string {
+	return fmt.Sprintf("YS%x", boolToInt(c.Enable))
+}
+
+func (c EnablePlayStateTallyCommand) Validate() error {
+	return nil
 }
 
 // EnablePlayStateTally enables/disables the template play state tally for the connection on which the command was received.
@@ -81,9 +169,12 @@ func RestartAnimation_AsString(layer OxtelLayer) string {
 //
 // An unsolicited response will be transmitted as a OxtelPlayStateTally.
 func (o *Oxtel) EnablePlayStateTally(enable bool) error {
-	msg := fmt.Sprintf("YS%x", boolToInt(enable))
+	return o.EnablePlayStateTallyContext(context.Background(), enable)
+}
 
-	return o.sendCommand(msg)
+// EnablePlayStateTallyContext is like EnablePlayStateTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnablePlayStateTallyContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, EnablePlayStateTallyCommand{Enable: enable})
 }
 
 // EnablePlayStateTally_AsString returns the command used to enable/disable the template play state tally for the
@@ -91,15 +182,42 @@ func (o *Oxtel) EnablePlayStateTally(enable bool) error {
 //	connection on which the command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnablePlayStateTallyCommand instead.
 func EnablePlayStateTally_AsString(enable bool) string {
-	return fmt.Sprintf("YS%x", boolToInt(enable))
+	return EnablePlayStateTallyCommand{Enable: enable}.Encode()
+}
+
+// EnquirePlayStateTallyCommand is the Enquiry sent by EnquirePlayStateTally.
+type EnquirePlayStateTallyCommand struct{}
+
+func (c EnquirePlayStateTallyCommand) Encode() string {
+	return "YS"
+}
+
+func (c EnquirePlayStateTallyCommand) Validate() error {
+	return nil
+}
+
+func (c EnquirePlayStateTallyCommand) ResponsePrefix() string {
+	return "YS"
+}
+
+// The response is a single flag, where a PlayStateTally carries a layer and its state.
+func (c EnquirePlayStateTallyCommand) matchesResponse(data string) bool {
+	return len(data) == 1
 }
 
 // EnquirePlayStateTally queries the enable/disable state of the Play State tally.
 //
 // Response is a boolean representing if play state tally is enabled.
 func (o *Oxtel) EnquirePlayStateTally() (bool, error) {
-	val, err := o.sendCommandExpectResponse("YS", "")
+	return o.EnquirePlayStateTallyContext(context.Background())
+}
+
+// EnquirePlayStateTallyContext is like EnquirePlayStateTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquirePlayStateTallyContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquirePlayStateTallyCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -110,6 +228,8 @@ func (o *Oxtel) EnquirePlayStateTally() (bool, error) {
 // EnquirePlayStateTally_AsString returns the command string used to query the enable/disable state of the Play State tally.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquirePlayStateTallyCommand instead.
 func EnquirePlayStateTally_AsString() string {
-	return "YS"
+	return EnquirePlayStateTallyCommand{}.Encode()
 }
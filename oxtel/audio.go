@@ -1,10 +1,25 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
+// AudioPopSuppressionCommand is the Command sent by AudioPopSuppression.
+type AudioPopSuppressionCommand struct {
+	Source OxtelAudioSource
+	Enable bool
+}
+
+func (c AudioPopSuppressionCommand) Encode() string {
+	return fmt.Sprintf("hDP%01x%01x", c.Source, boolToInt(c.Enable))
+}
+
+func (c AudioPopSuppressionCommand) Validate() error {
+	return nil
+}
+
 // AudioPopSuppression disables pop suppression for two frames. This command is meant to be used in conjunction with an
 // audio cut command to prevent audio dropouts when the clips are contiguous and played back-to-back. I.E. loop record or
 // situations like a fade to A then Cut to A where the cut is to the already selected source where pop suppression
@@ -16,14 +31,35 @@ import (
 // Note that because this is a one-shot that resets to re-enable pop suppression immediately after two fields,
 // sending a second AudioPopSuppression with enable = false is not necessary.
 func (o *Oxtel) AudioPopSuppression(source OxtelAudioSource, enable bool) error {
-	return o.sendCommand(fmt.Sprintf("hDP%01x%01x", source, boolToInt(enable)))
+	return o.AudioPopSuppressionContext(context.Background(), source, enable)
+}
+
+// AudioPopSuppressionContext is like AudioPopSuppression but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AudioPopSuppressionContext(ctx context.Context, source OxtelAudioSource, enable bool) error {
+	return o.SendContext(ctx, AudioPopSuppressionCommand{Source: source, Enable: enable})
 }
 
 // AudioPopSuppression_AsString returns the command string used to enable/disable pop suppression for two frames.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AudioPopSuppressionCommand instead.
 func AudioPopSuppression_AsString(source OxtelAudioSource, enable bool) string {
-	return fmt.Sprintf("hDP%01x%01x", source, boolToInt(enable))
+	return AudioPopSuppressionCommand{Source: source, Enable: enable}.Encode()
+}
+
+// PauseResumeDolbyEncoderCommand is the Command sent by PauseResumeDolbyEncoder.
+type PauseResumeDolbyEncoderCommand struct {
+	Input OxtelMixerInput
+	Pause bool
+}
+
+func (c PauseResumeDolbyEncoderCommand) Encode() string {
+	return fmt.Sprintf("hDE%01x%01x", c.Input, boolToInt(c.Pause))
+}
+
+func (c PauseResumeDolbyEncoderCommand) Validate() error {
+	return nil
 }
 
 // PauseResumeDolbyEncoder pauses/resumes an active Dolby Encoder. Pausing the Dolby Encoder will pass through the PCM audio.
@@ -31,20 +67,35 @@ func AudioPopSuppression_AsString(source OxtelAudioSource, enable bool) string {
 //
 // Encoding will resume when it receives the resume command with no latencies.
 func (o *Oxtel) PauseResumeDolbyEncoder(input OxtelMixerInput, pause bool) error {
-	return o.sendCommand(fmt.Sprintf("hDE%01x%01x", input, boolToInt(pause)))
+	return o.PauseResumeDolbyEncoderContext(context.Background(), input, pause)
+}
+
+// PauseResumeDolbyEncoderContext is like PauseResumeDolbyEncoder but uses ctx for cancellation and deadlines.
+func (o *Oxtel) PauseResumeDolbyEncoderContext(ctx context.Context, input OxtelMixerInput, pause bool) error {
+	return o.SendContext(ctx, PauseResumeDolbyEncoderCommand{Input: input, Pause: pause})
 }
 
 // PauseResumeDolbyEncoder_AsString returns the command string that is used to pause/resume an active Dolby Encoder.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a PauseResumeDolbyEncoderCommand instead.
 func PauseResumeDolbyEncoder_AsString(input OxtelMixerInput, pause bool) string {
-	return fmt.Sprintf("hDE%01x%01x", input, boolToInt(pause))
+	return PauseResumeDolbyEncoderCommand{Input: input, Pause: pause}.Encode()
 }
 
-// SetDolbyEncoderProfile selects one of the predefined Dolby Encoder Profiles.
-// There can be up to four Profiles defined in the SystemManager application.
-func (o *Oxtel) SetDolbyEncoderProfile(input OxtelMixerInput, profile uint8) error {
-	if profile > 4 {
+// SetDolbyEncoderProfileCommand is the Command sent by SetDolbyEncoderProfile.
+type SetDolbyEncoderProfileCommand struct {
+	Input   OxtelMixerInput
+	Profile uint8
+}
+
+func (c SetDolbyEncoderProfileCommand) Encode() string {
+	return fmt.Sprintf("hDA%01x%01x", c.Input, c.Profile)
+}
+
+func (c SetDolbyEncoderProfileCommand) Validate() error {
+	if c.Profile > 4 {
 		return &InvalidDolbyProfileError{
 			BaseError: BaseError{
 				Message: "Profile must be less than 5",
@@ -52,21 +103,56 @@ func (o *Oxtel) SetDolbyEncoderProfile(input OxtelMixerInput, profile uint8) err
 		}
 	}
 
-	return o.sendCommand(fmt.Sprintf("hDA%01x%01x", input, profile))
+	return nil
+}
+
+// SetDolbyEncoderProfile selects one of the predefined Dolby Encoder Profiles.
+// There can be up to four Profiles defined in the SystemManager application.
+func (o *Oxtel) SetDolbyEncoderProfile(input OxtelMixerInput, profile uint8) error {
+	return o.SetDolbyEncoderProfileContext(context.Background(), input, profile)
+}
+
+// SetDolbyEncoderProfileContext is like SetDolbyEncoderProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetDolbyEncoderProfileContext(ctx context.Context, input OxtelMixerInput, profile uint8) error {
+	return o.SendContext(ctx, SetDolbyEncoderProfileCommand{Input: input, Profile: profile})
 }
 
 // SetDolbyEncoderProfile_AsString returns the command string used to select one of the predefined Dolby Encoder Profiles.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetDolbyEncoderProfileCommand instead.
 func SetDolbyEncoderProfile_AsString(input OxtelMixerInput, profile uint8) string {
-	return fmt.Sprintf("hDA%01x%01x", input, profile)
+	return SetDolbyEncoderProfileCommand{Input: input, Profile: profile}.Encode()
+}
+
+// EnquireDolbyEncoderProfileCommand is the Enquiry sent by EnquireDolbyEncoderProfile.
+type EnquireDolbyEncoderProfileCommand struct {
+	Input OxtelMixerInput
+}
+
+func (c EnquireDolbyEncoderProfileCommand) Encode() string {
+	return fmt.Sprintf("hDA%01x", c.Input)
+}
+
+func (c EnquireDolbyEncoderProfileCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireDolbyEncoderProfileCommand) ResponsePrefix() string {
+	return "hDA"
 }
 
 // EnquireDolbyEncoderProfile queries Dolby Encoder Profile used on the input.
 //
 // Response is an int.
 func (o *Oxtel) EnquireDolbyEncoderProfile(input OxtelMixerInput) (uint64, error) {
-	val, err := o.sendCommandExpectResponse("hDA", fmt.Sprintf("%01x", input))
+	return o.EnquireDolbyEncoderProfileContext(context.Background(), input)
+}
+
+// EnquireDolbyEncoderProfileContext is like EnquireDolbyEncoderProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireDolbyEncoderProfileContext(ctx context.Context, input OxtelMixerInput) (uint64, error) {
+	val, err := o.EnquireContext(ctx, EnquireDolbyEncoderProfileCommand{Input: input})
 	if err != nil {
 		return 0, err
 	}
@@ -77,6 +163,8 @@ func (o *Oxtel) EnquireDolbyEncoderProfile(input OxtelMixerInput) (uint64, error
 // EnquireDolbyEncoderProfile_AsString returns the command string used to query Dolby Encoder Profile used on the input.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireDolbyEncoderProfileCommand instead.
 func EnquireDolbyEncoderProfile_AsString(input OxtelMixerInput) string {
-	return fmt.Sprintf("hDA%01x", input)
+	return EnquireDolbyEncoderProfileCommand{Input: input}.Encode()
 }
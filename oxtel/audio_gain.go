@@ -0,0 +1,154 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MuteGainDB and MaxGainDB are the lowest and highest gains accepted by SetAudioGain. MuteGainDB mutes the channels.
+const (
+	MuteGainDB = -100.0
+	MaxGainDB  = 30.0
+)
+
+// audioOutputs is every OxtelAudioOutput, in the order a GainMatrix reads them.
+var audioOutputs = []OxtelAudioOutput{
+	OXTEL_AUDIO_OUTPUT_PLAYER_A,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_1,
+	OXTEL_AUDIO_OUTPUT_PLAYER_B,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_2,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_3,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_4,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_5,
+	OXTEL_AUDIO_OUTPUT_EXT_IN_6,
+	OXTEL_AUDIO_OUTPUT_VOICE_OVER,
+	OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT,
+}
+
+// SetAudioGainDB is like SetAudioGain but takes the gain in dB. The engine sets gain in whole dB, so gainDB is rounded
+// to the nearest one. Negative infinity mutes the channels, as does MuteGainDB.
+//
+// An InvalidGainError is returned if gainDB is NaN or outside MuteGainDB to MaxGainDB.
+func (o *Oxtel) SetAudioGainDB(output OxtelAudioOutput, channelMask ChannelMask, gainDB float64) error {
+	return o.SetAudioGainDBContext(context.Background(), output, channelMask, gainDB)
+}
+
+// SetAudioGainDBContext is like SetAudioGainDB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioGainDBContext(ctx context.Context, output OxtelAudioOutput, channelMask ChannelMask, gainDB float64) error {
+	gain, err := gainFromDB(gainDB)
+	if err != nil {
+		return err
+	}
+	return o.SetAudioGainContext(ctx, output, channelMask, &gain)
+}
+
+// EnquireAudioGainDB is like EnquireAudioGain but returns the gain in dB. As for EnquireAudioGain, the gain of a mask
+// of several channels is only correct if they are all set the same.
+func (o *Oxtel) EnquireAudioGainDB(output OxtelAudioOutput, channelMask ChannelMask) (float64, error) {
+	return o.EnquireAudioGainDBContext(context.Background(), output, channelMask)
+}
+
+// EnquireAudioGainDBContext is like EnquireAudioGainDB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireAudioGainDBContext(ctx context.Context, output OxtelAudioOutput, channelMask ChannelMask) (float64, error) {
+	val, err := o.EnquireAudioGainContext(ctx, output, channelMask)
+	if err != nil {
+		return 0, err
+	}
+	return float64(val.Gain), nil
+}
+
+func gainFromDB(gainDB float64) (int8, error) {
+	if math.IsInf(gainDB, -1) {
+		return int8(MuteGainDB), nil
+	}
+	if math.IsNaN(gainDB) || gainDB < MuteGainDB || gainDB > MaxGainDB {
+		return 0, &InvalidGainError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Gain %g dB must be between %g and +%g", gainDB, MuteGainDB, MaxGainDB),
+			},
+		}
+	}
+	return int8(math.Round(gainDB)), nil
+}
+
+// GainMatrix is the gain in dB of every channel of every OxtelAudioOutput, as read by EnquireGainMatrix. Gains[output]
+// holds channels 1 to 16 in order.
+type GainMatrix struct {
+	Gains map[OxtelAudioOutput][16]int8
+}
+
+// GainDB returns the gain of a channel of an output, and whether the matrix holds it.
+func (m GainMatrix) GainDB(output OxtelAudioOutput, channel int) (float64, bool) {
+	gains, ok := m.Gains[output]
+	if !ok || channel < 1 || channel > 16 {
+		return 0, false
+	}
+	return float64(gains[channel-1]), true
+}
+
+// EnquireGainMatrix reads the gain of each of the 16 channels of every OxtelAudioOutput, one channel at a time, so that
+// it can be restored later with SetGainMatrix.
+func (o *Oxtel) EnquireGainMatrix() (GainMatrix, error) {
+	return o.EnquireGainMatrixContext(context.Background())
+}
+
+// EnquireGainMatrixContext is like EnquireGainMatrix but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireGainMatrixContext(ctx context.Context) (GainMatrix, error) {
+	m := GainMatrix{Gains: make(map[OxtelAudioOutput][16]int8, len(audioOutputs))}
+	for _, output := range audioOutputs {
+		var gains [16]int8
+		for ch := 1; ch <= 16; ch++ {
+			val, err := o.EnquireAudioGainContext(ctx, output, 1<<(ch-1))
+			if err != nil {
+				return GainMatrix{}, err
+			}
+			gains[ch-1] = val.Gain
+		}
+		m.Gains[output] = gains
+	}
+	return m, nil
+}
+
+// SetGainMatrix sets the gains held by a GainMatrix. Channels of an output that share a gain are set by a single
+// SetAudioGain, and every command is sent in one write.
+func (o *Oxtel) SetGainMatrix(m GainMatrix) error {
+	return o.SetGainMatrixContext(context.Background(), m)
+}
+
+// SetGainMatrixContext is like SetGainMatrix but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetGainMatrixContext(ctx context.Context, m GainMatrix) error {
+	outputs := make([]OxtelAudioOutput, 0, len(m.Gains))
+	for output := range m.Gains {
+		outputs = append(outputs, output)
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i] < outputs[j] })
+
+	var cmds []Command
+	for _, output := range outputs {
+		masks := make(map[int8]ChannelMask)
+		for i, gain := range m.Gains[output] {
+			masks[gain] |= 1 << i
+		}
+
+		gains := make([]int8, 0, len(masks))
+		for gain := range masks {
+			gains = append(gains, gain)
+		}
+		sort.Slice(gains, func(i, j int) bool { return gains[i] < gains[j] })
+
+		for _, gain := range gains {
+			gain := gain
+			cmd := SetAudioGainCommand{Output: output, ChannelMask: masks[gain], Gain: &gain}
+			if err := cmd.Validate(); err != nil {
+				return err
+			}
+			cmds = append(cmds, cmd)
+		}
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return o.SendBatchContext(ctx, cmds...)
+}
@@ -0,0 +1,61 @@
+package oxtel
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAudioGainDB(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	mask, _ := ChannelRange(3, 4)
+	if err := client.SetAudioGainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, mask, -6.4); err != nil {
+		t.Fatal(err)
+	}
+	if gain, err := client.EnquireAudioGainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, mask); err != nil || gain != -6 {
+		t.Errorf("gain is %g, %v", gain, err)
+	}
+	if err := client.SetAudioGainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, mask, math.Inf(-1)); err != nil {
+		t.Fatal(err)
+	}
+	if gain, err := client.EnquireAudioGainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, mask); err != nil || gain != MuteGainDB {
+		t.Errorf("muted gain is %g, %v", gain, err)
+	}
+	for _, bad := range []float64{30.5, -101, math.NaN()} {
+		if err := client.SetAudioGainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, mask, bad); err == nil {
+			t.Errorf("expected %g dB to be rejected", bad)
+		}
+	}
+
+	if err := client.SetAudioGainDB(OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT, AllChannels, 3); err != nil {
+		t.Fatal(err)
+	}
+	matrix, err := client.EnquireGainMatrix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gain, ok := matrix.GainDB(OXTEL_AUDIO_OUTPUT_EXT_IN_1, 3); !ok || gain != MuteGainDB {
+		t.Errorf("matrix holds %g for channel 3 of Ext In 1", gain)
+	}
+	if gain, ok := matrix.GainDB(OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT, 16); !ok || gain != 3 {
+		t.Errorf("matrix holds %g for channel 16 of the mixer output", gain)
+	}
+
+	if err := client.SetAudioGainDB(OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT, AllChannels, -20); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetGainMatrix(matrix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireAudioGain(OXTEL_AUDIO_OUTPUT_PLAYER_A, MakeTwoChannelMask()); err != nil {
+		t.Fatal(err)
+	}
+	for ch := 1; ch <= 16; ch++ {
+		if gain := srv.AudioGain(uint8(OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT), ch); gain != 3 {
+			t.Errorf("channel %d of the mixer output is %d after restoring the matrix", ch, gain)
+		}
+	}
+	if gain := srv.AudioGain(uint8(OXTEL_AUDIO_OUTPUT_EXT_IN_1), 4); gain != -100 {
+		t.Errorf("channel 4 of Ext In 1 is %d after restoring the matrix", gain)
+	}
+}
@@ -1,10 +1,35 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
+// SetAudioLoudnessCommand is the Command sent by SetAudioLoudness. The second program is optional.
+type SetAudioLoudnessCommand struct {
+	FirstProgram  AudioProgram
+	SecondProgram *AudioProgram
+}
+
+func (c SetAudioLoudnessCommand) Encode() string {
+	msg := buildAudioProgramCommand(c.FirstProgram)
+	if c.SecondProgram != nil {
+		msg += buildAudioProgramCommand(*c.SecondProgram)
+	}
+	return msg
+}
+
+func (c SetAudioLoudnessCommand) Validate() error {
+	if err := validateAudioProgram(c.FirstProgram); err != nil {
+		return err
+	}
+	if c.SecondProgram != nil {
+		return validateAudioProgram(*c.SecondProgram)
+	}
+	return nil
+}
+
 // SetAudioLoudness reconfigures or creates a new Loudness instance on the specified audio channels.
 //
 // Multiple SetAudioLoudness commands can be use to set multiple loudness groups within the output stream.
@@ -19,35 +44,51 @@ import (
 //
 // The second audio program is optional.
 func (o *Oxtel) SetAudioLoudness(firstProgram AudioProgram, secondProgram *AudioProgram) error {
-	msg, err := buildAudioProgramCommand(firstProgram)
-	if err != nil {
-		return err
-	}
-
-	msg2 := ""
-	if secondProgram != nil {
-		msg2, err = buildAudioProgramCommand(*secondProgram)
-		if err != nil {
-			return err
-		}
-	}
+	return o.SetAudioLoudnessContext(context.Background(), firstProgram, secondProgram)
+}
 
-	return o.sendCommand(fmt.Sprintf("%s%s", msg, msg2))
+// SetAudioLoudnessContext is like SetAudioLoudness but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioLoudnessContext(ctx context.Context, firstProgram AudioProgram, secondProgram *AudioProgram) error {
+	return o.SendContext(ctx, SetAudioLoudnessCommand{FirstProgram: firstProgram, SecondProgram: secondProgram})
 }
 
 // SetAudioLoudness_AsString returns the command string used to reconfigure or create a new Loudness instance on the
 // specified audio channels.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioLoudnessCommand instead.
 func SetAudioLoudness_AsString(firstProgram AudioProgram, secondProgram *AudioProgram) string {
-	msg, _ := buildAudioProgramCommand(firstProgram)
+	return SetAudioLoudnessCommand{FirstProgram: firstProgram, SecondProgram: secondProgram}.Encode()
+}
 
-	msg2 := ""
-	if secondProgram != nil {
-		msg2, _ = buildAudioProgramCommand(*secondProgram)
+// GetAudioLoudnessCommand is the Enquiry sent by GetAudioLoudness. A nil Channel enquires every channel.
+type GetAudioLoudnessCommand struct {
+	Sdi     uint8
+	Channel *uint8
+}
+
+func (c GetAudioLoudnessCommand) Encode() string {
+	if c.Channel == nil {
+		return fmt.Sprintf("jAL%02x", c.Sdi)
 	}
+	return fmt.Sprintf("jAL%02x%02x", c.Sdi, *c.Channel)
+}
+
+func (c GetAudioLoudnessCommand) Validate() error {
+	if c.Channel != nil && *c.Channel > 8 {
+		return &InvalidAudioProfileError{
+			BaseError: BaseError{
+				Message: "Channel must be less than 8",
+			},
+		}
+	}
+
+	return nil
+}
 
-	return fmt.Sprintf("%s%s", msg, msg2)
+func (c GetAudioLoudnessCommand) ResponsePrefix() string {
+	return "jAL"
 }
 
 // GetAudioLoudness returns all audio channels that have a loudness program configured.
@@ -58,9 +99,14 @@ func SetAudioLoudness_AsString(firstProgram AudioProgram, secondProgram *AudioPr
 //
 // Response is a AudioLoudnessResponse. Sdi, AudioProgram, and JungarProgram are only populated when a channel is specified.
 func (o *Oxtel) GetAudioLoudness(sdi uint8, channel *uint8) (AudioLoudnessResponse, error) {
+	return o.GetAudioLoudnessContext(context.Background(), sdi, channel)
+}
+
+// GetAudioLoudnessContext is like GetAudioLoudness but uses ctx for cancellation and deadlines.
+func (o *Oxtel) GetAudioLoudnessContext(ctx context.Context, sdi uint8, channel *uint8) (AudioLoudnessResponse, error) {
 	if channel == nil {
 		// Return all audio channels that have loudness configured.
-		val, err := o.sendCommandExpectResponse("jAL", fmt.Sprintf("%02x", sdi))
+		val, err := o.EnquireContext(ctx, GetAudioLoudnessCommand{Sdi: sdi, Channel: channel})
 		if err != nil {
 			return AudioLoudnessResponse{}, err
 		}
@@ -117,15 +163,7 @@ func (o *Oxtel) GetAudioLoudness(sdi uint8, channel *uint8) (AudioLoudnessRespon
 			Channel8: ch8,
 		}, nil
 	} else {
-		if *channel > 8 {
-			return AudioLoudnessResponse{}, &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel must be less than 8",
-				},
-			}
-		}
-
-		val, err := o.sendCommandExpectResponse("jAL", fmt.Sprintf("%02x%02x", sdi, *channel))
+		val, err := o.EnquireContext(ctx, GetAudioLoudnessCommand{Sdi: sdi, Channel: channel})
 		if err != nil {
 			return AudioLoudnessResponse{}, err
 		}
@@ -211,17 +249,23 @@ func (o *Oxtel) GetAudioLoudness(sdi uint8, channel *uint8) (AudioLoudnessRespon
 // GetAudioLoudness_AsString returns the command string used to return all audio channels that have a loudness program configured.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a GetAudioLoudnessCommand instead.
 func GetAudioLoudness_AsString(sdi uint8, channel *uint8) string {
-	if channel == nil {
-		return fmt.Sprintf("jAL%02x", sdi)
-	} else {
-		return fmt.Sprintf("jAL%02x%02x", sdi, *channel)
-	}
+	return GetAudioLoudnessCommand{Sdi: sdi, Channel: channel}.Encode()
 }
 
-// DisableAudioLoudness stops all loudness processing.
-func (o *Oxtel) DisableAudioLoudness(sdi uint8) error {
-	if sdi > 2 {
+// DisableAudioLoudnessCommand is the Command sent by DisableAudioLoudness.
+type DisableAudioLoudnessCommand struct {
+	Sdi uint8
+}
+
+func (c DisableAudioLoudnessCommand) Encode() string {
+	return fmt.Sprintf("jALP%02x", c.Sdi)
+}
+
+func (c DisableAudioLoudnessCommand) Validate() error {
+	if c.Sdi > 2 {
 		return &InvalidSdiError{
 			BaseError: BaseError{
 				Message: "SDI must be less than 3",
@@ -229,22 +273,39 @@ func (o *Oxtel) DisableAudioLoudness(sdi uint8) error {
 		}
 	}
 
-	msg := fmt.Sprintf("jALP%02x", sdi)
-	return o.sendCommand(msg)
+	return nil
+}
+
+// DisableAudioLoudness stops all loudness processing.
+func (o *Oxtel) DisableAudioLoudness(sdi uint8) error {
+	return o.DisableAudioLoudnessContext(context.Background(), sdi)
+}
+
+// DisableAudioLoudnessContext is like DisableAudioLoudness but uses ctx for cancellation and deadlines.
+func (o *Oxtel) DisableAudioLoudnessContext(ctx context.Context, sdi uint8) error {
+	return o.SendContext(ctx, DisableAudioLoudnessCommand{Sdi: sdi})
 }
 
 // DisableAudioLoudness_AsString returns the commands string used to stop all loudness processing.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a DisableAudioLoudnessCommand instead.
 func DisableAudioLoudness_AsString(sdi uint8) string {
-	return fmt.Sprintf("jALP%02x", sdi)
+	return DisableAudioLoudnessCommand{Sdi: sdi}.Encode()
 }
 
-// EnableAudioLoudness resumes loudness processing.
-//
-// If audio loudness was not disabled, this command has no effect.
-func (o *Oxtel) EnableAudioLoudness(sdi uint8) error {
-	if sdi > 2 {
+// EnableAudioLoudnessCommand is the Command sent by EnableAudioLoudness.
+type EnableAudioLoudnessCommand struct {
+	Sdi uint8
+}
+
+func (c EnableAudioLoudnessCommand) Encode() string {
+	return fmt.Sprintf("jALR%02x", c.Sdi)
+}
+
+func (c EnableAudioLoudnessCommand) Validate() error {
+	if c.Sdi > 2 {
 		return &InvalidSdiError{
 			BaseError: BaseError{
 				Message: "SDI must be less than 3",
@@ -252,23 +313,42 @@ func (o *Oxtel) EnableAudioLoudness(sdi uint8) error {
 		}
 	}
 
-	msg := fmt.Sprintf("jALR%02x", sdi)
-	return o.sendCommand(msg)
+	return nil
+}
+
+// EnableAudioLoudness resumes loudness processing.
+//
+// If audio loudness was not disabled, this command has no effect.
+func (o *Oxtel) EnableAudioLoudness(sdi uint8) error {
+	return o.EnableAudioLoudnessContext(context.Background(), sdi)
+}
+
+// EnableAudioLoudnessContext is like EnableAudioLoudness but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableAudioLoudnessContext(ctx context.Context, sdi uint8) error {
+	return o.SendContext(ctx, EnableAudioLoudnessCommand{Sdi: sdi})
 }
 
 // EnableAudioLoudness_AsString returns the command string used to resume loudness processing.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableAudioLoudnessCommand instead.
 func EnableAudioLoudness_AsString(sdi uint8) string {
-	return fmt.Sprintf("jALR%02x", sdi)
+	return EnableAudioLoudnessCommand{Sdi: sdi}.Encode()
 }
 
-// ChangeAudioLoudnessProfile sets the Junger audio Loudness profile for the specified audio program.
-//
-// Audio profiles are set up in SystemManager. There can be up to 16 loudness profiles defined.
-// Only one profile is in effect at a time, and profiles other than 1, can only be selected with the SetAudioLoudness command.
-func (o *Oxtel) ChangeAudioLoudnessProfile(sdi uint8, profile uint8) error {
-	if sdi > 2 {
+// ChangeAudioLoudnessProfileCommand is the Command sent by ChangeAudioLoudnessProfile.
+type ChangeAudioLoudnessProfileCommand struct {
+	Sdi     uint8
+	Profile uint8
+}
+
+func (c ChangeAudioLoudnessProfileCommand) Encode() string {
+	return fmt.Sprintf("jALA%02x%02x", c.Sdi, c.Profile)
+}
+
+func (c ChangeAudioLoudnessProfileCommand) Validate() error {
+	if c.Sdi > 2 {
 		return &InvalidSdiError{
 			BaseError: BaseError{
 				Message: "SDI must be less than 3",
@@ -276,7 +356,7 @@ func (o *Oxtel) ChangeAudioLoudnessProfile(sdi uint8, profile uint8) error {
 		}
 	}
 
-	if profile > 16 {
+	if c.Profile > 16 {
 		return &InvalidAudioProfileError{
 			BaseError: BaseError{
 				Message: "Audio Profile must be less than 17",
@@ -284,32 +364,67 @@ func (o *Oxtel) ChangeAudioLoudnessProfile(sdi uint8, profile uint8) error {
 		}
 	}
 
-	msg := fmt.Sprintf("jALA%02x%02x", sdi, profile)
+	return nil
+}
 
-	return o.sendCommand(msg)
+// ChangeAudioLoudnessProfile sets the Junger audio Loudness profile for the specified audio program.
+//
+// Audio profiles are set up in SystemManager. There can be up to 16 loudness profiles defined.
+// Only one profile is in effect at a time, and profiles other than 1, can only be selected with the SetAudioLoudness command.
+func (o *Oxtel) ChangeAudioLoudnessProfile(sdi uint8, profile uint8) error {
+	return o.ChangeAudioLoudnessProfileContext(context.Background(), sdi, profile)
+}
+
+// ChangeAudioLoudnessProfileContext is like ChangeAudioLoudnessProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ChangeAudioLoudnessProfileContext(ctx context.Context, sdi uint8, profile uint8) error {
+	return o.SendContext(ctx, ChangeAudioLoudnessProfileCommand{Sdi: sdi, Profile: profile})
 }
 
 // ChangeAudioLoudnessProfile_AsString returns the command string used to set the Junger audio Loudness profile
 // for the specified audio program.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a ChangeAudioLoudnessProfileCommand instead.
 func ChangeAudioLoudnessProfile_AsString(sdi uint8, profile uint8) string {
-	return fmt.Sprintf("jALA%02x%02x", sdi, profile)
+	return ChangeAudioLoudnessProfileCommand{Sdi: sdi, Profile: profile}.Encode()
 }
 
-// GetAudioLoudnessProfile returns the current Audio Loudness Profile for the specified program.
-//
-// Response is a uint8 representing the current profile.
-func (o *Oxtel) GetAudioLoudnessProfile(sdi uint8) (uint8, error) {
-	if sdi > 2 {
-		return 0, &InvalidSdiError{
+// GetAudioLoudnessProfileCommand is the Enquiry sent by GetAudioLoudnessProfile.
+type GetAudioLoudnessProfileCommand struct {
+	Sdi uint8
+}
+
+func (c GetAudioLoudnessProfileCommand) Encode() string {
+	return fmt.Sprintf("jALA%02x", c.Sdi)
+}
+
+func (c GetAudioLoudnessProfileCommand) Validate() error {
+	if c.Sdi > 2 {
+		return &InvalidSdiError{
 			BaseError: BaseError{
 				Message: "SDI must be less than 3",
 			},
 		}
 	}
 
-	val, err := o.sendCommandExpectResponse("jALA", fmt.Sprintf("%02x", sdi))
+	return nil
+}
+
+func (c GetAudioLoudnessProfileCommand) ResponsePrefix() string {
+	return "jALA"
+}
+
+// GetAudioLoudnessProfile returns the current Audio Loudness Profile for the specified program.
+//
+// Response is a uint8 representing the current profile.
+func (o *Oxtel) GetAudioLoudnessProfile(sdi uint8) (uint8, error) {
+	return o.GetAudioLoudnessProfileContext(context.Background(), sdi)
+}
+
+// GetAudioLoudnessProfileContext is like GetAudioLoudnessProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) GetAudioLoudnessProfileContext(ctx context.Context, sdi uint8) (uint8, error) {
+	val, err := o.EnquireContext(ctx, GetAudioLoudnessProfileCommand{Sdi: sdi})
 	if err != nil {
 		return 0, err
 	}
@@ -322,15 +437,37 @@ func (o *Oxtel) GetAudioLoudnessProfile(sdi uint8) (uint8, error) {
 // for the specified program.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a GetAudioLoudnessProfileCommand instead.
 func GetAudioLoudnessProfile_AsString(sdi uint8) string {
-	return fmt.Sprintf("jALA%02x", sdi)
+	return GetAudioLoudnessProfileCommand{Sdi: sdi}.Encode()
+}
+
+// GetLoudnessLicenseStatusCommand is the Enquiry sent by GetLoudnessLicenseStatus.
+type GetLoudnessLicenseStatusCommand struct{}
+
+func (c GetLoudnessLicenseStatusCommand) Encode() string {
+	return "jALL"
+}
+
+func (c GetLoudnessLicenseStatusCommand) Validate() error {
+	return nil
+}
+
+func (c GetLoudnessLicenseStatusCommand) ResponsePrefix() string {
+	return "jALL"
 }
 
 // GetLoudnessLicenseStatus queries the current loudness license status.
 //
 // Response is a boolean representing if loudness is licensed.
 func (o *Oxtel) GetLoudnessLicenseStatus() (bool, error) {
-	val, err := o.sendCommandExpectResponse("jALL", "")
+	return o.GetLoudnessLicenseStatusContext(context.Background())
+}
+
+// GetLoudnessLicenseStatusContext is like GetLoudnessLicenseStatus but uses ctx for cancellation and deadlines.
+func (o *Oxtel) GetLoudnessLicenseStatusContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, GetLoudnessLicenseStatusCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -341,7 +478,8 @@ func (o *Oxtel) GetLoudnessLicenseStatus() (bool, error) {
 // GetLoudnessLicenseStatus_AsString returns the command string used to query the current loudness license status.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a GetLoudnessLicenseStatusCommand instead.
 func GetLoudnessLicenseStatus_AsString() string {
-	return "jALL"
-
+	return GetLoudnessLicenseStatusCommand{}.Encode()
 }
@@ -1,13 +1,22 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
-// SetAudioABMixerFadeRate sets the fade rate for audio mixes.
-func (o *Oxtel) SetAudioABMixerFadeRate(duration uint16) error {
-	if duration > 999 {
+// SetAudioABMixerFadeRateCommand is the Command sent by SetAudioABMixerFadeRate.
+type SetAudioABMixerFadeRateCommand struct {
+	Duration uint16
+}
+
+func (c SetAudioABMixerFadeRateCommand) Encode() string {
+	return fmt.Sprintf("j31%03x", c.Duration)
+}
+
+func (c SetAudioABMixerFadeRateCommand) Validate() error {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000",
@@ -15,46 +24,105 @@ func (o *Oxtel) SetAudioABMixerFadeRate(duration uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("j31%03x", duration)
+	return nil
+}
+
+// SetAudioABMixerFadeRate sets the fade rate for audio mixes.
+func (o *Oxtel) SetAudioABMixerFadeRate(duration uint16) error {
+	return o.SetAudioABMixerFadeRateContext(context.Background(), duration)
+}
 
-	return o.sendCommand(msg)
+// SetAudioABMixerFadeRateContext is like SetAudioABMixerFadeRate but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioABMixerFadeRateContext(ctx context.Context, duration uint16) error {
+	return o.SendContext(ctx, SetAudioABMixerFadeRateCommand{Duration: duration})
 }
 
 // SetAudioABMixerFadeRate_AsString returns the command string used to set the fade rate for audio mixes.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioABMixerFadeRateCommand instead.
 func SrtAudioABMixerFadeRate_AsString(duration uint16) string {
-	return fmt.Sprintf("j31%03x", duration)
+	return SetAudioABMixerFadeRateCommand{Duration: duration}.Encode()
+}
+
+// AudioCutABCommand is the Command sent by AudioCutAB.
+type AudioCutABCommand struct {
+	Destination OxtelMixerInput
+}
+
+func (c AudioCutABCommand) Encode() string {
+	return fmt.Sprintf("j40%x", c.Destination)
+}
+
+func (c AudioCutABCommand) Validate() error {
+	return nil
 }
 
 // AudioCutAB cuts audio between the A and B sources
 func (o *Oxtel) AudioCutAB(destination OxtelMixerInput) error {
-	msg := fmt.Sprintf("j40%x", destination)
+	return o.AudioCutABContext(context.Background(), destination)
+}
 
-	return o.sendCommand(msg)
+// AudioCutABContext is like AudioCutAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AudioCutABContext(ctx context.Context, destination OxtelMixerInput) error {
+	return o.SendContext(ctx, AudioCutABCommand{Destination: destination})
 }
 
 // AudioCutAB_AsString returns the command string used to cut audio between the A and B sources
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AudioCutABCommand instead.
 func AudioCutAB_AsString(destination OxtelMixerInput) string {
-	return fmt.Sprintf("j40%x", destination)
+	return AudioCutABCommand{Destination: destination}.Encode()
+}
+
+// AudioFadeABCommand is the Command sent by AudioFadeAB.
+type AudioFadeABCommand struct {
+	Destination OxtelMixerInput
+}
+
+func (c AudioFadeABCommand) Encode() string {
+	return fmt.Sprintf("j41%x", c.Destination)
+}
+
+func (c AudioFadeABCommand) Validate() error {
+	return nil
 }
 
 // AudioFadeAB fades audio between the A and B sources using the duration specified by the SetAudioABMixerFadeRate command
 // and the mode specified by the SetAudioABMixMode command.
 func (o *Oxtel) AudioFadeAB(destination OxtelMixerInput) error {
-	msg := fmt.Sprintf("j41%x", destination)
+	return o.AudioFadeABContext(context.Background(), destination)
+}
 
-	return o.sendCommand(msg)
+// AudioFadeABContext is like AudioFadeAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AudioFadeABContext(ctx context.Context, destination OxtelMixerInput) error {
+	return o.SendContext(ctx, AudioFadeABCommand{Destination: destination})
 }
 
 // AudioFadeAB_AsString returns the command string used to fade audio between the A and B sources using the duration specified
 // by the SetAudioABMixerFadeRate command and the mode specified by the SetAudioABMixMode command.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AudioFadeABCommand instead.
 func AudioFadeAB_AsString(destination OxtelMixerInput) string {
-	return fmt.Sprintf("j41%x", destination)
+	return AudioFadeABCommand{Destination: destination}.Encode()
+}
+
+// SetAudioABFollowVideoABCommand is the Command sent by SetAudioABFollowVideoAB.
+type SetAudioABFollowVideoABCommand struct {
+	Enable bool
+}
+
+func (c SetAudioABFollowVideoABCommand) Encode() string {
+	return fmt.Sprintf("j51%x", boolToInt(c.Enable))
+}
+
+func (c SetAudioABFollowVideoABCommand) Validate() error {
+	return nil
 }
 
 // SetAudioABFollowVideoAB allows the audio A/B mixer to automatically follow the position of the video A/B mixer.
@@ -62,24 +130,49 @@ func AudioFadeAB_AsString(destination OxtelMixerInput) string {
 // When enabled, the audio A/B mixer will follow the video A/B mixer. When disabled, the audio A/B mixer must be
 // controlled independently.
 func (o *Oxtel) SetAudioABFollowVideoAB(enable bool) error {
-	msg := fmt.Sprintf("j51%x", boolToInt(enable))
+	return o.SetAudioABFollowVideoABContext(context.Background(), enable)
+}
 
-	return o.sendCommand(msg)
+// SetAudioABFollowVideoABContext is like SetAudioABFollowVideoAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioABFollowVideoABContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, SetAudioABFollowVideoABCommand{Enable: enable})
 }
 
 // SetAudioABFollowVideoAB_AsString returns the command string used to allow the audio A/B mixer to automatically
 // follow the position of the video A/B mixer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioABFollowVideoABCommand instead.
 func SetAudioABFollowVideoAB_AsString(enable bool) string {
-	return fmt.Sprintf("j51%x", boolToInt(enable))
+	return SetAudioABFollowVideoABCommand{Enable: enable}.Encode()
+}
+
+// EnquireAudioABFollowVideoABCommand is the Enquiry sent by EnquireAudioABFollowVideoAB.
+type EnquireAudioABFollowVideoABCommand struct{}
+
+func (c EnquireAudioABFollowVideoABCommand) Encode() string {
+	return "j74"
+}
+
+func (c EnquireAudioABFollowVideoABCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireAudioABFollowVideoABCommand) ResponsePrefix() string {
+	return "j74"
 }
 
 // EnquireAudioABFollowVideoAB queries the current audio follow video settings.
 //
 // Response is an AudioABFollowVideoABResponse.
 func (o *Oxtel) EnquireAudioABFollowVideoAB() (AudioABFollowVideoABResponse, error) {
-	val, err := o.sendCommandExpectResponse("j74", "")
+	return o.EnquireAudioABFollowVideoABContext(context.Background())
+}
+
+// EnquireAudioABFollowVideoABContext is like EnquireAudioABFollowVideoAB but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireAudioABFollowVideoABContext(ctx context.Context) (AudioABFollowVideoABResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireAudioABFollowVideoABCommand{})
 	if err != nil {
 		return AudioABFollowVideoABResponse{}, err
 	}
@@ -99,13 +192,23 @@ func (o *Oxtel) EnquireAudioABFollowVideoAB() (AudioABFollowVideoABResponse, err
 // EnquireAudioABFollowVideoAB_AsString returns the command string used to query the current audio follow video settings.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireAudioABFollowVideoABCommand instead.
 func EnquireAudioABFollowVideoAB_AsString() string {
-	return "j74"
+	return EnquireAudioABFollowVideoABCommand{}.Encode()
 }
 
-// SetAudioABPosition sets the position of the audio A/B mixer.
-func (o *Oxtel) SetAudioABPosition(mix uint16) error {
-	if mix > 512 {
+// SetAudioABPositionCommand is the Command sent by SetAudioABPosition.
+type SetAudioABPositionCommand struct {
+	Mix uint16
+}
+
+func (c SetAudioABPositionCommand) Encode() string {
+	return fmt.Sprintf("ja%03x", c.Mix)
+}
+
+func (c SetAudioABPositionCommand) Validate() error {
+	if c.Mix > 512 {
 		return &InvalidMixError{
 			BaseError: BaseError{
 				Message: "Mix must be less than 513",
@@ -113,30 +216,73 @@ func (o *Oxtel) SetAudioABPosition(mix uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("ja%03x", mix)
+	return nil
+}
+
+// SetAudioABPosition sets the position of the audio A/B mixer.
+func (o *Oxtel) SetAudioABPosition(mix uint16) error {
+	return o.SetAudioABPositionContext(context.Background(), mix)
+}
 
-	return o.sendCommand(msg)
+// SetAudioABPositionContext is like SetAudioABPosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioABPositionContext(ctx context.Context, mix uint16) error {
+	return o.SendContext(ctx, SetAudioABPositionCommand{Mix: mix})
 }
 
 // SetAudioABPosition_AsString returns the command string used to set the position of the audio A/B mixer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioABPositionCommand instead.
 func SetAudioABPosition_AsString(mix uint16) string {
-	return fmt.Sprintf("ja%03x", mix)
+	return SetAudioABPositionCommand{Mix: mix}.Encode()
+}
+
+// SetAudioABMixModeCommand is the Command sent by SetAudioABMixMode.
+type SetAudioABMixModeCommand struct {
+	Mode OxtelAudioMixMode
+}
+
+func (c SetAudioABMixModeCommand) Encode() string {
+	return fmt.Sprintf("jb%x", c.Mode)
+}
+
+func (c SetAudioABMixModeCommand) Validate() error {
+	return nil
 }
 
 // SetAudioABMixMode sets the audio mixer mode.
 func (o *Oxtel) SetAudioABMixMode(mode OxtelAudioMixMode) error {
-	msg := fmt.Sprintf("jb%x", mode)
+	return o.SetAudioABMixModeContext(context.Background(), mode)
+}
 
-	return o.sendCommand(msg)
+// SetAudioABMixModeContext is like SetAudioABMixMode but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioABMixModeContext(ctx context.Context, mode OxtelAudioMixMode) error {
+	return o.SendContext(ctx, SetAudioABMixModeCommand{Mode: mode})
 }
 
 // SetAudioABMixMode_AsString returns the command string used to set the audio mixer mode.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioABMixModeCommand instead.
 func SetAudioABMixMode_AsString(mode OxtelAudioMixMode) string {
-	return fmt.Sprintf("jb%x", mode)
+	return SetAudioABMixModeCommand{Mode: mode}.Encode()
+}
+
+// AudioABAsymmetricTransitionCommand is the Command sent by AudioABAsymmetricTransition.
+type AudioABAsymmetricTransitionCommand struct {
+	Direction OxtelMixerInput
+	Rate1     uint16
+	Rate2     uint16
+}
+
+func (c AudioABAsymmetricTransitionCommand) Encode() string {
+	return fmt.Sprintf("jc%x%03x%03x", c.Direction, c.Rate1, c.Rate2)
+}
+
+func (c AudioABAsymmetricTransitionCommand) Validate() error {
+	return nil
 }
 
 // AudioABAsymmetricTransition performs an asymmetric transition such as a cut-fade or fade-cut.
@@ -147,24 +293,36 @@ func SetAudioABMixMode_AsString(mode OxtelAudioMixMode) string {
 //
 // Both rates are measured in fields (interlaces) or frames (progressive).
 func (o *Oxtel) AudioABAsymmetricTransition(direction OxtelMixerInput, rate1 uint16, rate2 uint16) error {
-	msg := fmt.Sprintf("jc%x%03x%03x", direction, rate1, rate2)
+	return o.AudioABAsymmetricTransitionContext(context.Background(), direction, rate1, rate2)
+}
 
-	return o.sendCommand(msg)
+// AudioABAsymmetricTransitionContext is like AudioABAsymmetricTransition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AudioABAsymmetricTransitionContext(ctx context.Context, direction OxtelMixerInput, rate1 uint16, rate2 uint16) error {
+	return o.SendContext(ctx, AudioABAsymmetricTransitionCommand{Direction: direction, Rate1: rate1, Rate2: rate2})
 }
 
 // AudioABAsymmetricTransition_AsString returns the command string used to perform an asymmetric transition such as
 // a cut-fade or fade-cut.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AudioABAsymmetricTransitionCommand instead.
 func AudioABAsymmetricTransition_AsString(direction OxtelMixerInput, rate1 uint16, rate2 uint16) string {
-	return fmt.Sprintf("jc%x%03x%03x", direction, rate1, rate2)
+	return AudioABAsymmetricTransitionCommand{Direction: direction, Rate1: rate1, Rate2: rate2}.Encode()
 }
 
-// AudioABFadeToPosition fades to the specified position over the defined duration in fields (interlaces) or frames (progressive).
-//
-// This is similar to the AudioABPosition command but provides a fade to the specified position instead of a cut.
-func (o *Oxtel) AudioABFadeToPosition(mix uint16, duration uint16) error {
-	if mix > 512 {
+// AudioABFadeToPositionCommand is the Command sent by AudioABFadeToPosition.
+type AudioABFadeToPositionCommand struct {
+	Mix      uint16
+	Duration uint16
+}
+
+func (c AudioABFadeToPositionCommand) Encode() string {
+	return fmt.Sprintf("jd%03x%03x", c.Mix, c.Duration)
+}
+
+func (c AudioABFadeToPositionCommand) Validate() error {
+	if c.Mix > 512 {
 		return &InvalidMixError{
 			BaseError: BaseError{
 				Message: "Mix must be less than 513",
@@ -172,7 +330,7 @@ func (o *Oxtel) AudioABFadeToPosition(mix uint16, duration uint16) error {
 		}
 	}
 
-	if duration > 999 {
+	if c.Duration > 999 {
 		return &InvalidDurationError{
 			BaseError: BaseError{
 				Message: "Duration must be less than 1000",
@@ -180,17 +338,55 @@ func (o *Oxtel) AudioABFadeToPosition(mix uint16, duration uint16) error {
 		}
 	}
 
-	msg := fmt.Sprintf("jd%03x%03x", mix, duration)
+	return nil
+}
+
+// AudioABFadeToPosition fades to the specified position over the defined duration in fields (interlaces) or frames (progressive).
+//
+// This is similar to the AudioABPosition command but provides a fade to the specified position instead of a cut.
+func (o *Oxtel) AudioABFadeToPosition(mix uint16, duration uint16) error {
+	return o.AudioABFadeToPositionContext(context.Background(), mix, duration)
+}
 
-	return o.sendCommand(msg)
+// AudioABFadeToPositionContext is like AudioABFadeToPosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AudioABFadeToPositionContext(ctx context.Context, mix uint16, duration uint16) error {
+	return o.SendContext(ctx, AudioABFadeToPositionCommand{Mix: mix, Duration: duration})
 }
 
 // AudioABFadeToPosition_AsString returns the command string used to fade to the specified position over the defined
 // duration in fields (interlaces) or frames (progressive).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an AudioABFadeToPositionCommand instead.
 func AudioABFadeToPosition_AsString(mix uint16, duration uint16) string {
-	return fmt.Sprintf("jd%03x%03x", mix, duration)
+	return AudioABFadeToPositionCommand{Mix: mix, Duration: duration}.Encode()
+}
+
+// SetAudioGainCommand is the Command sent by SetAudioGain.
+type SetAudioGainCommand struct {
+	Output      OxtelAudioOutput
+	ChannelMask ChannelMask
+	Gain        *int8
+}
+
+func (c SetAudioGainCommand) Encode() string {
+	if c.Gain == nil {
+		return fmt.Sprintf("jAG%02x%s", c.Output, buildChannelMask(c.ChannelMask))
+	}
+	return fmt.Sprintf("jAG%02x%s%d", c.Output, buildChannelMask(c.ChannelMask), *c.Gain)
+}
+
+func (c SetAudioGainCommand) Validate() error {
+	if c.Gain != nil && (*c.Gain < -100 || *c.Gain > 30) {
+		return &InvalidGainError{
+			BaseError: BaseError{
+				Message: "Gain must be between -100 and +30",
+			},
+		}
+	}
+
+	return nil
 }
 
 // SetAudioGain will control the audio gain for each source or the mixed output.
@@ -204,33 +400,49 @@ func AudioABFadeToPosition_AsString(mix uint16, duration uint16) string {
 //
 // Getting the gain for a single channel should always return the correct setting.
 func (o *Oxtel) SetAudioGain(output OxtelAudioOutput, channelMask ChannelMask, gain *int8) error {
-	mask := buildChannelMask(channelMask)
-	if *gain < -100 || *gain > 30 {
-		return &InvalidGainError{
-			BaseError: BaseError{
-				Message: "Gain must be between -100 and +30",
-			},
-		}
-	}
-
-	msg := fmt.Sprintf("jAG%02x%s%d", output, mask, gain)
+	return o.SetAudioGainContext(context.Background(), output, channelMask, gain)
+}
 
-	return o.sendCommand(msg)
+// SetAudioGainContext is like SetAudioGain but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioGainContext(ctx context.Context, output OxtelAudioOutput, channelMask ChannelMask, gain *int8) error {
+	return o.SendContext(ctx, SetAudioGainCommand{Output: output, ChannelMask: channelMask, Gain: gain})
 }
 
 // SetAudioGain_AsString returns the command string used to control the audio gain for each source or the mixed output.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioGainCommand instead.
 func SetAudioGain_AsString(output OxtelAudioOutput, channelMask ChannelMask, gain *int8) string {
-	mask := buildChannelMask(channelMask)
+	return SetAudioGainCommand{Output: output, ChannelMask: channelMask, Gain: gain}.Encode()
+}
+
+// EnquireAudioGainCommand is the Enquiry sent by EnquireAudioGain.
+type EnquireAudioGainCommand struct {
+	Output      OxtelAudioOutput
+	ChannelMask ChannelMask
+}
 
-	return fmt.Sprintf("jAG%02x%s%d", output, mask, gain)
+func (c EnquireAudioGainCommand) Encode() string {
+	return fmt.Sprintf("jAG%02x%s", c.Output, buildChannelMask(c.ChannelMask))
+}
+
+func (c EnquireAudioGainCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireAudioGainCommand) ResponsePrefix() string {
+	return "jAG"
 }
 
 // EnquireAudioGain queries the audio gain status for the specified channel mask.
 func (o *Oxtel) EnquireAudioGain(output OxtelAudioOutput, channelMask ChannelMask) (AudioGainResponse, error) {
-	mask := buildChannelMask(channelMask)
-	val, err := o.sendCommandExpectResponse("jAG", fmt.Sprintf("%02x%s", output, mask))
+	return o.EnquireAudioGainContext(context.Background(), output, channelMask)
+}
+
+// EnquireAudioGainContext is like EnquireAudioGain but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireAudioGainContext(ctx context.Context, output OxtelAudioOutput, channelMask ChannelMask) (AudioGainResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireAudioGainCommand{Output: output, ChannelMask: channelMask})
 	if err != nil {
 		return AudioGainResponse{}, err
 	}
@@ -240,17 +452,16 @@ func (o *Oxtel) EnquireAudioGain(output OxtelAudioOutput, channelMask ChannelMas
 		return AudioGainResponse{}, err
 	}
 
-	channelMaskRaw, err := strconv.ParseInt(string(val[2:6]), 16, 16)
+	mask, err := strconv.ParseUint(string(val[2:6]), 16, 16)
 	if err != nil {
 		return AudioGainResponse{}, err
 	}
-	outChannelMask := fmt.Sprintf("%016b", channelMaskRaw)
 
 	gain, err := strconv.Atoi(string(val[6:]))
 
 	return AudioGainResponse{
 		Source:      OxtelAudioSource(source),
-		ChannelMask: outChannelMask,
+		ChannelMask: ChannelMask(mask),
 		Gain:        int8(gain),
 	}, err
 }
@@ -258,8 +469,8 @@ func (o *Oxtel) EnquireAudioGain(output OxtelAudioOutput, channelMask ChannelMas
 // EnquireAudioGain_AsString returns the command string used to query the audio gain status for the specified channel mask.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireAudioGainCommand instead.
 func EnquireAudioGain_AsString(output OxtelAudioOutput, channelMask ChannelMask) string {
-	mask := buildChannelMask(channelMask)
-
-	return fmt.Sprintf("jAG%02x%s", output, mask)
+	return EnquireAudioGainCommand{Output: output, ChannelMask: channelMask}.Encode()
 }
@@ -1,14 +1,23 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
-// SetAudioProfile sets the audio profile for the specified audio source.
-// Valid Audio Profile values are 0 (use the default from SystemManager), 1-15. The Audio Profiles are configured in SystemManager.
-func (o *Oxtel) SetAudioProfile(source OxtelAudioSource, profile uint8) error {
-	if profile > 16 {
+// SetAudioProfileCommand is the Command sent by SetAudioProfile.
+type SetAudioProfileCommand struct {
+	Source  OxtelAudioSource
+	Profile uint8
+}
+
+func (c SetAudioProfileCommand) Encode() string {
+	return fmt.Sprintf("jAP%x%02x", c.Source, c.Profile)
+}
+
+func (c SetAudioProfileCommand) Validate() error {
+	if c.Profile > 16 {
 		return &InvalidAudioProfileError{
 			BaseError: BaseError{
 				Message: "Audio Profile must be less than 17",
@@ -16,16 +25,44 @@ func (o *Oxtel) SetAudioProfile(source OxtelAudioSource, profile uint8) error {
 		}
 	}
 
-	msg := fmt.Sprintf("jAP%x%02x", source, profile)
+	return nil
+}
 
-	return o.sendCommand(msg)
+// SetAudioProfile sets the audio profile for the specified audio source.
+// Valid Audio Profile values are 0 (use the default from SystemManager), 1-15. The Audio Profiles are configured in SystemManager.
+func (o *Oxtel) SetAudioProfile(source OxtelAudioSource, profile uint8) error {
+	return o.SetAudioProfileContext(context.Background(), source, profile)
+}
+
+// SetAudioProfileContext is like SetAudioProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetAudioProfileContext(ctx context.Context, source OxtelAudioSource, profile uint8) error {
+	return o.SendContext(ctx, SetAudioProfileCommand{Source: source, Profile: profile})
 }
 
 // SetAudioProfile_AsString returns the command string used set the audio profile for the specified audio source.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetAudioProfileCommand instead.
 func SetAudioProfile_AsString(source OxtelAudioSource, profile uint8) string {
-	return fmt.Sprintf("jAP%x%02x", source, profile)
+	return SetAudioProfileCommand{Source: source, Profile: profile}.Encode()
+}
+
+// EnquireAudioProfileCommand is the Enquiry sent by EnquireAudioProfile.
+type EnquireAudioProfileCommand struct {
+	Source OxtelAudioSource
+}
+
+func (c EnquireAudioProfileCommand) Encode() string {
+	return fmt.Sprintf("jAP%x", c.Source)
+}
+
+func (c EnquireAudioProfileCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireAudioProfileCommand) ResponsePrefix() string {
+	return "jAP"
 }
 
 // EnquireAudioProfile queries the audio profile for the specified audio source.
@@ -34,7 +71,12 @@ func SetAudioProfile_AsString(source OxtelAudioSource, profile uint8) string {
 //
 // Response is an AudioProfileResponse.
 func (o *Oxtel) EnquireAudioProfile(source OxtelAudioSource) (AudioProfileResponse, error) {
-	val, err := o.sendCommandExpectResponse("jAP", fmt.Sprintf("%x", source))
+	return o.EnquireAudioProfileContext(context.Background(), source)
+}
+
+// EnquireAudioProfileContext is like EnquireAudioProfile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireAudioProfileContext(ctx context.Context, source OxtelAudioSource) (AudioProfileResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireAudioProfileCommand{Source: source})
 	if err != nil {
 		return AudioProfileResponse{}, err
 	}
@@ -59,31 +101,71 @@ func (o *Oxtel) EnquireAudioProfile(source OxtelAudioSource) (AudioProfileRespon
 // EnquireAudioProfile_AsString returns the command string used to query the audio profile for the specified audio source.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireAudioProfileCommand instead.
 func EnquireAudioProfile_AsString(source OxtelAudioSource) string {
-	return fmt.Sprintf("jAP%x", source)
+	return EnquireAudioProfileCommand{Source: source}.Encode()
+}
+
+// EnableAudioProfileTalliesCommand is the Command sent by EnableAudioProfileTallies.
+type EnableAudioProfileTalliesCommand struct {
+	Enable bool
+}
+
+func (c EnableAudioProfileTalliesCommand) Encode() string {
+	return fmt.Sprintf("jAT%x", boolToInt(c.Enable))
+}
+
+func (c EnableAudioProfileTalliesCommand) Validate() error {
+	return nil
 }
 
 // EnableAudioProfileTallies enables/disables VideoTally, ImageLoadTally, ImagePreloadTally, KeyerPositionTally on the
 // connection on which the command was received.
 func (o *Oxtel) EnableAudioProfileTallies(enable bool) error {
-	msg := fmt.Sprintf("jAT%x", boolToInt(enable))
+	return o.EnableAudioProfileTalliesContext(context.Background(), enable)
+}
 
-	return o.sendCommand(msg)
+// EnableAudioProfileTalliesContext is like EnableAudioProfileTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableAudioProfileTalliesContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, EnableAudioProfileTalliesCommand{Enable: enable})
 }
 
 // EnableAudioProfileTallies_AsString returns the command string used to enable/disable VideoTally, ImageLoadTally,
 // ImagePreloadTally, KeyerPositionTally on the connection on which the command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableAudioProfileTalliesCommand instead.
 func EnableAudioProfileTallies_AsString(enable bool) string {
-	return fmt.Sprintf("jAT%x", boolToInt(enable))
+	return EnableAudioProfileTalliesCommand{Enable: enable}.Encode()
+}
+
+// EnquireAudioProfileTalliesCommand is the Enquiry sent by EnquireAudioProfileTallies.
+type EnquireAudioProfileTalliesCommand struct{}
+
+func (c EnquireAudioProfileTalliesCommand) Encode() string {
+	return "jAT"
+}
+
+func (c EnquireAudioProfileTalliesCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireAudioProfileTalliesCommand) ResponsePrefix() string {
+	return "jAT"
 }
 
 // EnquireAudioProfileTallies queries the state of the Video Tallies set using the EnableVideoTallies command.
 //
 // Response is a boolean representing if audio profile tallies are enabled.
 func (o *Oxtel) EnquireAudioProfileTallies() (bool, error) {
-	val, err := o.sendCommandExpectResponse("jAT", "")
+	return o.EnquireAudioProfileTalliesContext(context.Background())
+}
+
+// EnquireAudioProfileTalliesContext is like EnquireAudioProfileTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireAudioProfileTalliesContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquireAudioProfileTalliesCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -96,6 +178,8 @@ func (o *Oxtel) EnquireAudioProfileTallies() (bool, error) {
 // using the EnableVideoTallies command.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireAudioProfileTalliesCommand instead.
 func EnquireAudioProfileTallies_AsString() string {
-	return "jAT"
+	return EnquireAudioProfileTalliesCommand{}.Encode()
 }
@@ -0,0 +1,169 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+)
+
+// AVTake is a take of the video and audio A/B mixers to the same input, with the audio transition set independently of
+// the video one.
+type AVTake struct {
+	Destination OxtelMixerInput
+
+	// VideoType is OXTEL_TRANSITION_TYPE_V_FADE, OXTEL_TRANSITION_TYPE_X_FADE or OXTEL_TRANSITION_TYPE_CUT.
+	// VideoDuration is in fields (interlaced) or frames (progressive); zero cuts.
+	VideoType     OxtelTransitionType
+	VideoDuration uint16
+
+	// AudioMode is the V-fade or X-fade used by the audio mixer. AudioDuration is in fields (interlaced) or frames
+	// (progressive); zero cuts.
+	AudioMode     OxtelAudioMixMode
+	AudioDuration uint16
+
+	// AudioOffset is the number of frames the audio transition starts after the video one. A negative offset makes
+	// the audio lead the video.
+	AudioOffset int
+}
+
+// Validate checks the destination, transition types and durations of the take.
+func (t AVTake) Validate() error {
+	if t.Destination != OXTEL_MIXER_A && t.Destination != OXTEL_MIXER_B {
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: "Destination must be the A or B input of the mixer",
+			},
+		}
+	}
+
+	switch t.VideoType {
+	case OXTEL_TRANSITION_TYPE_V_FADE, OXTEL_TRANSITION_TYPE_X_FADE, OXTEL_TRANSITION_TYPE_CUT:
+	default:
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Video transition type %d is not a V-fade, X-fade or cut", t.VideoType),
+			},
+		}
+	}
+
+	switch t.AudioMode {
+	case OXTEL_AUDIO_MIX_MODE_X_FADE, OXTEL_AUDIO_MIX_MODE_V_FADE:
+	default:
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Audio mix mode %d is not a V-fade or X-fade", t.AudioMode),
+			},
+		}
+	}
+
+	if t.VideoDuration > 999 || t.AudioDuration > 999 {
+		return &InvalidDurationError{
+			BaseError: BaseError{
+				Message: "Durations must be less than 1000 fields/frames",
+			},
+		}
+	}
+
+	return nil
+}
+
+// Commands returns the take as ScheduledCommands, with the video transition at start and the audio transition
+// AudioOffset frames later. Audio follow video is turned off on the first frame of the take, so that the audio mixer
+// is driven by its own transition. It is not turned back on by the commands; TakeAV does that if it was on.
+func (t AVTake) Commands(start Timecode) []Command {
+	video := start
+	audio := start.AddFrames(t.AudioOffset)
+	first, _ := t.span(start)
+
+	var videoCmd Command
+	switch {
+	case t.VideoType == OXTEL_TRANSITION_TYPE_CUT || t.VideoDuration == 0:
+		videoCmd = CutToACommand{}
+		if t.Destination == OXTEL_MIXER_B {
+			videoCmd = CutToBCommand{}
+		}
+	case t.Destination == OXTEL_MIXER_B:
+		videoCmd = FadeToBCommand{Duration: t.VideoDuration}
+	default:
+		videoCmd = FadeToACommand{Duration: t.VideoDuration}
+	}
+
+	var audioCmd Command = AudioCutABCommand{Destination: t.Destination}
+	if t.AudioDuration > 0 {
+		mix := uint16(0)
+		if t.Destination == OXTEL_MIXER_B {
+			mix = 512
+		}
+		audioCmd = AudioABFadeToPositionCommand{Mix: mix, Duration: t.AudioDuration}
+	}
+
+	return []Command{
+		ScheduledCommand{Time: first, Command: SetAudioABFollowVideoABCommand{Enable: false}},
+		ScheduledCommand{Time: video, Command: SetTransitionTypeCommand{TransitionType: t.VideoType}},
+		ScheduledCommand{Time: video, Command: videoCmd},
+		ScheduledCommand{Time: audio, Command: SetAudioABMixModeCommand{Mode: t.AudioMode}},
+		ScheduledCommand{Time: audio, Command: audioCmd},
+	}
+}
+
+// span returns the first frame of a take starting at start and the frame after both transitions have ended. Durations
+// are counted as frames, so the end is late rather than early for interlaced transitions, which are timed in fields.
+func (t AVTake) span(start Timecode) (first Timecode, end Timecode) {
+	video := start
+	audio := start.AddFrames(t.AudioOffset)
+	first, end = video, video.AddFrames(int(t.VideoDuration)+1)
+	if t.AudioOffset < 0 {
+		first = audio
+	}
+	if t.AudioOffset+int(t.AudioDuration) > int(t.VideoDuration) {
+		end = audio.AddFrames(int(t.AudioDuration) + 1)
+	}
+	return first, end
+}
+
+// TakeAV schedules a take of the video and audio A/B mixers so that the video transition is seen on the SDI output at
+// start and the audio transition AudioOffset frames later. Both are scheduled with AddScheduledCommand and sent in a
+// single write, so the engine clock keeps them in step. The latencies and frame rate of the engine are taken into
+// account as by ScheduleOnAir.
+//
+// Audio follow video is turned off for the take, as described for Commands. If it was on, it is turned back on once
+// both transitions have ended.
+//
+// A ScheduleHorizonError is returned if the first or the last command of the take is not within ScheduleHorizon of the
+// current time of the engine, such as when a leading audio transition has already passed.
+func (o *Oxtel) TakeAV(start Timecode, take AVTake) error {
+	return o.TakeAVContext(context.Background(), start, take)
+}
+
+// TakeAVContext is like TakeAV but uses ctx for cancellation and deadlines.
+func (o *Oxtel) TakeAVContext(ctx context.Context, start Timecode, take AVTake) error {
+	if err := take.Validate(); err != nil {
+		return err
+	}
+
+	recognized, now, err := o.recognitionTimeContext(ctx, start)
+	if err != nil {
+		return err
+	}
+	// Only the video transition has been checked, and a leading audio transition is earlier still.
+	first, end := take.span(recognized)
+	for _, tc := range []Timecode{first, end} {
+		if now.Until(tc) > ScheduleHorizon {
+			return &ScheduleHorizonError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Take at %s must be recognized from %s to %s, which is not within %s of %s", start, first, end, ScheduleHorizon, now),
+				},
+			}
+		}
+	}
+
+	follow, err := o.EnquireAudioABFollowVideoABContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmds := take.Commands(recognized)
+	if follow.Enabled {
+		cmds = append(cmds, ScheduledCommand{Time: end, Command: SetAudioABFollowVideoABCommand{Enable: true}})
+	}
+	return o.SendBatchContext(ctx, cmds...)
+}
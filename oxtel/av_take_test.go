@@ -0,0 +1,90 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAVTakeValidate(t *testing.T) {
+	take := AVTake{Destination: OXTEL_MIXER_B, VideoType: OXTEL_TRANSITION_TYPE_X_FADE, VideoDuration: 10}
+	if err := take.Validate(); err != nil {
+		t.Errorf("valid take rejected: %v", err)
+	}
+
+	between := take
+	between.Destination = OXTEL_MIXER_IN_BETWEEN
+	if err := between.Validate(); err == nil {
+		t.Error("expected an in-between destination to be rejected")
+	}
+	wipe := take
+	wipe.VideoType = 0x02
+	if err := wipe.Validate(); err == nil {
+		t.Error("expected an unknown transition type to be rejected")
+	}
+	long := take
+	long.AudioDuration = 1000
+	if err := long.Validate(); err == nil {
+		t.Error("expected a long audio duration to be rejected")
+	}
+}
+
+func TestTakeAV(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.SetTime(10, 0, 0, 0)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+	take := AVTake{
+		Destination:   OXTEL_MIXER_B,
+		VideoType:     OXTEL_TRANSITION_TYPE_X_FADE,
+		VideoDuration: 10,
+		AudioMode:     OXTEL_AUDIO_MIX_MODE_V_FADE,
+		AudioDuration: 20,
+		AudioOffset:   -5,
+	}
+	if err := client.SetAudioABFollowVideoAB(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.TakeAV(Timecode{Hours: 10, Seconds: 5}, take); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 6 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// 7 fields round up to 4 frames before the take is on air, and the audio leads by 5 frames. Audio follow video is
+	// turned back on the frame after the 20 frames of the audio transition.
+	want := []string{"10000421;j510", "10000426;U603", "10000426;U300a", "10000421;jb1", "10000421;jd200014", "10000512;j511"}
+	scheduled := srv.ScheduledCommands()
+	if len(scheduled) != len(want) {
+		t.Fatalf("got scheduled commands %q, want %q", scheduled, want)
+	}
+	for i := range want {
+		if scheduled[i] != want[i] {
+			t.Errorf("scheduled command %d is %q, want %q", i, scheduled[i], want[i])
+		}
+	}
+
+	for time.Now().Before(deadline.Add(time.Second)) {
+		if srv.MixerPosition() == 512 && srv.AudioPosition() == 512 && len(srv.ScheduledCommands()) == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if srv.MixerPosition() != 512 || srv.AudioPosition() != 512 {
+		t.Errorf("video mixer ended at %d and audio mixer at %d", srv.MixerPosition(), srv.AudioPosition())
+	}
+	if follow, err := client.EnquireAudioABFollowVideoAB(); err != nil || !follow.Enabled {
+		t.Errorf("audio follow video was not turned back on: %+v, %v", follow, err)
+	}
+
+	// Audio leading by 200 frames would start before the current time, although the video would not.
+	srv.SetTime(10, 0, 0, 0)
+	take.AudioOffset = -200
+	if err := client.TakeAV(Timecode{Hours: 10, Seconds: 5}, take); err == nil {
+		t.Error("expected a take starting in the past to be rejected")
+	} else if _, ok := err.(*ScheduleHorizonError); !ok {
+		t.Errorf("expected a ScheduleHorizonError, got %T", err)
+	}
+}
@@ -0,0 +1,382 @@
+package oxtel
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// catalogBufferSize is the queue length of the subscription feeding a Catalog.
+const catalogBufferSize = 256
+
+// CatalogEntry is a file known to a Catalog.
+type CatalogEntry struct {
+	// Alias is the folder name alias the file was listed in, such as "$VIDEO".
+	Alias string
+	Name  string
+	Info  ExtendedFileInfoResponse
+}
+
+// CatalogChange describes an entry added to, modified in or deleted from a Catalog. Entry is the entry as it was
+// before a deletion and as it is after an addition or modification.
+type CatalogChange struct {
+	Action OxtelMediaTallies
+	Entry  CatalogEntry
+}
+
+type catalogKey struct {
+	alias string
+	name  string
+}
+
+// Catalog lists the files in one or more folder name aliases along with their extended file information. It is filled
+// by enumerating the aliases when it is created and after every reconnect, and kept current from media tallies in
+// between.
+//
+// Media tallies name a file but not its folder, so an added file causes the aliases to be listed again, and a deleted
+// or modified file applies to every alias holding a file of that name.
+type Catalog struct {
+	o       *Oxtel
+	aliases []string
+	sub     *Subscription
+
+	// syncMu serializes the enquiries made by Refresh and by the tallies.
+	syncMu sync.Mutex
+
+	mu        sync.Mutex
+	entries   map[catalogKey]CatalogEntry
+	callbacks []*catalogCallback
+	queue     []MediaTally
+	closed    bool
+
+	wake       chan struct{}
+	ctx        context.Context
+	cancel     context.CancelFunc
+	unregister func()
+}
+
+// NewCatalog enables the image media tallies, lists the files in the given folder name aliases and keeps the list
+// current until Close or Disconnect is called. Without aliases, the template folder ($VIDEO) is listed.
+func NewCatalog(o *Oxtel, aliases ...string) (*Catalog, error) {
+	return NewCatalogContext(context.Background(), o, aliases...)
+}
+
+// NewCatalogContext is like NewCatalog but uses ctx for cancellation and deadlines.
+func NewCatalogContext(ctx context.Context, o *Oxtel, aliases ...string) (*Catalog, error) {
+	if len(aliases) == 0 {
+		aliases = []string{"$VIDEO"}
+	}
+
+	c := &Catalog{
+		o:       o,
+		aliases: append([]string(nil), aliases...),
+		sub: o.Subscribe(SubscriptionOptions{
+			Types:      []OxtelTallyType{OXTEL_TALLY_MEDIA},
+			BufferSize: catalogBufferSize,
+		}),
+		entries: make(map[catalogKey]CatalogEntry),
+		wake:    make(chan struct{}, 1),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	go c.receive()
+	go c.work()
+
+	err := o.EnableMediaTalliesContext(ctx, MediaTallies{Images: true})
+	if err == nil {
+		err = c.Refresh(ctx)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	c.unregister = o.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		if state != OXTEL_CONNECTION_STATE_CONNECTED {
+			return
+		}
+		go func() {
+			if c.ctx.Err() == nil {
+				c.Refresh(c.ctx)
+			}
+		}()
+	})
+
+	return c, nil
+}
+
+// Entries returns every entry, sorted by alias and then name.
+func (c *Catalog) Entries() []CatalogEntry {
+	return c.filter(func(CatalogEntry) bool { return true })
+}
+
+// Lookup returns the entries for a file name, one for each alias holding it.
+func (c *Catalog) Lookup(name string) []CatalogEntry {
+	return c.filter(func(e CatalogEntry) bool { return e.Name == name })
+}
+
+// WithPrefix returns the entries whose names start with prefix.
+func (c *Catalog) WithPrefix(prefix string) []CatalogEntry {
+	return c.filter(func(e CatalogEntry) bool { return strings.HasPrefix(e.Name, prefix) })
+}
+
+// Glob returns the entries whose names match pattern, using the syntax of path.Match.
+func (c *Catalog) Glob(pattern string) ([]CatalogEntry, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return c.filter(func(e CatalogEntry) bool {
+		ok, _ := path.Match(pattern, e.Name)
+		return ok
+	}), nil
+}
+
+// OnChange registers a callback that is invoked for every entry added, modified or deleted, including by Refresh, and
+// returns a function that unregisters it.
+//
+// Callbacks are invoked synchronously from the goroutine that follows the media tallies. They may make enquiries, but
+// the catalog is not updated until they return.
+func (c *Catalog) OnChange(callback func(change CatalogChange)) (unregister func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb := &catalogCallback{fn: callback}
+	c.callbacks = append(c.callbacks, cb)
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, registered := range c.callbacks {
+			if registered == cb {
+				c.callbacks = append(c.callbacks[:i], c.callbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// catalogCallback is a callback registered with OnChange. It is held by pointer so that it can be unregistered.
+type catalogCallback struct {
+	fn func(change CatalogChange)
+}
+
+// Refresh lists the aliases again and replaces every entry with the results of new enquiries.
+func (c *Catalog) Refresh(ctx context.Context) error {
+	return c.sync(ctx, true)
+}
+
+// Close stops the Catalog from following the engine. The media tallies it enabled are left enabled.
+func (c *Catalog) Close() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	if c.unregister != nil {
+		c.unregister()
+	}
+	c.cancel()
+	c.sub.Close()
+}
+
+func (c *Catalog) filter(match func(CatalogEntry) bool) []CatalogEntry {
+	c.mu.Lock()
+	var out []CatalogEntry
+	for _, e := range c.entries {
+		if match(e) {
+			out = append(out, e)
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Alias != out[j].Alias {
+			return out[i].Alias < out[j].Alias
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// sync lists the aliases and brings the entries in line. The information of files already known is only enquired
+// again when all is set.
+func (c *Catalog) sync(ctx context.Context, all bool) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	c.mu.Lock()
+	known := make(map[catalogKey]CatalogEntry, len(c.entries))
+	for k, e := range c.entries {
+		known[k] = e
+	}
+	c.mu.Unlock()
+
+	listed := make(map[catalogKey]CatalogEntry)
+	for _, alias := range c.aliases {
+		names, err := c.o.ListFilesContext(ctx, &alias)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			key := catalogKey{alias: alias, name: name}
+			if e, ok := known[key]; ok && !all {
+				listed[key] = e
+				continue
+			}
+			info, err := c.o.EnquireExtendedFileInformationContext(ctx, qualifiedName(alias, name))
+			if err != nil {
+				return err
+			}
+			listed[key] = CatalogEntry{Alias: alias, Name: name, Info: info}
+		}
+	}
+
+	var changes []CatalogChange
+	for k, e := range known {
+		if _, ok := listed[k]; !ok {
+			changes = append(changes, CatalogChange{Action: OXTEL_MEDIA_DELETED, Entry: e})
+		}
+	}
+	for k, e := range listed {
+		if old, ok := known[k]; !ok {
+			changes = append(changes, CatalogChange{Action: OXTEL_MEDIA_ADDED, Entry: e})
+		} else if old != e {
+			changes = append(changes, CatalogChange{Action: OXTEL_MEDIA_MODIFIED, Entry: e})
+		}
+	}
+
+	c.mu.Lock()
+	c.entries = listed
+	c.mu.Unlock()
+
+	c.notify(changes)
+	return nil
+}
+
+// modified enquires the information of every entry with the given name again.
+func (c *Catalog) modified(ctx context.Context, name string) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	var changes []CatalogChange
+	for _, e := range c.Lookup(name) {
+		info, err := c.o.EnquireExtendedFileInformationContext(ctx, qualifiedName(e.Alias, e.Name))
+		if err != nil {
+			return err
+		}
+		e.Info = info
+		changes = append(changes, CatalogChange{Action: OXTEL_MEDIA_MODIFIED, Entry: e})
+	}
+
+	c.mu.Lock()
+	for _, change := range changes {
+		c.entries[catalogKey{alias: change.Entry.Alias, name: change.Entry.Name}] = change.Entry
+	}
+	c.mu.Unlock()
+
+	c.notify(changes)
+	return nil
+}
+
+// deleted removes every entry with the given name.
+func (c *Catalog) deleted(name string) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	var changes []CatalogChange
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if e.Name == name {
+			delete(c.entries, k)
+			changes = append(changes, CatalogChange{Action: OXTEL_MEDIA_DELETED, Entry: e})
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(changes)
+}
+
+func (c *Catalog) notify(changes []CatalogChange) {
+	if len(changes) == 0 {
+		return
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Entry.Alias != changes[j].Entry.Alias {
+			return changes[i].Entry.Alias < changes[j].Entry.Alias
+		}
+		return changes[i].Entry.Name < changes[j].Entry.Name
+	})
+
+	c.mu.Lock()
+	callbacks := make([]*catalogCallback, len(c.callbacks))
+	copy(callbacks, c.callbacks)
+	c.mu.Unlock()
+
+	for _, change := range changes {
+		for _, callback := range callbacks {
+			callback.fn(change)
+		}
+	}
+}
+
+// qualifiedName returns the name a file listed in alias is enquired by. Files in the template folder are enquired by
+// their bare name, as media tallies name them.
+func qualifiedName(alias string, name string) string {
+	if alias == "$VIDEO" {
+		return name
+	}
+	return alias + `\` + name
+}
+
+// receive queues media tallies for work, so that the tally subscription never waits on an enquiry. The work stops
+// once the subscription is closed.
+func (c *Catalog) receive() {
+	defer c.cancel()
+
+	for tally := range c.sub.C {
+		t, ok := tally.(MediaTally)
+		if !ok {
+			continue
+		}
+		c.mu.Lock()
+		c.queue = append(c.queue, t)
+		c.mu.Unlock()
+
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// work applies the queued media tallies. A tally that fails to apply is dropped; the next Refresh corrects the
+// entries.
+func (c *Catalog) work() {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.wake:
+		}
+
+		for {
+			c.mu.Lock()
+			if len(c.queue) == 0 || c.closed {
+				c.mu.Unlock()
+				break
+			}
+			t := c.queue[0]
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+
+			switch t.Action {
+			case OXTEL_MEDIA_ADDED:
+				c.sync(c.ctx, false)
+			case OXTEL_MEDIA_MODIFIED:
+				c.modified(c.ctx, t.Filename)
+			case OXTEL_MEDIA_DELETED:
+				c.deleted(t.Filename)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCatalog(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+	srv.AddFile("bug.html")
+	srv.AddFileTo("$LOGOS", "logo.png")
+
+	catalog, err := NewCatalog(client, "$VIDEO", "$LOGOS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer catalog.Close()
+
+	entries := catalog.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if e := entries[0]; e.Alias != "$LOGOS" || e.Name != "logo.png" || !e.Info.Exists {
+		t.Errorf("first entry is %+v", e)
+	}
+	// Files outside the template folder are enquired by their alias.
+	qualified := false
+	for _, cmd := range srv.Commands() {
+		qualified = qualified || cmd == `R6$LOGOS\logo.png`
+	}
+	if !qualified {
+		t.Errorf("logo.png was not enquired in $LOGOS: %q", srv.Commands())
+	}
+	if got := catalog.WithPrefix("lo"); len(got) != 2 {
+		t.Errorf("got %d entries starting with lo, want 2", len(got))
+	}
+	if got, err := catalog.Glob("*.html"); err != nil || len(got) != 2 {
+		t.Errorf("got %d entries matching *.html, want 2 (%v)", len(got), err)
+	}
+	if _, err := catalog.Glob("["); err == nil {
+		t.Error("expected a malformed pattern to be rejected")
+	}
+
+	changes := make(chan CatalogChange, 16)
+	unregister := catalog.OnChange(func(change CatalogChange) { changes <- change })
+	expect := func(action OxtelMediaTallies, alias string, name string) {
+		t.Helper()
+		select {
+		case change := <-changes:
+			if change.Action != action || change.Entry.Alias != alias || change.Entry.Name != name {
+				t.Fatalf("got change %+v, want %d %s %s", change, action, alias, name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %d %s %s", action, alias, name)
+		}
+	}
+
+	srv.AddFileTo("$LOGOS", "sponsor.png")
+	expect(OXTEL_MEDIA_ADDED, "$LOGOS", "sponsor.png")
+	srv.ModifyFile("bug.html")
+	expect(OXTEL_MEDIA_MODIFIED, "$VIDEO", "bug.html")
+	srv.RemoveFile("lower.html")
+	expect(OXTEL_MEDIA_DELETED, "$VIDEO", "lower.html")
+
+	if got := catalog.Lookup("lower.html"); len(got) != 0 {
+		t.Errorf("deleted file is still listed: %+v", got)
+	}
+	if got := catalog.Lookup("sponsor.png"); len(got) != 1 {
+		t.Errorf("added file is not listed")
+	}
+
+	unregister()
+	srv.AddFile("late.html")
+	deadline := time.Now().Add(5 * time.Second)
+	for len(catalog.Lookup("late.html")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case change := <-changes:
+		t.Errorf("got change %+v after unregistering", change)
+	default:
+	}
+}
+
+func TestCatalogCloseUnregisters(t *testing.T) {
+	_, client := connectTallyClient(t)
+
+	catalog, err := NewCatalog(client, "$VIDEO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := stateCallbackCount(client); n != 1 {
+		t.Fatalf("%d connection state callbacks registered", n)
+	}
+
+	catalog.Close()
+	if n := stateCallbackCount(client); n != 0 {
+		t.Fatalf("%d connection state callbacks left after Close", n)
+	}
+}
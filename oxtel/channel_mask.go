@@ -0,0 +1,165 @@
+package oxtel
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ChannelMask is a set of the 16 audio channels, numbered from 1. Channel n is bit n-1, as in the 16 bit channel mask
+// of the protocol, so 0x00FF is channels 1-8.
+type ChannelMask uint16
+
+// NoChannels and AllChannels are the empty and the full ChannelMask.
+const (
+	NoChannels  ChannelMask = 0x0000
+	AllChannels ChannelMask = 0xFFFF
+)
+
+// NewChannelMask returns a ChannelMask of the given channels. An InvalidAudioChannelError is returned if a channel is
+// not between 1 and 16.
+func NewChannelMask(channels ...int) (ChannelMask, error) {
+	var m ChannelMask
+	for _, ch := range channels {
+		if err := validateChannel(ch); err != nil {
+			return NoChannels, err
+		}
+		m |= 1 << (ch - 1)
+	}
+	return m, nil
+}
+
+// ChannelRange returns a ChannelMask of the channels from first to last inclusive.
+func ChannelRange(first int, last int) (ChannelMask, error) {
+	if err := validateChannel(first); err != nil {
+		return NoChannels, err
+	}
+	if err := validateChannel(last); err != nil {
+		return NoChannels, err
+	}
+	if first > last {
+		return NoChannels, &InvalidAudioChannelError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Channel range %d-%d is reversed", first, last),
+			},
+		}
+	}
+	return AllChannels >> (16 - (last - first + 1)) << (first - 1), nil
+}
+
+// ParseChannelMask parses a ChannelMask written as by String, such as "1-4,7", or in hexadecimal with a "0x" prefix,
+// such as "0x00FF". An empty string is NoChannels.
+func ParseChannelMask(s string) (ChannelMask, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return NoChannels, nil
+	}
+	if len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		n, err := strconv.ParseUint(s[2:], 16, 16)
+		if err != nil {
+			return NoChannels, invalidChannelMask(s)
+		}
+		return ChannelMask(n), nil
+	}
+
+	var m ChannelMask
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		a, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return NoChannels, invalidChannelMask(s)
+		}
+		b := a
+		if isRange {
+			if b, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+				return NoChannels, invalidChannelMask(s)
+			}
+		}
+		r, err := ChannelRange(a, b)
+		if err != nil {
+			return NoChannels, err
+		}
+		m |= r
+	}
+	return m, nil
+}
+
+// String returns the channels in ascending order, with runs of consecutive channels written as ranges, such as
+// "1-4,7". NoChannels is the empty string.
+func (m ChannelMask) String() string {
+	var parts []string
+	channels := m.Channels()
+	for i := 0; i < len(channels); {
+		j := i
+		for j+1 < len(channels) && channels[j+1] == channels[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(channels[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", channels[i], channels[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Has reports whether the channel is in the mask.
+func (m ChannelMask) Has(channel int) bool {
+	return channel >= 1 && channel <= 16 && m&(1<<(channel-1)) != 0
+}
+
+// Union returns the channels in either mask.
+func (m ChannelMask) Union(other ChannelMask) ChannelMask {
+	return m | other
+}
+
+// Intersection returns the channels in both masks.
+func (m ChannelMask) Intersection(other ChannelMask) ChannelMask {
+	return m & other
+}
+
+// Difference returns the channels in m that are not in other.
+func (m ChannelMask) Difference(other ChannelMask) ChannelMask {
+	return m &^ other
+}
+
+// Len returns the number of channels in the mask.
+func (m ChannelMask) Len() int {
+	return bits.OnesCount16(uint16(m))
+}
+
+// Channels returns the channels in the mask in ascending order.
+func (m ChannelMask) Channels() []int {
+	channels := make([]int, 0, m.Len())
+	for ch := 1; ch <= 16; ch++ {
+		if m.Has(ch) {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+func validateChannel(channel int) error {
+	if channel < 1 || channel > 16 {
+		return &InvalidAudioChannelError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Channel %d must be between 1 and 16", channel),
+			},
+		}
+	}
+	return nil
+}
+
+func invalidChannelMask(s string) error {
+	return &InvalidAudioChannelError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf("Invalid channel mask %q", s),
+		},
+	}
+}
+
+func buildChannelMask(mask ChannelMask) string {
+	return fmt.Sprintf("%04X", uint16(mask))
+}
@@ -0,0 +1,46 @@
+package oxtel
+
+import "testing"
+
+func TestChannelMask(t *testing.T) {
+	mask, err := ParseChannelMask("1-4, 7,16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mask != 0x804F || mask.String() != "1-4,7,16" || mask.Len() != 6 {
+		t.Errorf("parsed %04X, %q", uint16(mask), mask)
+	}
+	if !mask.Has(16) || mask.Has(5) || mask.Has(17) {
+		t.Error("wrong membership")
+	}
+
+	// Channel 16 is the sign bit of an int16 and must still encode as an unsigned mask.
+	sixteen, _ := NewChannelMask(16)
+	if got := buildChannelMask(sixteen); got != "8000" {
+		t.Errorf("channel 16 encodes as %s", got)
+	}
+	if got := SetAudioGain_AsString(OXTEL_AUDIO_OUTPUT_MIXER_OUTPUT, AllChannels, nil); got != "jAG10FFFF" {
+		t.Errorf("all channels encode as %s", got)
+	}
+
+	hex, err := ParseChannelMask("0x00FF")
+	if err != nil || hex != MakeEightChannelMask() {
+		t.Errorf("parsed 0x00FF as %04X, %v", uint16(hex), err)
+	}
+	high, _ := ChannelRange(5, 12)
+	if got := hex.Intersection(high).String(); got != "5-8" {
+		t.Errorf("intersection is %q", got)
+	}
+	if got := hex.Union(high).String(); got != "1-12" {
+		t.Errorf("union is %q", got)
+	}
+	if got := hex.Difference(high).Channels(); len(got) != 4 || got[0] != 1 || got[3] != 4 {
+		t.Errorf("difference is %v", got)
+	}
+
+	for _, bad := range []string{"0", "17", "4-2", "a", "0xFFFFF"} {
+		if _, err := ParseChannelMask(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
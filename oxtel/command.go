@@ -0,0 +1,157 @@
+package oxtel
+
+import "context"
+
+// Command is a single Oxtel protocol command.
+//
+// Every command and enquiry in this package has a Command type named after its method with a Command suffix, e.g.
+// FadeKeyerCommand for FadeKeyer. Commands can be sent with Send, sent together with SendBatch or scheduled with
+// Schedule.
+type Command interface {
+	// Encode returns the command string, without protocol escaping or the ':' terminator.
+	Encode() string
+
+	// Validate reports whether the parameters of the command are within the ranges accepted by the engine.
+	Validate() error
+}
+
+// Enquiry is a Command the engine answers with a response starting with ResponsePrefix.
+type Enquiry interface {
+	Command
+
+	// ResponsePrefix returns the prefix echoed at the start of the response.
+	ResponsePrefix() string
+}
+
+// responseMatcher is implemented by the Enquiries whose ResponsePrefix also starts a tally, so that a tally broadcast
+// while the enquiry is waiting is not taken as its response.
+type responseMatcher interface {
+	// matchesResponse reports whether data, the message following the response prefix without its terminator, is
+	// the response to the enquiry.
+	matchesResponse(data string) bool
+}
+
+// RawCommand is a command string that has already been encoded, such as one returned by an _AsString function.
+// It is sent as is and never fails validation.
+type RawCommand string
+
+func (c RawCommand) Encode() string {
+	return string(c)
+}
+
+func (c RawCommand) Validate() error {
+	return nil
+}
+
+// EncodeCommand validates a command and returns its command string, as the _AsString functions do without validation.
+//
+// For use with scheduled commands.
+func EncodeCommand(cmd Command) (string, error) {
+	if err := cmd.Validate(); err != nil {
+		return "", err
+	}
+	return cmd.Encode(), nil
+}
+
+// Send validates and sends a command.
+func (o *Oxtel) Send(cmd Command) error {
+	return o.SendContext(context.Background(), cmd)
+}
+
+// SendContext is like Send but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SendContext(ctx context.Context, cmd Command) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	o.recordArmedState(cmd)
+
+	if err := o.sendCommandContext(ctx, cmd.Encode()); err != nil {
+		return err
+	}
+	o.recordScheduled(cmd)
+	return nil
+}
+
+// SendBatch validates every command and, only if all of them are valid, sends them in a single write so that no
+// other command from this client is interleaved between them.
+func (o *Oxtel) SendBatch(cmds ...Command) error {
+	return o.SendBatchContext(context.Background(), cmds...)
+}
+
+// SendBatchContext is like SendBatch but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SendBatchContext(ctx context.Context, cmds ...Command) error {
+	if err := o.sendBatch(ctx, cmds...); err != nil {
+		return err
+	}
+	o.recordScheduled(cmds...)
+	return nil
+}
+
+// sendBatch is SendBatchContext without updating the schedule ledger.
+func (o *Oxtel) sendBatch(ctx context.Context, cmds ...Command) error {
+	encoded := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		if err := cmd.Validate(); err != nil {
+			return err
+		}
+		encoded = append(encoded, cmd.Encode())
+	}
+	if len(encoded) == 0 {
+		return nil
+	}
+
+	for _, cmd := range cmds {
+		o.recordArmedState(cmd)
+	}
+
+	o.writeMu.Lock()
+	defer o.writeMu.Unlock()
+
+	return o.write(ctx, encoded...)
+}
+
+// Schedule validates a command and adds it to the engine scheduler, to be executed at the given time.
+//
+// See AddScheduledCommand for how the time relates to the output.
+func (o *Oxtel) Schedule(tc Timecode, cmd Command) error {
+	return o.ScheduleContext(context.Background(), tc, cmd)
+}
+
+// ScheduleContext is like Schedule but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ScheduleContext(ctx context.Context, tc Timecode, cmd Command) error {
+	return o.SendContext(ctx, ScheduledCommand{Time: tc, Command: cmd})
+}
+
+// ScheduleBatch validates every command and, only if all of them are valid, schedules them all at the given time in
+// a single write.
+func (o *Oxtel) ScheduleBatch(tc Timecode, cmds ...Command) error {
+	return o.ScheduleBatchContext(context.Background(), tc, cmds...)
+}
+
+// ScheduleBatchContext is like ScheduleBatch but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ScheduleBatchContext(ctx context.Context, tc Timecode, cmds ...Command) error {
+	scheduled := make([]Command, len(cmds))
+	for i, cmd := range cmds {
+		scheduled[i] = ScheduledCommand{Time: tc, Command: cmd}
+	}
+
+	return o.SendBatchContext(ctx, scheduled...)
+}
+
+// Enquire validates and sends an enquiry and returns the data of its response, following the response prefix.
+func (o *Oxtel) Enquire(q Enquiry) (string, error) {
+	return o.EnquireContext(context.Background(), q)
+}
+
+// EnquireContext is like Enquire but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireContext(ctx context.Context, q Enquiry) (string, error) {
+	if err := q.Validate(); err != nil {
+		return "", err
+	}
+
+	var matches func(data string) bool
+	if m, ok := q.(responseMatcher); ok {
+		matches = m.matchesResponse
+	}
+	return o.sendCommandExpectResponseContext(ctx, q.ResponsePrefix(), matches, q.Encode())
+}
@@ -0,0 +1,113 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestAsStringMatchesEncode(t *testing.T) {
+	rate := uint16(0x20)
+	gain := int8(-6)
+
+	cases := []struct {
+		asString string
+		cmd      Command
+	}{
+		{FadeKeyer_AsString(1, OXTEL_DIR_UP, &rate), FadeKeyerCommand{Layer: 1, Direction: OXTEL_DIR_UP, Rate: &rate}},
+		{SetAudioGain_AsString(0, MakeTwoChannelMask(), &gain), SetAudioGainCommand{Output: 0, ChannelMask: MakeTwoChannelMask(), Gain: &gain}},
+		{LoadImage_AsString(2, "lower.html"), LoadImageCommand{Layer: 2, TemplateName: "lower.html"}},
+		{EnquireCurrentTime_AsString(), EnquireCurrentTimeCommand{}},
+	}
+	for _, c := range cases {
+		if c.asString != c.cmd.Encode() {
+			t.Errorf("%T: _AsString returned %q, Encode returned %q", c.cmd, c.asString, c.cmd.Encode())
+		}
+	}
+
+	if got := FadeKeyer_AsString(1, OXTEL_DIR_UP, &rate); got != "11 1 20" {
+		t.Errorf("FadeKeyer encoded the rate as %q", got)
+	}
+}
+
+func TestEncodeCommandValidates(t *testing.T) {
+	if _, err := EncodeCommand(SetAudioLoudnessCommand{FirstProgram: AudioProgram{Channel1: 16}}); err == nil {
+		t.Fatal("expected channel 16 to be rejected")
+	} else if _, ok := err.(*InvalidAudioProfileError); !ok {
+		t.Fatalf("expected an InvalidAudioProfileError, got %v", err)
+	}
+
+	cmd := SetAudioLoudnessCommand{FirstProgram: AudioProgram{Channel1: 2}}
+	got, err := EncodeCommand(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cmd.Encode() {
+		t.Errorf("EncodeCommand returned %q, Encode returned %q", got, cmd.Encode())
+	}
+}
+
+func TestSendBatchRejectsInvalidCommands(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	err := client.SendBatch(
+		CutKeyerCommand{Layer: 0, Direction: OXTEL_DIR_UP},
+		SetDolbyEncoderProfileCommand{Profile: 9},
+	)
+	if _, ok := err.(*InvalidDolbyProfileError); !ok {
+		t.Fatalf("expected an InvalidDolbyProfileError, got %v", err)
+	}
+
+	if err := client.SendBatch(
+		CutKeyerCommand{Layer: 0, Direction: OXTEL_DIR_UP},
+		CutKeyerCommand{Layer: 1, Direction: OXTEL_DIR_UP},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireMixMode(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmds := srv.Commands()
+	if len(cmds) != 3 || cmds[0] != "30 1" || cmds[1] != "31 1" {
+		t.Fatalf("unexpected commands %q", cmds)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetTime(10, 0, 0, 0)
+	tc := Timecode{Hours: 11, Minutes: 30, Seconds: 0, Frames: 12}
+	if err := client.ScheduleBatch(tc, CutToBCommand{}, LoadImageCommand{Layer: 0, TemplateName: "a.html"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Schedule(Timecode{Hours: 24}, CutToACommand{}); err == nil {
+		t.Fatal("expected an out of range timecode to be rejected")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	scheduled := srv.ScheduledCommands()
+	if len(scheduled) != 2 || scheduled[0] != "11300012;U1" || scheduled[1] != "11300012;R00a.html" {
+		t.Fatalf("unexpected scheduled commands %q", scheduled)
+	}
+}
@@ -0,0 +1,244 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestConcurrentEnquiries(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	const layers = 8
+	for layer := 0; layer < layers; layer++ {
+		name := fmt.Sprintf("layer%d.html", layer)
+		srv.AddFile(name)
+		if err := client.LoadImage(OxtelLayer(layer), name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for layer := uint8(0); layer < layers; layer++ {
+		for srv.LoadedTemplate(layer) != fmt.Sprintf("layer%d.html", layer) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 1000)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				layer := OxtelLayer((g + i) % layers)
+				resp, err := client.EnquireLoadImage(layer)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if resp.Layer != layer || resp.Filename != fmt.Sprintf("layer%d.html", layer) {
+					errs <- fmt.Errorf("layer %d got the response for layer %d (%s)", layer, resp.Layer, resp.Filename)
+					return
+				}
+
+				if _, err := client.EnquireMixMode(); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := client.EnquireCurrentTime(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestConcurrentCommandsAndEnquiries(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.EnableVideoTallies(true); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for range client.Unsolicited {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if err := client.CutKeyer(OxtelLayer(g), OxtelDirection(i%2)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				resp, err := client.EnquireImagePosition(OxtelLayer(g))
+				if err != nil {
+					errs <- err
+					return
+				}
+				if resp.Layer != OxtelLayer(g) {
+					errs <- fmt.Errorf("layer %d got the response for layer %d", g, resp.Layer)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestTimedOutEnquiryIsForgotten(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.Handle("R0", func(cmd string) []string { return nil })
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.EnquireLoadImageContext(ctx, 0); err == nil {
+		t.Fatal("expected a timeout")
+	}
+
+	client.mu.Lock()
+	pending := len(client.pending)
+	client.mu.Unlock()
+	if pending != 0 {
+		t.Fatalf("%d requests still pending after timeout", pending)
+	}
+
+	srv.Handle("R0", nil)
+	resp, err := client.EnquireLoadImage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Layer != 1 {
+		t.Fatalf("got the response for layer %d", resp.Layer)
+	}
+}
+
+func TestDisconnectFailsPendingEnquiries(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.Handle("R0", func(cmd string) []string { return nil })
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.EnquireLoadImage(0)
+		done <- err
+	}()
+
+	for len(srv.Commands()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	client.Disconnect()
+
+	select {
+	case err := <-done:
+		if _, ok := err.(*NotConnectedError); !ok {
+			t.Fatalf("expected a NotConnectedError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending enquiry was not released by Disconnect")
+	}
+}
+
+func TestEnquiryIgnoresTallyWithSamePrefix(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	// Each enquiry is answered with a tally of the same prefix first, as if it had been broadcast just before.
+	for prefix, lines := range map[string][]string{
+		"Y6":   {"Y6000000000", "Y61"},
+		"YS":   {"YS21", "YS1"},
+		"YB":   {"YB0000011a.html", "YB000001"},
+		"hXS":  {"hXSY0001010200", "hXS0001010200"},
+		"hXDC": {"hXDCY000100,eth1", "hXDC000100,eth0"},
+	} {
+		lines := lines
+		srv.Handle(prefix, func(cmd string) []string { return lines })
+	}
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+	sub := client.Subscribe(SubscriptionOptions{BufferSize: 8})
+	defer sub.Close()
+
+	if on, err := client.EnquireVideoTallies(); err != nil || !on {
+		t.Errorf("EnquireVideoTallies returned %v, %v", on, err)
+	}
+	if on, err := client.EnquirePlayStateTally(); err != nil || !on {
+		t.Errorf("EnquirePlayStateTally returned %v, %v", on, err)
+	}
+	if tallies, err := client.EnquireMediaTallies(); err != nil || !tallies.Images {
+		t.Errorf("EnquireMediaTallies returned %+v, %v", tallies, err)
+	}
+	if source, err := client.EnquireExternalIOSource(0, 1); err != nil || source.IOId != 1 {
+		t.Errorf("EnquireExternalIOSource returned %+v, %v", source, err)
+	}
+	if config, err := client.EnquireExternalIODynamicConfiguration(0, 1, OXTEL_EXT_IO_TYPE_SDI); err != nil || config.LocalInterface == nil || *config.LocalInterface != "eth0" {
+		t.Errorf("EnquireExternalIODynamicConfiguration returned %+v, %v", config, err)
+	}
+
+	want := []string{"VideoTally", "PlayStateTally", "MediaTally", "ExternalIOSourceChangedTally", "ExternalIODynamicConfigChangedTally"}
+	for _, name := range want {
+		select {
+		case tally := <-sub.C:
+			if got := fmt.Sprintf("%T", tally); got != "oxtel."+name {
+				t.Errorf("got a %s, want a %s", got, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for a %s", name)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package oxtel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestContextDeadline(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.Handle("R0", func(cmd string) []string { return nil })
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.EnquireLoadImageContext(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected an error wrapping context.DeadlineExceeded, got %v", err)
+	}
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a TimeoutError, got %T", err)
+	}
+
+	if _, err := client.EnquirePreloadImageContext(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.Handle("R0", func(cmd string) []string { return nil })
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	if _, err := client.EnquireLoadImageContext(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if err := client.CutKeyerContext(ctx, 0, OXTEL_DIR_DOWN); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled sending on a cancelled context, got %v", err)
+	}
+}
@@ -1,6 +1,41 @@
 package oxtel
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultMaxTextPacketSize is the longest UpdateTextField or UpdatePreloadedTextField command sent as a single packet
+// unless SetMaxTextPacketSize is called. The size is of the escaped command, without the terminator.
+const DefaultMaxTextPacketSize = 256
+
+// minTextPacketSize leaves room for the longest command header and the longest escaped character.
+const minTextPacketSize = 16
+
+// UpdateTextFieldCommand is the Command sent by UpdateTextField.
+type UpdateTextFieldCommand struct {
+	Layer OxtelLayer
+	Field uint8
+	Flags OxtelUpdateTextFieldFlag
+	Text  string
+}
+
+func (c UpdateTextFieldCommand) Encode() string {
+	return fmt.Sprintf("Z0%x%02x%x%s", c.Layer, c.Field, c.Flags, c.Text)
+}
+
+func (c UpdateTextFieldCommand) Validate() error {
+	if c.Field > 254 {
+		return &InvalidFieldError{
+			BaseError: BaseError{
+				Message: "Field must be less than 255",
+			},
+		}
+	}
+
+	return nil
+}
 
 // UpdateTextField updates the text in the specified text field.
 //
@@ -12,25 +47,50 @@ import "fmt"
 //
 // If the OXTEL_UPDATE_TEXT_FIELD_APPEND flag is set, then the specified text string is appended to the existing text.
 // This allows for long text strings to be defined over several command packets.
+//
+// Text too long for a single packet is split automatically, never inside a character or an escape sequence. The
+// first packet is sent with flags less OXTEL_UPDATE_TEXT_FIELD_RENDER, the rest with OXTEL_UPDATE_TEXT_FIELD_APPEND,
+// and only the last with OXTEL_UPDATE_TEXT_FIELD_RENDER if it is in flags. See SetMaxTextPacketSize.
 func (o *Oxtel) UpdateTextField(layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) error {
-	if field > 254 {
-		return &InvalidFieldError{
-			BaseError: BaseError{
-				Message: "Field must be less than 255",
-			},
-		}
-	}
-
-	msg := fmt.Sprintf("Z0%x%02x%x%s", layer, field, flags, text)
+	return o.UpdateTextFieldContext(context.Background(), layer, field, flags, text)
+}
 
-	return o.sendCommand(msg)
+// UpdateTextFieldContext is like UpdateTextField but uses ctx for cancellation and deadlines.
+func (o *Oxtel) UpdateTextFieldContext(ctx context.Context, layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) error {
+	return o.SendBatchContext(ctx, o.splitText(UpdateTextFieldCommand{Layer: layer, Field: field, Flags: flags, Text: text})...)
 }
 
 // UpdateTextField_AsString returns the command string used to update the text in the specified text field.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an UpdateTextFieldCommand instead.
 func UpdateTextField_AsString(layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) string {
-	return fmt.Sprintf("Z0%x%02x%x%s", layer, field, flags, text)
+	return UpdateTextFieldCommand{Layer: layer, Field: field, Flags: flags, Text: text}.Encode()
+}
+
+// UpdatePreloadedTextFieldCommand is the Command sent by UpdatePreloadedTextField.
+type UpdatePreloadedTextFieldCommand struct {
+	Layer OxtelLayer
+	Field uint8
+	Flags OxtelUpdateTextFieldFlag
+	Text  string
+}
+
+func (c UpdatePreloadedTextFieldCommand) Encode() string {
+	return fmt.Sprintf("hZ0%x%02x%x%s", c.Layer, c.Field, c.Flags, c.Text)
+}
+
+func (c UpdatePreloadedTextFieldCommand) Validate() error {
+	if c.Field > 254 {
+		return &InvalidFieldError{
+			BaseError: BaseError{
+				Message: "Field must be less than 255",
+			},
+		}
+	}
+
+	return nil
 }
 
 // UpdatePreloadedTextField updates the text in the specified text field for a preloaded template.
@@ -43,49 +103,175 @@ func UpdateTextField_AsString(layer OxtelLayer, field uint8, flags OxtelUpdateTe
 //
 // If the OXTEL_UPDATE_TEXT_FIELD_APPEND flag is set, then the specified text string is appended to the existing text.
 // This allows for long text strings to be defined over several command packets.
+//
+// Text too long for a single packet is split automatically, never inside a character or an escape sequence. The
+// first packet is sent with flags less OXTEL_UPDATE_TEXT_FIELD_RENDER, the rest with OXTEL_UPDATE_TEXT_FIELD_APPEND,
+// and only the last with OXTEL_UPDATE_TEXT_FIELD_RENDER if it is in flags. See SetMaxTextPacketSize.
 func (o *Oxtel) UpdatePreloadedTextField(layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) error {
-	if field > 254 {
-		return &InvalidFieldError{
-			BaseError: BaseError{
-				Message: "Field must be less than 255",
-			},
-		}
-	}
-
-	msg := fmt.Sprintf("hZ0%x%02x%x%s", layer, field, flags, text)
+	return o.UpdatePreloadedTextFieldContext(context.Background(), layer, field, flags, text)
+}
 
-	return o.sendCommand(msg)
+// UpdatePreloadedTextFieldContext is like UpdatePreloadedTextField but uses ctx for cancellation and deadlines.
+func (o *Oxtel) UpdatePreloadedTextFieldContext(ctx context.Context, layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) error {
+	return o.SendBatchContext(ctx, o.splitText(UpdatePreloadedTextFieldCommand{Layer: layer, Field: field, Flags: flags, Text: text})...)
 }
 
 // UpdatePreloadedTextField_AsString returns the command string used to update the text in the specified text field for
 // a preloaded template.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an UpdatePreloadedTextFieldCommand instead.
 func UpdatePreloadedTextField_AsString(layer OxtelLayer, field uint8, flags OxtelUpdateTextFieldFlag, text string) string {
-	return fmt.Sprintf("hZ0%x%02x%x%s", layer, field, flags, text)
+	return UpdatePreloadedTextFieldCommand{Layer: layer, Field: field, Flags: flags, Text: text}.Encode()
+}
+
+// SetMaxTextPacketSize sets the longest UpdateTextField or UpdatePreloadedTextField command, once escaped, sent as a
+// single packet. Longer text is split over several packets. It must be at least 16.
+func (o *Oxtel) SetMaxTextPacketSize(size int) error {
+	if size < minTextPacketSize {
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Text packet size must be at least %d", minTextPacketSize),
+			},
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.textPacketSize = size
+	return nil
+}
+
+// splitText splits the text of UpdateTextField and UpdatePreloadedTextField commands that do not fit in a packet.
+// Other commands are returned unchanged.
+func (o *Oxtel) splitText(cmds ...Command) []Command {
+	o.mu.Lock()
+	size := o.textPacketSize
+	o.mu.Unlock()
+	if size == 0 {
+		size = DefaultMaxTextPacketSize
+	}
+
+	var out []Command
+	for _, cmd := range cmds {
+		switch c := cmd.(type) {
+		case UpdateTextFieldCommand:
+			header := len(escapeCommand(UpdateTextFieldCommand{Layer: c.Layer, Field: c.Field}.Encode()))
+			for _, chunk := range splitTextChunks(c.Flags, c.Text, size-header) {
+				out = append(out, UpdateTextFieldCommand{Layer: c.Layer, Field: c.Field, Flags: chunk.flags, Text: chunk.text})
+			}
+		case UpdatePreloadedTextFieldCommand:
+			header := len(escapeCommand(UpdatePreloadedTextFieldCommand{Layer: c.Layer, Field: c.Field}.Encode()))
+			for _, chunk := range splitTextChunks(c.Flags, c.Text, size-header) {
+				out = append(out, UpdatePreloadedTextFieldCommand{Layer: c.Layer, Field: c.Field, Flags: chunk.flags, Text: chunk.text})
+			}
+		default:
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+type textChunk struct {
+	flags OxtelUpdateTextFieldFlag
+	text  string
+}
+
+// splitTextChunks splits text into chunks of at most limit bytes once escaped. Characters are never split, and as
+// escaping replaces single characters, neither are escape sequences. Chunks neither start nor end with a space where
+// it can be helped, as whitespace around a packet may be trimmed.
+func splitTextChunks(flags OxtelUpdateTextFieldFlag, text string, limit int) []textChunk {
+	var chunks []textChunk
+	start, n := 0, 0
+	for i := 0; i < len(text); {
+		r, width := utf8.DecodeRuneInString(text[i:])
+		escaped := width
+		if r < utf8.RuneSelf {
+			escaped = len(escapeCommand(text[i : i+1]))
+		}
+		if n+escaped > limit && i > start {
+			split := splitPoint(text, start, i)
+			chunks = append(chunks, textChunk{text: text[start:split]})
+			start, n, i = split, 0, split
+			continue
+		}
+		n += escaped
+		i += width
+	}
+	chunks = append(chunks, textChunk{text: text[start:]})
+
+	chunks[0].flags = flags &^ OXTEL_UPDATE_TEXT_FIELD_RENDER
+	for i := 1; i < len(chunks); i++ {
+		chunks[i].flags = OXTEL_UPDATE_TEXT_FIELD_APPEND
+	}
+	chunks[len(chunks)-1].flags |= flags & OXTEL_UPDATE_TEXT_FIELD_RENDER
+	return chunks
+}
+
+// splitPoint returns the last character boundary in (start, end] that is not next to a space, or end if there is none.
+func splitPoint(text string, start int, end int) int {
+	for i := end; i > start; i-- {
+		if utf8.RuneStart(text[i]) && !isSpace(text[i-1]) && !isSpace(text[i]) {
+			return i
+		}
+	}
+	return end
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// RenderBoxCommand is the Command sent by RenderBox.
+type RenderBoxCommand struct {
+	Layer OxtelLayer
+	Field uint8
+}
+
+func (c RenderBoxCommand) Encode() string {
+	return fmt.Sprintf("Z0%x%02x", c.Layer, c.Field)
+}
+
+func (c RenderBoxCommand) Validate() error {
+	return nil
 }
 
 // RenderBox updates the specified text field
 //
 // If field is 0xFF (255), all fields are updated.
 func (o *Oxtel) RenderBox(layer OxtelLayer, field uint8) error {
-	msg := fmt.Sprintf("Z0%x%02x", layer, field)
+	return o.RenderBoxContext(context.Background(), layer, field)
+}
 
-	return o.sendCommand(msg)
+// RenderBoxContext is like RenderBox but uses ctx for cancellation and deadlines.
+func (o *Oxtel) RenderBoxContext(ctx context.Context, layer OxtelLayer, field uint8) error {
+	return o.SendContext(ctx, RenderBoxCommand{Layer: layer, Field: field})
 }
 
 // RenderBox_AsString returns the command string used to update the specified text field
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a RenderBoxCommand instead.
 func RenderBox_AsString(layer OxtelLayer, field uint8) string {
-	return fmt.Sprintf("Z0%x%02x", layer, field)
+	return RenderBoxCommand{Layer: layer, Field: field}.Encode()
 }
 
-// ChangeImage changes the image associated with a text field to be replaced with another image on disk.
-//
-// The new settings take effect when the RenderBox command is issued.
-func (o *Oxtel) ChangeImage(layer OxtelLayer, field uint8, fileName string) error {
-	if field > 254 {
+// ChangeImageCommand is the Command sent by ChangeImage.
+type ChangeImageCommand struct {
+	Layer    OxtelLayer
+	Field    uint8
+	FileName string
+}
+
+func (c ChangeImageCommand) Encode() string {
+	return fmt.Sprintf("Z4%x%02x%s", c.Layer, c.Field, c.FileName)
+}
+
+func (c ChangeImageCommand) Validate() error {
+	if c.Field > 254 {
 		return &InvalidFieldError{
 			BaseError: BaseError{
 				Message: "Field must be less than 255",
@@ -93,27 +279,44 @@ func (o *Oxtel) ChangeImage(layer OxtelLayer, field uint8, fileName string) erro
 		}
 	}
 
-	msg := fmt.Sprintf("Z4%x%02x%s", layer, field, fileName)
+	return nil
+}
 
-	return o.sendCommand(msg)
+// ChangeImage changes the image associated with a text field to be replaced with another image on disk.
+//
+// The new settings take effect when the RenderBox command is issued.
+func (o *Oxtel) ChangeImage(layer OxtelLayer, field uint8, fileName string) error {
+	return o.ChangeImageContext(context.Background(), layer, field, fileName)
+}
+
+// ChangeImageContext is like ChangeImage but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ChangeImageContext(ctx context.Context, layer OxtelLayer, field uint8, fileName string) error {
+	return o.SendContext(ctx, ChangeImageCommand{Layer: layer, Field: field, FileName: fileName})
 }
 
 // ChangeImage_AsString returns the command string used to change the image associated with a text field to be
 // replaced with another image on disk.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a ChangeImageCommand instead.
 func ChangeImage_AsString(layer OxtelLayer, field uint8, fileName string) string {
-	return fmt.Sprintf("Z4%x%02x%s", layer, field, fileName)
+	return ChangeImageCommand{Layer: layer, Field: field, FileName: fileName}.Encode()
 }
 
-// StopTextFieldAnimation stops an animation from playing in the specified text field on the specified layer.
-//
-// This command behaves similarly to the StopAnimation command; however, it includes a text field number parameter
-//  to allow finer control of the text field in the template.
-// If immediate is true, the animation halts immediately at the current frame. If it is set to false, the animation
-// completes before stopping.
-func (o *Oxtel) StopTextFieldAnimation(layer OxtelLayer, field uint8, immediate bool) error {
-	if field > 254 {
+// StopTextFieldAnimationCommand is the Command sent by StopTextFieldAnimation.
+type StopTextFieldAnimationCommand struct {
+	Layer     OxtelLayer
+	Field     uint8
+	Immediate bool
+}
+
+func (c StopTextFieldAnimationCommand) Encode() string {
+	return fmt.Sprintf("Zf%x%02x%x", c.Layer, c.Field, boolToInt(c.Immediate))
+}
+
+func (c StopTextFieldAnimationCommand) Validate() error {
+	if c.Field > 254 {
 		return &InvalidFieldError{
 			BaseError: BaseError{
 				Message: "Field must be less than 255",
@@ -121,24 +324,47 @@ func (o *Oxtel) StopTextFieldAnimation(layer OxtelLayer, field uint8, immediate
 		}
 	}
 
-	msg := fmt.Sprintf("Zf%x%02x%x", layer, field, boolToInt(immediate))
+	return nil
+}
+
+// StopTextFieldAnimation stops an animation from playing in the specified text field on the specified layer.
+//
+// This command behaves similarly to the StopAnimation command; however, it includes a text field number parameter
+//  to allow finer control of the text field in the template.
+// If immediate is true, the animation halts immediately at the current frame. If it is set to false, the animation
+// completes before stopping.
+func (o *Oxtel) StopTextFieldAnimation(layer OxtelLayer, field uint8, immediate bool) error {
+	return o.StopTextFieldAnimationContext(context.Background(), layer, field, immediate)
+}
 
-	return o.sendCommand(msg)
+// StopTextFieldAnimationContext is like StopTextFieldAnimation but uses ctx for cancellation and deadlines.
+func (o *Oxtel) StopTextFieldAnimationContext(ctx context.Context, layer OxtelLayer, field uint8, immediate bool) error {
+	return o.SendContext(ctx, StopTextFieldAnimationCommand{Layer: layer, Field: field, Immediate: immediate})
 }
 
 // StopTextFieldAnimation_AsString returns the command string used to stop an animation from playing in the specified
 // text field on the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a StopTextFieldAnimationCommand instead.
 func StopTextFieldAnimation_AsString(layer OxtelLayer, field uint8, immediate bool) string {
-	return fmt.Sprintf("Zf%x%02x%x", layer, field, boolToInt(immediate))
+	return StopTextFieldAnimationCommand{Layer: layer, Field: field, Immediate: immediate}.Encode()
 }
 
-// PauseRestartStrap pauses/restarts the specified text field on the specified layer.
+// PauseRestartStrapCommand is the Command sent by PauseRestartStrap.
+type PauseRestartStrapCommand struct {
+	Layer   OxtelLayer
+	Field   uint8
+	Restart bool
+}
 
-// If the restart is false, then the pause command is sent instead.
-func (o *Oxtel) PauseRestartStrap(layer OxtelLayer, field uint8, restart bool) error {
-	if field > 254 {
+func (c PauseRestartStrapCommand) Encode() string {
+	return fmt.Sprintf("Zg%x%02x%x", c.Layer, c.Field, boolToInt(c.Restart))
+}
+
+func (c PauseRestartStrapCommand) Validate() error {
+	if c.Field > 254 {
 		return &InvalidFieldError{
 			BaseError: BaseError{
 				Message: "Field must be less than 255",
@@ -146,14 +372,26 @@ func (o *Oxtel) PauseRestartStrap(layer OxtelLayer, field uint8, restart bool) e
 		}
 	}
 
-	msg := fmt.Sprintf("Zg%x%02x%x", layer, field, boolToInt(restart))
+	return nil
+}
+
+// PauseRestartStrap pauses/restarts the specified text field on the specified layer.
+//
+// If the restart is false, then the pause command is sent instead.
+func (o *Oxtel) PauseRestartStrap(layer OxtelLayer, field uint8, restart bool) error {
+	return o.PauseRestartStrapContext(context.Background(), layer, field, restart)
+}
 
-	return o.sendCommand(msg)
+// PauseRestartStrapContext is like PauseRestartStrap but uses ctx for cancellation and deadlines.
+func (o *Oxtel) PauseRestartStrapContext(ctx context.Context, layer OxtelLayer, field uint8, restart bool) error {
+	return o.SendContext(ctx, PauseRestartStrapCommand{Layer: layer, Field: field, Restart: restart})
 }
 
 // PauseRestartStrap_AsString returns the command string used to pause/restart the specified text field on the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a PauseRestartStrapCommand instead.
 func PauseRestartStrap_AsString(layer OxtelLayer, field uint8, restart bool) string {
-	return fmt.Sprintf("Zg%x%02x%x", layer, field, boolToInt(restart))
+	return PauseRestartStrapCommand{Layer: layer, Field: field, Restart: restart}.Encode()
 }
@@ -0,0 +1,73 @@
+package oxtel
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestUpdateTextFieldChunking(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+
+	if err := client.SetMaxTextPacketSize(8); err == nil {
+		t.Error("expected a packet size too small to be rejected")
+	}
+	if err := client.SetMaxTextPacketSize(16); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadImage(OXTEL_LAYER_1, "lower.html"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireLoadImage(OXTEL_LAYER_1); err != nil {
+		t.Fatal(err)
+	}
+	srv.ClearCommands()
+
+	text := "Zürich: 3;2 | Genève \\ Köln ☀☀☀"
+	if err := client.UpdateTextField(OXTEL_LAYER_1, 1, OXTEL_UPDATE_TEXT_FIELD_RENDER, text); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireLoadImage(OXTEL_LAYER_1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := srv.TextField(1, 1); got != text {
+		t.Errorf("field 1 is %q, want %q", got, text)
+	}
+
+	commands := srv.Commands()
+	commands = commands[:len(commands)-1]
+	if len(commands) < 3 {
+		t.Fatalf("text was sent in %d packets", len(commands))
+	}
+	for i, cmd := range commands {
+		if n := len(escapeCommand(cmd)); n > 16 {
+			t.Errorf("packet %q is %d bytes", cmd, n)
+		}
+		if !utf8.ValidString(cmd) {
+			t.Errorf("packet %q splits a character", cmd)
+		}
+		want := "Z01012"
+		switch i {
+		case 0:
+			want = "Z01010"
+		case len(commands) - 1:
+			want = "Z01013"
+		}
+		if !strings.HasPrefix(cmd, want) {
+			t.Errorf("packet %d is %q, want prefix %q", i, cmd, want)
+		}
+	}
+
+	srv.ClearCommands()
+	if err := client.UpdatePreloadedTextField(OXTEL_LAYER_1, 2, 0, "short"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireLoadImage(OXTEL_LAYER_1); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Commands()[0]; got != "hZ01020short" {
+		t.Errorf("short text was sent as %q", got)
+	}
+}
@@ -24,6 +24,11 @@ type OxtelKantarOutput uint8
 type OxtelExternalIOType uint8
 type OxtelExternalIODirection uint8
 type OxtelExternalIOId uint8
+type OxtelConnectionState uint8
+type OxtelTallyType uint8
+type OxtelOverflowPolicy uint8
+type OxtelFieldType uint8
+type OxtelEasing uint8
 
 const (
 	OXTEL_LAYER_0 OxtelLayer = 0
@@ -174,4 +179,34 @@ const (
 	OXTEL_EXT_IO_OUTPUT_SECONDARY       = 0x1
 	OXTEL_EXT_IO_OUTPUT_CLEAN_PRIMARY   = 0x2
 	OXTEL_EXT_IO_OUTPUT_CLEAN_SECONDARY = 0x3
+
+	OXTEL_CONNECTION_STATE_CONNECTED    OxtelConnectionState = 0
+	OXTEL_CONNECTION_STATE_LOST         OxtelConnectionState = 1
+	OXTEL_CONNECTION_STATE_RECONNECTING OxtelConnectionState = 2
+	OXTEL_CONNECTION_STATE_CLOSED       OxtelConnectionState = 3
+
+	OXTEL_TALLY_KEYER_POSITION             OxtelTallyType = 0
+	OXTEL_TALLY_IMAGE_LOAD                 OxtelTallyType = 1
+	OXTEL_TALLY_IMAGE_PRELOAD              OxtelTallyType = 2
+	OXTEL_TALLY_MEDIA                      OxtelTallyType = 3
+	OXTEL_TALLY_PLAY_STATE                 OxtelTallyType = 4
+	OXTEL_TALLY_VIDEO                      OxtelTallyType = 5
+	OXTEL_TALLY_AUDIO_PROFILE              OxtelTallyType = 6
+	OXTEL_TALLY_LOCK                       OxtelTallyType = 7
+	OXTEL_TALLY_EXTERNAL_IO_SOURCE_CHANGED OxtelTallyType = 8
+	OXTEL_TALLY_EXTERNAL_IO_DYNAMIC_CONFIG OxtelTallyType = 9
+	OXTEL_TALLY_MALFORMED                  OxtelTallyType = 10
+	OXTEL_TALLY_UNKNOWN                    OxtelTallyType = 11
+
+	OXTEL_OVERFLOW_BLOCK       OxtelOverflowPolicy = 0
+	OXTEL_OVERFLOW_DROP_OLDEST OxtelOverflowPolicy = 1
+	OXTEL_OVERFLOW_DROP_NEWEST OxtelOverflowPolicy = 2
+
+	OXTEL_FIELD_TEXT  OxtelFieldType = 0
+	OXTEL_FIELD_IMAGE OxtelFieldType = 1
+
+	OXTEL_EASING_LINEAR      OxtelEasing = 0
+	OXTEL_EASING_EASE_IN     OxtelEasing = 1
+	OXTEL_EASING_EASE_OUT    OxtelEasing = 2
+	OXTEL_EASING_EASE_IN_OUT OxtelEasing = 3
 )
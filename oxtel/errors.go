@@ -37,8 +37,11 @@ type InvalidAudioProfileError struct {
 	BaseError
 }
 
+// TimeoutError is returned when a command or enquiry does not complete before its deadline. Err is
+// context.DeadlineExceeded, so errors.Is(err, context.DeadlineExceeded) reports true.
 type TimeoutError struct {
 	BaseError
+	Err error
 }
 
 type InvalidDolbyProfileError struct {
@@ -53,6 +56,50 @@ type InvalidParametersError struct {
 	BaseError
 }
 
+type NotConnectedError struct {
+	BaseError
+}
+
+type InvalidTimecodeError struct {
+	BaseError
+}
+
+type InvalidCommandError struct {
+	BaseError
+}
+
+type InvalidTallyError struct {
+	BaseError
+}
+
+type ScheduleHorizonError struct {
+	BaseError
+}
+
+type UnknownScheduledCommandError struct {
+	BaseError
+}
+
+type MissingTemplateError struct {
+	BaseError
+}
+
+type PreloadReplacedError struct {
+	BaseError
+}
+
+type MissingSchemaError struct {
+	BaseError
+}
+
+type TransitionReversedError struct {
+	BaseError
+}
+
 func (e *BaseError) Error() string {
 	return fmt.Sprintf("Error: %s", e.Message)
 }
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
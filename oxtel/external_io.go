@@ -1,11 +1,30 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
+// EnquireNumberOfExternalIOConfigurationsCommand is the Enquiry sent by EnquireNumberOfExternalIOConfigurations.
+type EnquireNumberOfExternalIOConfigurationsCommand struct {
+	IOType    OxtelExternalIOType
+	Direction OxtelExternalIODirection
+}
+
+func (c EnquireNumberOfExternalIOConfigurationsCommand) Encode() string {
+	return fmt.Sprintf("hXNC%02x%02x", c.IOType, c.Direction)
+}
+
+func (c EnquireNumberOfExternalIOConfigurationsCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireNumberOfExternalIOConfigurationsCommand) ResponsePrefix() string {
+	return "hXNC"
+}
+
 // EnquireNumberOfExternalIOConfigurations returns the number of external IO configurations for the specified type and direction.
 // Use this command to determine the number of configurations. To gain access to the complete configuration, use this
 // command and the EnquireExternalIOConfiguration command.
@@ -18,7 +37,13 @@ import (
 // Protocol (SDP) files.
 func (o *Oxtel) EnquireNumberOfExternalIOConfigurations(ioType OxtelExternalIOType,
 	direction OxtelExternalIODirection) (NumberOfExternalIOConfigurationsResponse, error) {
-	val, err := o.sendCommandExpectResponse("hXNC", fmt.Sprintf("%02x%02x", ioType, direction))
+	return o.EnquireNumberOfExternalIOConfigurationsContext(context.Background(), ioType, direction)
+}
+
+// EnquireNumberOfExternalIOConfigurationsContext is like EnquireNumberOfExternalIOConfigurations but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireNumberOfExternalIOConfigurationsContext(ctx context.Context, ioType OxtelExternalIOType,
+	direction OxtelExternalIODirection) (NumberOfExternalIOConfigurationsResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireNumberOfExternalIOConfigurationsCommand{IOType: ioType, Direction: direction})
 	if err != nil {
 		return NumberOfExternalIOConfigurationsResponse{}, err
 	}
@@ -48,9 +73,30 @@ func (o *Oxtel) EnquireNumberOfExternalIOConfigurations(ioType OxtelExternalIOTy
 // EnquireNumberOfExternalIOConfigurations_AsString returns the command string used to return the number of external IO configurations for the specified type and direction.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireNumberOfExternalIOConfigurationsCommand instead.
 func EnquireNumberOfExternalIOConfigurations_AsString(ioType OxtelExternalIOType,
 	direction OxtelExternalIODirection) string {
-	return "hXNC" + fmt.Sprintf("%02x%02x", ioType, direction)
+	return EnquireNumberOfExternalIOConfigurationsCommand{IOType: ioType, Direction: direction}.Encode()
+}
+
+// EnquireExternalIOConfigurationCommand is the Enquiry sent by EnquireExternalIOConfiguration.
+type EnquireExternalIOConfigurationCommand struct {
+	IOType    OxtelExternalIOType
+	Direction OxtelExternalIODirection
+	Index     uint8
+}
+
+func (c EnquireExternalIOConfigurationCommand) Encode() string {
+	return fmt.Sprintf("hXNC%02x%02x%02x", c.IOType, c.Direction, c.Index)
+}
+
+func (c EnquireExternalIOConfigurationCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExternalIOConfigurationCommand) ResponsePrefix() string {
+	return "hXNC"
 }
 
 // EnquireExternalIOConfiguration returns the external IO configuration for the specified type, direction, and index.
@@ -66,7 +112,12 @@ func EnquireNumberOfExternalIOConfigurations_AsString(ioType OxtelExternalIOType
 // Response is an ExternalIOConfigurationResponse. Every response will have a IOType, IODirection, Index, ConfigurationID,
 // and Name. Depending on what IOType is queried, the other fields may be nil.
 func (o *Oxtel) EnquireExternalIOConfiguration(ioType OxtelExternalIOType, direction OxtelExternalIODirection, index uint8) (ExternalIOConfigurationResponse, error) {
-	val, err := o.sendCommandExpectResponse("hXNC", fmt.Sprintf("%02x%02x%02x", ioType, direction, index))
+	return o.EnquireExternalIOConfigurationContext(context.Background(), ioType, direction, index)
+}
+
+// EnquireExternalIOConfigurationContext is like EnquireExternalIOConfiguration but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalIOConfigurationContext(ctx context.Context, ioType OxtelExternalIOType, direction OxtelExternalIODirection, index uint8) (ExternalIOConfigurationResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalIOConfigurationCommand{IOType: ioType, Direction: direction, Index: index})
 	if err != nil {
 		return ExternalIOConfigurationResponse{}, err
 	}
@@ -145,8 +196,27 @@ func (o *Oxtel) EnquireExternalIOConfiguration(ioType OxtelExternalIOType, direc
 // EnquireExternalIOConfiguration returns the command string used to return the external IO configuration for the specified type, direction, and index.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalIOConfigurationCommand instead.
 func EnquireExternalIOConfiguration_AsString(ioType OxtelExternalIOType, direction OxtelExternalIODirection, index uint8) string {
-	return "hXNC" + fmt.Sprintf("%02x%02x%02x", ioType, direction, index)
+	return EnquireExternalIOConfigurationCommand{IOType: ioType, Direction: direction, Index: index}.Encode()
+}
+
+// SetExternalIOSourceCommand is the Command sent by SetExternalIOSource.
+type SetExternalIOSourceCommand struct {
+	Direction    OxtelExternalIODirection
+	IOId         OxtelExternalIOId
+	IOType       OxtelExternalIOType
+	ConfigId     uint8
+	ForceRestart bool
+}
+
+func (c SetExternalIOSourceCommand) Encode() string {
+	return fmt.Sprintf("hXS%02x%02x%02x%02x%02x", c.Direction, c.IOId, c.IOType, c.ConfigId, boolToInt(c.ForceRestart))
+}
+
+func (c SetExternalIOSourceCommand) Validate() error {
+	return nil
 }
 
 // SetExternalIOSource sets the source for the specified External IO. The External IO is defined by the direction of the IO id.
@@ -154,21 +224,56 @@ func EnquireExternalIOConfiguration_AsString(ioType OxtelExternalIOType, directi
 // The _configId_ parameter is the static configuration ID specified in the SystemManager. "0" is always the Dynamic Config,
 // which is not specified in the SystemManager.
 func (o *Oxtel) SetExternalIOSource(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, configId uint8, forceRestart bool) error {
-	return o.sendCommand(fmt.Sprintf("hXS%02x%02x%02x%02x%02x", direction, ioId, ioType, configId, boolToInt(forceRestart)))
+	return o.SetExternalIOSourceContext(context.Background(), direction, ioId, ioType, configId, forceRestart)
+}
+
+// SetExternalIOSourceContext is like SetExternalIOSource but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetExternalIOSourceContext(ctx context.Context, direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, configId uint8, forceRestart bool) error {
+	return o.SendContext(ctx, SetExternalIOSourceCommand{Direction: direction, IOId: ioId, IOType: ioType, ConfigId: configId, ForceRestart: forceRestart})
 }
 
 // SetExternalIOSource_AsString returns the command string used to set the source for the specified External IO. The External IO is defined by the direction of the IO id.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetExternalIOSourceCommand instead.
 func SetExternalIOSource_AsString(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, configId uint8, forceRestart bool) string {
-	return fmt.Sprintf("hXS%02x%02x%02x%02x%02x", direction, ioId, ioType, configId, boolToInt(forceRestart))
+	return SetExternalIOSourceCommand{Direction: direction, IOId: ioId, IOType: ioType, ConfigId: configId, ForceRestart: forceRestart}.Encode()
+}
+
+// EnquireExternalIOSourceCommand is the Enquiry sent by EnquireExternalIOSource.
+type EnquireExternalIOSourceCommand struct {
+	Direction OxtelExternalIODirection
+	IOId      OxtelExternalIOId
+}
+
+func (c EnquireExternalIOSourceCommand) Encode() string {
+	return fmt.Sprintf("hXS%02x%02x", c.Direction, c.IOId)
+}
+
+func (c EnquireExternalIOSourceCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExternalIOSourceCommand) ResponsePrefix() string {
+	return "hXS"
+}
+
+// The response starts with the hex direction, where an ExternalIOSourceChangedTally starts with Y.
+func (c EnquireExternalIOSourceCommand) matchesResponse(data string) bool {
+	return !strings.HasPrefix(data, "Y")
 }
 
 // EnquireExternalIOSource gets the source for the specified External IO. the External IO is defined by the direction of the IO id.
 //
 // Response is a ExternalIOSourceResponse. State is always 0.
 func (o *Oxtel) EnquireExternalIOSource(direction OxtelExternalIODirection, ioId OxtelExternalIOId) (ExternalIOSourceResponse, error) {
-	val, err := o.sendCommandExpectResponse("hXS", fmt.Sprintf("%02x%02x", direction, ioId))
+	return o.EnquireExternalIOSourceContext(context.Background(), direction, ioId)
+}
+
+// EnquireExternalIOSourceContext is like EnquireExternalIOSource but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalIOSourceContext(ctx context.Context, direction OxtelExternalIODirection, ioId OxtelExternalIOId) (ExternalIOSourceResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalIOSourceCommand{Direction: direction, IOId: ioId})
 	if err != nil {
 		return ExternalIOSourceResponse{}, err
 	}
@@ -210,75 +315,128 @@ func (o *Oxtel) EnquireExternalIOSource(direction OxtelExternalIODirection, ioId
 // EnquireExternalIOSource_AsString returns the command string used to get the source for the specified External IO. the External IO is defined by the direction of the IO id.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalIOSourceCommand instead.
 func EnquireExternalIOSource_AsString(direction OxtelExternalIODirection, ioId OxtelExternalIOId) string {
-	return "hXS" + fmt.Sprintf("%02x%02x", direction, ioId)
+	return EnquireExternalIOSourceCommand{Direction: direction, IOId: ioId}.Encode()
 }
 
-// SetExternalIODynamicConfiguration sets the dynamic configuration for the specified External IO. The External IO is
-// defined by the direction and IO Id.
-//
-// For SDI, this command is not applicable.
-//
-// 2022-6 does not use ipAddress2, port2, or sdpFileName.
-// 2022-6/2022-7 does not use sdpFileName.
-// 2110 does not use ipAddress, port, ipAddress2, or port2.
-func (o *Oxtel) SetExternalIODynamicConfiguration(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, flags uint16, localInterface string, ipAddress *string, port *string, ipAddress2 *string, port2 *string, sdpFileName *string) error {
+// SetExternalIODynamicConfigurationCommand is the Command sent by SetExternalIODynamicConfiguration. Encode returns the bare command code if the parameters required by IOType are missing.
+type SetExternalIODynamicConfigurationCommand struct {
+	Direction      OxtelExternalIODirection
+	IOId           OxtelExternalIOId
+	IOType         OxtelExternalIOType
+	Flags          uint16
+	LocalInterface string
+	IPAddress      *string
+	Port           *string
+	IPAddress2     *string
+	Port2          *string
+	SDPFileName    *string
+}
+
+func (c SetExternalIODynamicConfigurationCommand) Encode() string {
+	if c.Validate() != nil {
+		return "hXDC"
+	}
 
-	if ioType == OXTEL_EXT_IO_TYPE_2022_6 {
-		if ipAddress == nil || port == nil {
+	switch c.IOType {
+	case OXTEL_EXT_IO_TYPE_2022_6:
+		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s", c.Direction, c.IOId, c.IOType, c.Flags, c.LocalInterface, *c.IPAddress, *c.Port)
+	case OXTEL_EXT_IO_TYPE_2022_6_2022_7:
+		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s,%s,%s", c.Direction, c.IOId, c.IOType, c.Flags, c.LocalInterface, *c.IPAddress, *c.Port, *c.IPAddress2, *c.Port2)
+	default:
+		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s", c.Direction, c.IOId, c.IOType, c.Flags, c.LocalInterface, *c.SDPFileName)
+	}
+}
+
+func (c SetExternalIODynamicConfigurationCommand) Validate() error {
+	switch c.IOType {
+	case OXTEL_EXT_IO_TYPE_2022_6:
+		if c.IPAddress == nil || c.Port == nil {
 			return &InvalidParametersError{
 				BaseError: BaseError{
 					Message: "Parameters ipAddress and port are required",
 				},
 			}
 		}
-
-		return o.sendCommand(fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s", direction, ioId, ioType, flags, localInterface, *ipAddress, *port))
-	} else if ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
-		if ipAddress == nil || port == nil || ipAddress2 == nil || port2 == nil {
+	case OXTEL_EXT_IO_TYPE_2022_6_2022_7:
+		if c.IPAddress == nil || c.Port == nil || c.IPAddress2 == nil || c.Port2 == nil {
 			return &InvalidParametersError{
 				BaseError: BaseError{
 					Message: "Parameters ipAddress, port, ipAddress2, and port2 are required",
 				},
 			}
 		}
-
-		return o.sendCommand(fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s,%s,%s", direction, ioId, ioType, flags, localInterface, *ipAddress, *port, *ipAddress2, *port2))
-	} else if ioType == OXTEL_EXT_IO_TYPE_2110 {
-		if sdpFileName == nil {
+	case OXTEL_EXT_IO_TYPE_2110:
+		if c.SDPFileName == nil {
 			return &InvalidParametersError{
 				BaseError: BaseError{
 					Message: "Parameter sdpFileName is required",
 				},
 			}
 		}
-
-		return o.sendCommand(fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s", direction, ioId, ioType, flags, localInterface, *sdpFileName))
+	default:
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: "SDI does not apply to this command",
+			},
+		}
 	}
 
-	return &InvalidParametersError{
-		BaseError: BaseError{
-			Message: "SDI does not apply to this command",
-		},
-	}
+	return nil
+}
+
+// SetExternalIODynamicConfiguration sets the dynamic configuration for the specified External IO. The External IO is
+// defined by the direction and IO Id.
+//
+// For SDI, this command is not applicable.
+//
+// 2022-6 does not use ipAddress2, port2, or sdpFileName.
+// 2022-6/2022-7 does not use sdpFileName.
+// 2110 does not use ipAddress, port, ipAddress2, or port2.
+func (o *Oxtel) SetExternalIODynamicConfiguration(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, flags uint16, localInterface string, ipAddress *string, port *string, ipAddress2 *string, port2 *string, sdpFileName *string) error {
+	return o.SetExternalIODynamicConfigurationContext(context.Background(), direction, ioId, ioType, flags, localInterface, ipAddress, port, ipAddress2, port2, sdpFileName)
+}
+
+// SetExternalIODynamicConfigurationContext is like SetExternalIODynamicConfiguration but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetExternalIODynamicConfigurationContext(ctx context.Context, direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, flags uint16, localInterface string, ipAddress *string, port *string, ipAddress2 *string, port2 *string, sdpFileName *string) error {
+	return o.SendContext(ctx, SetExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType, Flags: flags, LocalInterface: localInterface, IPAddress: ipAddress, Port: port, IPAddress2: ipAddress2, Port2: port2, SDPFileName: sdpFileName})
 }
 
 // SetExternalIODynamicConfiguration_AsString returns the command used to set the dynamic configuration for the specified External IO. The External IO is
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetExternalIODynamicConfigurationCommand instead.
 func SetExternalIODynamicConfiguration_AsString(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType, flags uint16, localInterface string, ipAddress *string, port *string, ipAddress2 *string, port2 *string, sdpFileName *string) string {
+	return SetExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType, Flags: flags, LocalInterface: localInterface, IPAddress: ipAddress, Port: port, IPAddress2: ipAddress2, Port2: port2, SDPFileName: sdpFileName}.Encode()
+}
 
-	if ioType == OXTEL_EXT_IO_TYPE_2022_6 {
-		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s", direction, ioId, ioType, flags, localInterface, *ipAddress, *port)
-	} else if ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
-		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s,%s,%s,%s", direction, ioId, ioType, flags, localInterface, *ipAddress, *port, *ipAddress2, *port2)
-	} else if ioType == OXTEL_EXT_IO_TYPE_2110 {
-		return fmt.Sprintf("hXDC%02x%02x%02x%02x,%s,%s", direction, ioId, ioType, flags, localInterface, *sdpFileName)
-	}
+// EnquireExternalIODynamicConfigurationCommand is the Enquiry sent by EnquireExternalIODynamicConfiguration.
+type EnquireExternalIODynamicConfigurationCommand struct {
+	Direction OxtelExternalIODirection
+	IOId      OxtelExternalIOId
+	IOType    OxtelExternalIOType
+}
+
+func (c EnquireExternalIODynamicConfigurationCommand) Encode() string {
+	return fmt.Sprintf("hXDC%02x%02x%02x", c.Direction, c.IOId, c.IOType)
+}
+
+func (c EnquireExternalIODynamicConfigurationCommand) Validate() error {
+	return nil
+}
 
+func (c EnquireExternalIODynamicConfigurationCommand) ResponsePrefix() string {
 	return "hXDC"
 }
 
+// The response starts with the hex direction, where an ExternalIODynamicConfigChangedTally starts with Y.
+func (c EnquireExternalIODynamicConfigurationCommand) matchesResponse(data string) bool {
+	return !strings.HasPrefix(data, "Y")
+}
+
 // EnquireExternalIODynamicConfiguration gets the dynamic configuration for the specified External IO. The External IO is defined
 // by the direction and IO Id.
 //
@@ -286,7 +444,12 @@ func SetExternalIODynamicConfiguration_AsString(direction OxtelExternalIODirecti
 //
 // Response is an ExternalIODynamicConfigurationResponse. Not all fields will be used.
 func (o *Oxtel) EnquireExternalIODynamicConfiguration(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType) (ExternalIODynamicConfigurationResponse, error) {
-	val, err := o.sendCommandExpectResponse("hXDC", fmt.Sprintf("%02x%02x%02x", direction, ioId, ioType))
+	return o.EnquireExternalIODynamicConfigurationContext(context.Background(), direction, ioId, ioType)
+}
+
+// EnquireExternalIODynamicConfigurationContext is like EnquireExternalIODynamicConfiguration but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalIODynamicConfigurationContext(ctx context.Context, direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType) (ExternalIODynamicConfigurationResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType})
 	if err != nil {
 		return ExternalIODynamicConfigurationResponse{}, err
 	}
@@ -362,29 +525,71 @@ func (o *Oxtel) EnquireExternalIODynamicConfiguration(direction OxtelExternalIOD
 // EnquireExternalIODynamicConfiguration_AsString returns the command used to get the dynamic configuration for the specified External IO. The External IO is defined
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalIODynamicConfigurationCommand instead.
 func EnquireExternalIODynamicConfiguration_AsString(direction OxtelExternalIODirection, ioId OxtelExternalIOId, ioType OxtelExternalIOType) string {
-	return "hXDC" + fmt.Sprintf("%02x%02x%02x", direction, ioId, ioType)
+	return EnquireExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType}.Encode()
+}
+
+// EnableExternalIOTallyCommand is the Command sent by EnableExternalIOTally.
+type EnableExternalIOTallyCommand struct {
+	Enable bool
+}
+
+func (c EnableExternalIOTallyCommand) Encode() string {
+	return fmt.Sprintf("hXIOT%01x", boolToInt(c.Enable))
+}
+
+func (c EnableExternalIOTallyCommand) Validate() error {
+	return nil
 }
 
 // EnableExternalIOTally enables/disables the External IO Tally for the connection on which the command was received.
 //
 // When enabled, unsolicited tallies will be sent so that the client can record the initial sate.
 func (o *Oxtel) EnableExternalIOTally(enable bool) error {
-	return o.sendCommand(fmt.Sprintf("hXIOT%01x", boolToInt(enable)))
+	return o.EnableExternalIOTallyContext(context.Background(), enable)
+}
+
+// EnableExternalIOTallyContext is like EnableExternalIOTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableExternalIOTallyContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, EnableExternalIOTallyCommand{Enable: enable})
 }
 
 // EnableExternalIOTally_AsString returns the command string used to enable/disable the External IO Tally for the connection on which the command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableExternalIOTallyCommand instead.
 func EnableExternalIOTally_AsString(enable bool) string {
-	return fmt.Sprintf("hXIOT%01x", boolToInt(enable))
+	return EnableExternalIOTallyCommand{Enable: enable}.Encode()
+}
+
+// EnquireExternalIOTallyCommand is the Enquiry sent by EnquireExternalIOTally.
+type EnquireExternalIOTallyCommand struct{}
+
+func (c EnquireExternalIOTallyCommand) Encode() string {
+	return "hXIOT"
+}
+
+func (c EnquireExternalIOTallyCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExternalIOTallyCommand) ResponsePrefix() string {
+	return "hXIOT"
 }
 
 // EnquireExternalIOTally queries if external IO tally is enabled for the connection on which the command was received.
 //
 // Response is a boolean.
 func (o *Oxtel) EnquireExternalIOTally() (bool, error) {
-	val, err := o.sendCommandExpectResponse("hXIOT", "")
+	return o.EnquireExternalIOTallyContext(context.Background())
+}
+
+// EnquireExternalIOTallyContext is like EnquireExternalIOTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalIOTallyContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalIOTallyCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -395,15 +600,37 @@ func (o *Oxtel) EnquireExternalIOTally() (bool, error) {
 // EnquireExternalIOTally_AsString returns the command string used to query if external IO tally is enabled for the connection on which the command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalIOTallyCommand instead.
 func EnquireExternalIOTally_AsString() string {
-	return "hXIOT"
+	return EnquireExternalIOTallyCommand{}.Encode()
+}
+
+// EnquireExternalIOSupportedCommand is the Enquiry sent by EnquireExternalIOSupported.
+type EnquireExternalIOSupportedCommand struct{}
+
+func (c EnquireExternalIOSupportedCommand) Encode() string {
+	return "hEXTIO"
+}
+
+func (c EnquireExternalIOSupportedCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExternalIOSupportedCommand) ResponsePrefix() string {
+	return "hEXTIO"
 }
 
 // EnquireExternalIOSupported returns whether or not the External IO feature is supported.
 //
 // Response is a boolean representing if External IO is supported.
 func (o *Oxtel) EnquireExternalIOSupported() (bool, error) {
-	val, err := o.sendCommandExpectResponse("hEXTIO", "")
+	return o.EnquireExternalIOSupportedContext(context.Background())
+}
+
+// EnquireExternalIOSupportedContext is like EnquireExternalIOSupported but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalIOSupportedContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalIOSupportedCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -414,8 +641,25 @@ func (o *Oxtel) EnquireExternalIOSupported() (bool, error) {
 // EnquireExternalIOSupported_AsString returns the command string used to return whether or not the External IO feature is supported.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalIOSupportedCommand instead.
 func EnquireExternalIOSupported_AsString() string {
-	return "hEXTIO"
+	return EnquireExternalIOSupportedCommand{}.Encode()
+}
+
+// EnquireExternalInputsCommand is the Enquiry sent by EnquireExternalInputs.
+type EnquireExternalInputsCommand struct{}
+
+func (c EnquireExternalInputsCommand) Encode() string {
+	return "hXIN"
+}
+
+func (c EnquireExternalInputsCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExternalInputsCommand) ResponsePrefix() string {
+	return "hXIN"
 }
 
 // EnquireExternalInputs returns a list of the external inputs supported by the channel. This command can be used to dynamically
@@ -423,7 +667,12 @@ func EnquireExternalIOSupported_AsString() string {
 //
 // Returns an ExternalInputsResponse. The VideoSourceId is the same value as defined for the SelectMixerInputCommand
 func (o *Oxtel) EnquireExternalInputs() (ExternalInputsResponse, error) {
-	val, err := o.sendCommandExpectResponse("hXIN", "")
+	return o.EnquireExternalInputsContext(context.Background())
+}
+
+// EnquireExternalInputsContext is like EnquireExternalInputs but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExternalInputsContext(ctx context.Context) (ExternalInputsResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireExternalInputsCommand{})
 	if err != nil {
 		return ExternalInputsResponse{}, err
 	}
@@ -457,8 +706,25 @@ func (o *Oxtel) EnquireExternalInputs() (ExternalInputsResponse, error) {
 // EnquireExternalInputs_AsString returns the command string used to return a list of the external inputs supported by the channel. This command can be used to dynamically
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExternalInputsCommand instead.
 func EnquireExternalInputs_AsString() string {
-	return "hXIN"
+	return EnquireExternalInputsCommand{}.Encode()
+}
+
+// EnquireOutputsCommand is the Enquiry sent by EnquireOutputs.
+type EnquireOutputsCommand struct{}
+
+func (c EnquireOutputsCommand) Encode() string {
+	return "hOUT"
+}
+
+func (c EnquireOutputsCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireOutputsCommand) ResponsePrefix() string {
+	return "hOUT"
 }
 
 // EnquireOutputs returns a list of the outputs supported for the channel. This command can be used to dynamically determine
@@ -466,7 +732,12 @@ func EnquireExternalInputs_AsString() string {
 //
 // Returns an ExternalOutputsResponse.
 func (o *Oxtel) EnquireOutputs() (ExternalOutputsResponse, error) {
-	val, err := o.sendCommandExpectResponse("hOUT", "")
+	return o.EnquireOutputsContext(context.Background())
+}
+
+// EnquireOutputsContext is like EnquireOutputs but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireOutputsContext(ctx context.Context) (ExternalOutputsResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireOutputsCommand{})
 	if err != nil {
 		return ExternalOutputsResponse{}, err
 	}
@@ -500,6 +771,8 @@ func (o *Oxtel) EnquireOutputs() (ExternalOutputsResponse, error) {
 // EnquireOutputs_AsString returns the command string used to return a list of the outputs supported for the channel. This command can be used to dynamically determine
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireOutputsCommand instead.
 func EnquireOutputs_AsString() string {
-	return "hOUT"
+	return EnquireOutputsCommand{}.Encode()
 }
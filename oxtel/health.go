@@ -1,14 +1,35 @@
 package oxtel
 
 import (
+	"context"
 	"strconv"
 )
 
+// EnquireTemperatureCommand is the Enquiry sent by EnquireTemperature.
+type EnquireTemperatureCommand struct{}
+
+func (c EnquireTemperatureCommand) Encode() string {
+	return "X0"
+}
+
+func (c EnquireTemperatureCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireTemperatureCommand) ResponsePrefix() string {
+	return "X0"
+}
+
 // EnquireTemperature is implemented but always returns 0 for the temperature.
 //
 // Response is a float64 of the temperature, which is always 0.0
 func (o *Oxtel) EnquireTemperature() (float64, error) {
-	val, err := o.sendCommandExpectResponse("X0", "")
+	return o.EnquireTemperatureContext(context.Background())
+}
+
+// EnquireTemperatureContext is like EnquireTemperature but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireTemperatureContext(ctx context.Context) (float64, error) {
+	val, err := o.EnquireContext(ctx, EnquireTemperatureCommand{})
 	if err != nil {
 		return 0x00000, err
 	}
@@ -23,6 +44,8 @@ func (o *Oxtel) EnquireTemperature() (float64, error) {
 // EnquireTemperature_AsString returns the command used to get the temperature.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireTemperatureCommand instead.
 func EnquireTemperature_AsString() string {
-	return "X0"
+	return EnquireTemperatureCommand{}.Encode()
 }
@@ -0,0 +1,202 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// keyerPollInterval is how often a KeyerTransition enquires the fader angle in case a tally is missed.
+const keyerPollInterval = 100 * time.Millisecond
+
+// keyerTransitionTimeout bounds a KeyerTransition. The longest fade, 999 fields, takes 20 seconds at 50 fields.
+const keyerTransitionTimeout = 30 * time.Second
+
+// KeyerTransition is a keyer fade or cut started by FadeKeyerAsync or CutKeyerAsync. It completes when the layer is
+// fully up or down.
+type KeyerTransition struct {
+	Layer     OxtelLayer
+	Direction OxtelDirection
+
+	done     chan struct{}
+	once     sync.Once
+	position OxtelKeyerPositionTally
+	err      error
+}
+
+// Done returns a channel that is closed when the transition completes.
+func (t *KeyerTransition) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the transition completes and returns the position the layer ended in.
+//
+// A TransitionReversedError is returned, along with the position, if a reverse command sent during the fade brought
+// the layer back. A TimeoutError is returned if the layer has not settled after 30 seconds.
+func (t *KeyerTransition) Wait() (OxtelKeyerPositionTally, error) {
+	return t.WaitContext(context.Background())
+}
+
+// WaitContext is like Wait but uses ctx for cancellation and deadlines. The transition carries on if ctx is done first.
+func (t *KeyerTransition) WaitContext(ctx context.Context) (OxtelKeyerPositionTally, error) {
+	select {
+	case <-t.done:
+		return t.position, t.err
+	case <-ctx.Done():
+		return OXTEL_KEYER_TALLY_IN_TRANSITION, contextError(ctx, fmt.Sprintf("Timed out waiting for the keyer of layer %d", t.Layer))
+	}
+}
+
+func (t *KeyerTransition) finish(position OxtelKeyerPositionTally, err error) {
+	t.once.Do(func() {
+		t.position, t.err = position, err
+		close(t.done)
+	})
+}
+
+// FadeKeyerAsync is like FadeKeyer but returns a KeyerTransition that completes when the layer is fully up or down.
+//
+// Completion is taken from the KeyerPositionTally of the layer, and the video tallies are enabled if they are not
+// already. The fader angle is also polled with EnquireVideoLayerStatus in case a tally is missed. If the fade is
+// reversed by another FadeKeyer before it finishes, the transition completes when the reversed fade does.
+func (o *Oxtel) FadeKeyerAsync(layer OxtelLayer, direction OxtelDirection, rate *uint16) (*KeyerTransition, error) {
+	return o.FadeKeyerAsyncContext(context.Background(), layer, direction, rate)
+}
+
+// FadeKeyerAsyncContext is like FadeKeyerAsync but uses ctx for cancellation and deadlines while the fade is started.
+// The transition itself is not bound to ctx.
+func (o *Oxtel) FadeKeyerAsyncContext(ctx context.Context, layer OxtelLayer, direction OxtelDirection, rate *uint16) (*KeyerTransition, error) {
+	return o.startKeyerTransition(ctx, layer, direction, FadeKeyerCommand{Layer: layer, Direction: direction, Rate: rate})
+}
+
+// CutKeyerAsync is like CutKeyer but returns a KeyerTransition, as described for FadeKeyerAsync.
+func (o *Oxtel) CutKeyerAsync(layer OxtelLayer, direction OxtelDirection) (*KeyerTransition, error) {
+	return o.CutKeyerAsyncContext(context.Background(), layer, direction)
+}
+
+// CutKeyerAsyncContext is like CutKeyerAsync but uses ctx for cancellation and deadlines while the cut is started.
+func (o *Oxtel) CutKeyerAsyncContext(ctx context.Context, layer OxtelLayer, direction OxtelDirection) (*KeyerTransition, error) {
+	return o.startKeyerTransition(ctx, layer, direction, CutKeyerCommand{Layer: layer, Direction: direction})
+}
+
+func (o *Oxtel) startKeyerTransition(ctx context.Context, layer OxtelLayer, direction OxtelDirection, cmd Command) (*KeyerTransition, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if err := o.enableVideoTalliesContext(ctx); err != nil {
+		return nil, err
+	}
+	target, err := o.keyerTargetContext(ctx, layer, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := o.subscribeKeyer()
+	if err := o.SendContext(ctx, cmd); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	t := newKeyerTransition(layer, direction)
+	go o.watchKeyer(t, sub, target, 0)
+	return t, nil
+}
+
+func newKeyerTransition(layer OxtelLayer, direction OxtelDirection) *KeyerTransition {
+	return &KeyerTransition{Layer: layer, Direction: direction, done: make(chan struct{})}
+}
+
+// keyerTargetContext returns the position a keyer moving in direction ends in. A toggle goes to the opposite of where
+// the layer is; if it is moving, either end will do and OXTEL_KEYER_TALLY_IN_TRANSITION is returned.
+func (o *Oxtel) keyerTargetContext(ctx context.Context, layer OxtelLayer, direction OxtelDirection) (OxtelKeyerPositionTally, error) {
+	if direction != OXTEL_DIR_TOGGLE {
+		return OxtelKeyerPositionTally(direction), nil
+	}
+
+	status, err := o.EnquireVideoLayerStatusContext(ctx, layer)
+	if err != nil {
+		return 0, err
+	}
+	switch keyerPositionOf(status.LayerFaderAngle) {
+	case OXTEL_KEYER_TALLY_DOWN:
+		return OXTEL_KEYER_TALLY_UP, nil
+	case OXTEL_KEYER_TALLY_UP:
+		return OXTEL_KEYER_TALLY_DOWN, nil
+	}
+	return OXTEL_KEYER_TALLY_IN_TRANSITION, nil
+}
+
+func (o *Oxtel) subscribeKeyer() *Subscription {
+	return o.Subscribe(SubscriptionOptions{
+		Types:    []OxtelTallyType{OXTEL_TALLY_KEYER_POSITION},
+		Overflow: OXTEL_OVERFLOW_DROP_OLDEST,
+	})
+}
+
+// watchKeyer completes t once the layer settles. A settled position other than target only counts once the layer has
+// been seen moving, as tallies and enquiries answered before the command took effect still report the old position.
+// For a scheduled command, delay is how long until it is executed; the fader angle is not polled before then.
+func (o *Oxtel) watchKeyer(t *KeyerTransition, sub *Subscription, target OxtelKeyerPositionTally, delay time.Duration) {
+	defer sub.Close()
+
+	due := time.Now().Add(delay)
+	poll := time.NewTicker(keyerPollInterval)
+	defer poll.Stop()
+	timeout := time.NewTimer(delay + keyerTransitionTimeout)
+	defer timeout.Stop()
+
+	moving := false
+	settled := func(position OxtelKeyerPositionTally) bool {
+		if position == OXTEL_KEYER_TALLY_IN_TRANSITION {
+			moving = true
+			return false
+		}
+		if position != target && !moving && target != OXTEL_KEYER_TALLY_IN_TRANSITION {
+			return false
+		}
+
+		var err error
+		if target != OXTEL_KEYER_TALLY_IN_TRANSITION && position != target {
+			err = &TransitionReversedError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Keyer of layer %d was reversed", t.Layer),
+				},
+			}
+		}
+		t.finish(position, err)
+		return true
+	}
+
+	for {
+		select {
+		case tally, ok := <-sub.C:
+			if !ok {
+				t.finish(OXTEL_KEYER_TALLY_IN_TRANSITION, &NotConnectedError{
+					BaseError: BaseError{
+						Message: "Disconnected during the keyer transition",
+					},
+				})
+				return
+			}
+			if k, ok := tally.(KeyerPositionTally); ok && k.Layer == t.Layer && settled(OxtelKeyerPositionTally(k.Direction)) {
+				return
+			}
+		case <-poll.C:
+			if time.Now().Before(due) {
+				continue
+			}
+			status, err := o.EnquireVideoLayerStatus(t.Layer)
+			if err == nil && settled(keyerPositionOf(status.LayerFaderAngle)) {
+				return
+			}
+		case <-timeout.C:
+			t.finish(OXTEL_KEYER_TALLY_IN_TRANSITION, &TimeoutError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Keyer of layer %d did not settle", t.Layer),
+				},
+			})
+			return
+		}
+	}
+}
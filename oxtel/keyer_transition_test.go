@@ -0,0 +1,78 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestFadeKeyerAsync(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	rate := uint16(20)
+	fade, err := client.FadeKeyerAsync(OXTEL_LAYER_1, OXTEL_DIR_UP, &rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := fade.Wait(); err != nil || position != OXTEL_KEYER_TALLY_UP {
+		t.Fatalf("fade up ended %d, %v", position, err)
+	}
+	if srv.FaderAngle(1) != 512 {
+		t.Errorf("fader angle is %d after the fade", srv.FaderAngle(1))
+	}
+
+	cut, err := client.CutKeyerAsync(OXTEL_LAYER_1, OXTEL_DIR_TOGGLE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := cut.Wait(); err != nil || position != OXTEL_KEYER_TALLY_DOWN {
+		t.Fatalf("toggle ended %d, %v", position, err)
+	}
+
+	// A reverse command during a fade brings the layer back at the same rate.
+	slow := uint16(200)
+	up, err := client.FadeKeyerAsync(OXTEL_LAYER_2, OXTEL_DIR_UP, &slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	down, err := client.FadeKeyerAsync(OXTEL_LAYER_2, OXTEL_DIR_DOWN, &slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	position, err := up.Wait()
+	if _, ok := err.(*TransitionReversedError); !ok || position != OXTEL_KEYER_TALLY_DOWN {
+		t.Errorf("reversed fade ended %d, %v", position, err)
+	}
+	if position, err := down.Wait(); err != nil || position != OXTEL_KEYER_TALLY_DOWN {
+		t.Errorf("reversing fade ended %d, %v", position, err)
+	}
+}
+
+func TestFadeKeyerAsyncPolling(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	// Without tallies, completion comes from polling the fader angle.
+	srv.Handle("Y6", func(cmd string) []string { return nil })
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	rate := uint16(20)
+	fade, err := client.FadeKeyerAsync(OXTEL_LAYER_3, OXTEL_DIR_UP, &rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-fade.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("fade did not complete")
+	}
+	if position, err := fade.Wait(); err != nil || position != OXTEL_KEYER_TALLY_UP {
+		t.Errorf("fade up ended %d, %v", position, err)
+	}
+}
@@ -1,6 +1,35 @@
 package oxtel
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
+
+// FadeKeyerCommand is the Command sent by FadeKeyer. A nil Rate uses the duration set with SetTransitionDuration.
+type FadeKeyerCommand struct {
+	Layer     OxtelLayer
+	Direction OxtelDirection
+	Rate      *uint16
+}
+
+func (c FadeKeyerCommand) Encode() string {
+	if c.Rate == nil {
+		return fmt.Sprintf("1%x %d", c.Layer, c.Direction)
+	}
+	return fmt.Sprintf("1%x %d %x", c.Layer, c.Direction, *c.Rate)
+}
+
+func (c FadeKeyerCommand) Validate() error {
+	if c.Rate != nil && *c.Rate > 999 {
+		return &InvalidDurationError{
+			BaseError: BaseError{
+				Message: "Rate must be less than 1000",
+			},
+		}
+	}
+
+	return nil
+}
 
 // FadeKeyer fades the specified graphic keying layer in the specified number of fields.
 // If a prior fade is not finished when a reverse command is received, the fade transition will reverse direction and
@@ -13,32 +42,35 @@ import "fmt"
 //
 // An unsolicited response will be transmitted as a OxtelKeyerPositionTally
 func (o *Oxtel) FadeKeyer(layer OxtelLayer, direction OxtelDirection, rate *uint16) error {
-	msg := ""
-	if rate == nil {
-		msg = fmt.Sprintf("1%x %d", layer, direction)
-	} else {
-		if *rate > 999 {
-			return &InvalidDurationError{
-				BaseError: BaseError{
-					Message: "Rate must be less than 1000",
-				},
-			}
-		}
-		msg = fmt.Sprintf("1%x %d %x", layer, direction, rate)
-	}
+	return o.FadeKeyerContext(context.Background(), layer, direction, rate)
+}
 
-	return o.sendCommand(msg)
+// FadeKeyerContext is like FadeKeyer but uses ctx for cancellation and deadlines.
+func (o *Oxtel) FadeKeyerContext(ctx context.Context, layer OxtelLayer, direction OxtelDirection, rate *uint16) error {
+	return o.SendContext(ctx, FadeKeyerCommand{Layer: layer, Direction: direction, Rate: rate})
 }
 
 // FadeKeyer_AsString returns the command string used to fade the specified graphic keying layer in the specified number of fields.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a FadeKeyerCommand instead.
 func FadeKeyer_AsString(layer OxtelLayer, direction OxtelDirection, rate *uint16) string {
-	if rate == nil {
-		return fmt.Sprintf("1%x %d", layer, direction)
-	} else {
-		return fmt.Sprintf("1%x %d %x", layer, direction, rate)
-	}
+	return FadeKeyerCommand{Layer: layer, Direction: direction, Rate: rate}.Encode()
+}
+
+// CutKeyerCommand is the Command sent by CutKeyer.
+type CutKeyerCommand struct {
+	Layer     OxtelLayer
+	Direction OxtelDirection
+}
+
+func (c CutKeyerCommand) Encode() string {
+	return fmt.Sprintf("3%x %d", c.Layer, c.Direction)
+}
+
+func (c CutKeyerCommand) Validate() error {
+	return nil
 }
 
 // CutKeyer cuts the specified graphic keying layer up or down. This command is the same as the FadeKeyer command executed
@@ -48,17 +80,44 @@ func FadeKeyer_AsString(layer OxtelLayer, direction OxtelDirection, rate *uint16
 //
 // An unsolicited response will be transmitted as a OxtelKeyerPositionTally
 func (o *Oxtel) CutKeyer(layer OxtelLayer, direction OxtelDirection) error {
-	msg := fmt.Sprintf("3%x %d", layer, direction)
+	return o.CutKeyerContext(context.Background(), layer, direction)
+}
 
-	return o.sendCommand(msg)
+// CutKeyerContext is like CutKeyer but uses ctx for cancellation and deadlines.
+func (o *Oxtel) CutKeyerContext(ctx context.Context, layer OxtelLayer, direction OxtelDirection) error {
+	return o.SendContext(ctx, CutKeyerCommand{Layer: layer, Direction: direction})
 }
 
 // CutKeyer_AsString returns the command string used to cut the specified graphic keying layer up or down. This command is
 // the same as the FadeKeyer command executed with a rate of 0.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a CutKeyerCommand instead.
 func CutKeyer_AsString(layer OxtelLayer, direction OxtelDirection) string {
-	return fmt.Sprintf("3%x %d", layer, direction)
+	return CutKeyerCommand{Layer: layer, Direction: direction}.Encode()
+}
+
+// SetTransitionDurationCommand is the Command sent by SetTransitionDuration.
+type SetTransitionDurationCommand struct {
+	Layer OxtelLayer
+	Rate  uint16
+}
+
+func (c SetTransitionDurationCommand) Encode() string {
+	return fmt.Sprintf("B%x 1 %x", c.Layer, c.Rate)
+}
+
+func (c SetTransitionDurationCommand) Validate() error {
+	if c.Rate > 999 {
+		return &InvalidRateError{
+			BaseError: BaseError{
+				Message: "Rate must be less than 1000 fields/frames",
+			},
+		}
+	}
+
+	return nil
 }
 
 // SetTransitionDuration sets the keyer fade duration for the specified layer. This value is used by the FadeKeyer command
@@ -68,43 +127,61 @@ func CutKeyer_AsString(layer OxtelLayer, direction OxtelDirection) string {
 //
 // An unsolicited response will be transmitted as a OxtelKeyerPositionTally
 func (o *Oxtel) SetTransitionDuration(layer OxtelLayer, rate uint16) error {
-	if rate > 999 {
-		return &InvalidRateError{
-			BaseError: BaseError{
-				Message: "Rate must be less than 1000 fields/frames",
-			},
-		}
-	}
-	msg := fmt.Sprintf("B%x 1 %x", layer, rate)
+	return o.SetTransitionDurationContext(context.Background(), layer, rate)
+}
 
-	return o.sendCommand(msg)
+// SetTransitionDurationContext is like SetTransitionDuration but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetTransitionDurationContext(ctx context.Context, layer OxtelLayer, rate uint16) error {
+	return o.SendContext(ctx, SetTransitionDurationCommand{Layer: layer, Rate: rate})
 }
 
 // SetTransitionDuration_AsString returns the command string used to set the keyer fade duration for the specified layer.
 // This value is used by the FadeKeyer command when the rate parameter is not specified.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetTransitionDurationCommand instead.
 func SetTransitionDuration_AsString(layer OxtelLayer, rate uint16) string {
-	return fmt.Sprintf("B%x 1 %x", layer, rate)
+	return SetTransitionDurationCommand{Layer: layer, Rate: rate}.Encode()
 }
 
-// SetFaderAngle sets the keyer fader for the specified layer to an absolute level.
-func (o *Oxtel) SetFaderAngle(layer OxtelLayer, angle uint16) error {
-	if angle > 512 {
+// SetFaderAngleCommand is the Command sent by SetFaderAngle.
+type SetFaderAngleCommand struct {
+	Layer OxtelLayer
+	Angle uint16
+}
+
+func (c SetFaderAngleCommand) Encode() string {
+	return fmt.Sprintf("@%x 1 %x", c.Layer, c.Angle)
+}
+
+func (c SetFaderAngleCommand) Validate() error {
+	if c.Angle > 512 {
 		return &InvalidAngleError{
 			BaseError: BaseError{
 				Message: "Angle must be less than 513",
 			},
 		}
 	}
-	msg := fmt.Sprintf("@%x 1 %x", layer, angle)
 
-	return o.sendCommand(msg)
+	return nil
+}
+
+// SetFaderAngle sets the keyer fader for the specified layer to an absolute level.
+func (o *Oxtel) SetFaderAngle(layer OxtelLayer, angle uint16) error {
+	return o.SetFaderAngleContext(context.Background(), layer, angle)
+}
+
+// SetFaderAngleContext is like SetFaderAngle but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetFaderAngleContext(ctx context.Context, layer OxtelLayer, angle uint16) error {
+	return o.SendContext(ctx, SetFaderAngleCommand{Layer: layer, Angle: angle})
 }
 
 // SetFaderAngle_AsString returns the command string used to set the keyer fader for the specified layer to an absolute level.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetFaderAngleCommand instead.
 func SetFaderAngle_AsString(layer OxtelLayer, angle uint16) string {
-	return fmt.Sprintf("@%x 1 %x", layer, angle)
+	return SetFaderAngleCommand{Layer: layer, Angle: angle}.Encode()
 }
@@ -0,0 +1,181 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+)
+
+// GroupMember is a layer of a LayerGroup. Offset is the number of frames after the start of the group that the layer
+// moves.
+type GroupMember struct {
+	Layer  OxtelLayer
+	Offset int
+}
+
+// LayerGroup is a set of layers that fade or cut together, such as the layers of a lower third and its bug.
+type LayerGroup struct {
+	Members []GroupMember
+}
+
+// NewLayerGroup returns a LayerGroup of the given layers, all moving on the same frame.
+func NewLayerGroup(layers ...OxtelLayer) LayerGroup {
+	g := LayerGroup{Members: make([]GroupMember, len(layers))}
+	for i, layer := range layers {
+		g.Members[i] = GroupMember{Layer: layer}
+	}
+	return g
+}
+
+// Validate checks that the group has members, that no layer is a member twice and that no offset is negative.
+func (g LayerGroup) Validate() error {
+	if len(g.Members) == 0 {
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: "Layer group has no members",
+			},
+		}
+	}
+
+	seen := make(map[OxtelLayer]bool, len(g.Members))
+	for _, m := range g.Members {
+		if seen[m.Layer] {
+			return &InvalidParametersError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Layer %d is in the group more than once", m.Layer),
+				},
+			}
+		}
+		seen[m.Layer] = true
+
+		if m.Offset < 0 {
+			return &InvalidParametersError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Offset of layer %d must not be negative", m.Layer),
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// GroupTransition is a fade or cut of a LayerGroup started by FadeGroup or CutGroup. It completes when every member
+// has completed.
+type GroupTransition struct {
+	// Transitions holds the transition of each member, in the order of the group.
+	Transitions []*KeyerTransition
+
+	done chan struct{}
+}
+
+// Done returns a channel that is closed when every member has completed.
+func (g *GroupTransition) Done() <-chan struct{} {
+	return g.done
+}
+
+// Wait blocks until every member has completed and returns the error of the first member, in the order of the group,
+// that failed. The position and error of each member are available from Transitions.
+func (g *GroupTransition) Wait() error {
+	return g.WaitContext(context.Background())
+}
+
+// WaitContext is like Wait but uses ctx for cancellation and deadlines. The transition carries on if ctx is done first.
+func (g *GroupTransition) WaitContext(ctx context.Context) error {
+	select {
+	case <-g.done:
+	case <-ctx.Done():
+		return contextError(ctx, "Timed out waiting for the layer group")
+	}
+
+	for _, t := range g.Transitions {
+		if _, err := t.Wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FadeGroup fades every layer of a group up or down. The fades are scheduled with AddScheduledCommand so that the
+// group is seen on the SDI output at start, each member Offset frames later, and are sent in a single write. The
+// latencies and frame rate of the engine are taken into account as by ScheduleOnAir.
+//
+// The returned GroupTransition completes when the KeyerPositionTally of every member settles, as described for
+// FadeKeyerAsync. OXTEL_DIR_TOGGLE is not accepted, as the members could move in different directions.
+func (o *Oxtel) FadeGroup(group LayerGroup, start Timecode, direction OxtelDirection, rate *uint16) (*GroupTransition, error) {
+	return o.FadeGroupContext(context.Background(), group, start, direction, rate)
+}
+
+// FadeGroupContext is like FadeGroup but uses ctx for cancellation and deadlines while the fades are scheduled. The
+// transition itself is not bound to ctx.
+func (o *Oxtel) FadeGroupContext(ctx context.Context, group LayerGroup, start Timecode, direction OxtelDirection, rate *uint16) (*GroupTransition, error) {
+	return o.startGroupTransition(ctx, group, start, direction, func(layer OxtelLayer) Command {
+		return FadeKeyerCommand{Layer: layer, Direction: direction, Rate: rate}
+	})
+}
+
+// CutGroup cuts every layer of a group up or down, as described for FadeGroup.
+func (o *Oxtel) CutGroup(group LayerGroup, start Timecode, direction OxtelDirection) (*GroupTransition, error) {
+	return o.CutGroupContext(context.Background(), group, start, direction)
+}
+
+// CutGroupContext is like CutGroup but uses ctx for cancellation and deadlines while the cuts are scheduled.
+func (o *Oxtel) CutGroupContext(ctx context.Context, group LayerGroup, start Timecode, direction OxtelDirection) (*GroupTransition, error) {
+	return o.startGroupTransition(ctx, group, start, direction, func(layer OxtelLayer) Command {
+		return CutKeyerCommand{Layer: layer, Direction: direction}
+	})
+}
+
+func (o *Oxtel) startGroupTransition(ctx context.Context, group LayerGroup, start Timecode, direction OxtelDirection, command func(layer OxtelLayer) Command) (*GroupTransition, error) {
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+	if direction == OXTEL_DIR_TOGGLE {
+		return nil, &InvalidParametersError{
+			BaseError: BaseError{
+				Message: "A layer group cannot be toggled",
+			},
+		}
+	}
+
+	cmds := make([]Command, len(group.Members))
+	for i, m := range group.Members {
+		cmds[i] = command(m.Layer)
+		if err := cmds[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := o.enableVideoTalliesContext(ctx); err != nil {
+		return nil, err
+	}
+	recognized, now, err := o.recognitionTimeContext(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]*Subscription, len(group.Members))
+	for i, m := range group.Members {
+		cmds[i] = ScheduledCommand{Time: recognized.AddFrames(m.Offset), Command: cmds[i]}
+		subs[i] = o.subscribeKeyer()
+	}
+	if err := o.SendBatchContext(ctx, cmds...); err != nil {
+		for _, sub := range subs {
+			sub.Close()
+		}
+		return nil, err
+	}
+
+	g := &GroupTransition{Transitions: make([]*KeyerTransition, len(group.Members)), done: make(chan struct{})}
+	for i, m := range group.Members {
+		t := newKeyerTransition(m.Layer, direction)
+		g.Transitions[i] = t
+		delay := now.Until(recognized.AddFrames(m.Offset))
+		go o.watchKeyer(t, subs[i], OxtelKeyerPositionTally(direction), delay)
+	}
+	go func() {
+		for _, t := range g.Transitions {
+			<-t.Done()
+		}
+		close(g.done)
+	}()
+	return g, nil
+}
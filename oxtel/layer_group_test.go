@@ -0,0 +1,70 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayerGroupValidate(t *testing.T) {
+	if err := NewLayerGroup(OXTEL_LAYER_1, OXTEL_LAYER_2).Validate(); err != nil {
+		t.Errorf("valid group rejected: %v", err)
+	}
+	if err := NewLayerGroup().Validate(); err == nil {
+		t.Error("expected an empty group to be rejected")
+	}
+	if err := NewLayerGroup(OXTEL_LAYER_1, OXTEL_LAYER_1).Validate(); err == nil {
+		t.Error("expected a duplicate layer to be rejected")
+	}
+	if err := (LayerGroup{Members: []GroupMember{{Layer: OXTEL_LAYER_1, Offset: -1}}}).Validate(); err == nil {
+		t.Error("expected a negative offset to be rejected")
+	}
+}
+
+func TestFadeGroup(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.SetTime(10, 0, 0, 0)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+	if _, err := client.CutGroup(NewLayerGroup(OXTEL_LAYER_1), Timecode{Hours: 10, Seconds: 1}, OXTEL_DIR_TOGGLE); err == nil {
+		t.Error("expected a toggle to be rejected")
+	}
+
+	group := LayerGroup{Members: []GroupMember{
+		{Layer: OXTEL_LAYER_1},
+		{Layer: OXTEL_LAYER_2, Offset: 5},
+		{Layer: OXTEL_LAYER_3},
+	}}
+	rate := uint16(10)
+	fade, err := client.FadeGroup(group, Timecode{Hours: 10, Seconds: 5}, OXTEL_DIR_UP, &rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	scheduled := srv.ScheduledCommands()
+	if len(scheduled) != 3 {
+		t.Fatalf("got %d scheduled commands, want 3", len(scheduled))
+	}
+	// 7 fields round up to 4 frames before the group is on air.
+	if scheduled[0] != "10000426;11 1 a" || scheduled[1] != "10000501;12 1 a" || scheduled[2] != "10000426;13 1 a" {
+		t.Errorf("unexpected scheduled commands %q", scheduled)
+	}
+
+	if err := fade.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	for _, layer := range []uint8{1, 2, 3} {
+		if angle := srv.FaderAngle(layer); angle != 512 {
+			t.Errorf("fader angle of layer %d is %d after the fade", layer, angle)
+		}
+	}
+	for _, tr := range fade.Transitions {
+		if position, err := tr.Wait(); err != nil || position != OXTEL_KEYER_TALLY_UP {
+			t.Errorf("layer %d ended %d, %v", tr.Layer, position, err)
+		}
+	}
+}
@@ -1,10 +1,24 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
 
+// SetSessionLocksCommand is the Command sent by SetSessionLocks. Locks can be built with BuildSessionLocks.
+type SetSessionLocksCommand struct {
+	Locks int32
+}
+
+func (c SetSessionLocksCommand) Encode() string {
+	return fmt.Sprintf("hSL%08x", c.Locks)
+}
+
+func (c SetSessionLocksCommand) Validate() error {
+	return nil
+}
+
 // SetSessionLocks sets the session lock for the specified item for this connection.
 //
 // A session lock is a mechanism that Oxtel clients can use to prevent specific items from being changed via the Oxtel protocol.
@@ -18,21 +32,48 @@ import (
 //
 // Use BuildSessionLocks to get the locks bitwise value.
 func (o *Oxtel) SetSessionLocks(locks int32) error {
-	return o.sendCommand(fmt.Sprintf("hSL%08x", locks))
+	return o.SetSessionLocksContext(context.Background(), locks)
+}
+
+// SetSessionLocksContext is like SetSessionLocks but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetSessionLocksContext(ctx context.Context, locks int32) error {
+	return o.SendContext(ctx, SetSessionLocksCommand{Locks: locks})
 }
 
 // SetSessionLocks_AsString returns the command string used to set the session lock for the specified item for this connection.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetSessionLocksCommand instead.
 func SetSessionLocks_AsString(locks int32) string {
-	return fmt.Sprintf("hSL%08x", locks)
+	return SetSessionLocksCommand{Locks: locks}.Encode()
+}
+
+// EnquireSessionLocksCommand is the Enquiry sent by EnquireSessionLocks.
+type EnquireSessionLocksCommand struct{}
+
+func (c EnquireSessionLocksCommand) Encode() string {
+	return "hSL"
+}
+
+func (c EnquireSessionLocksCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireSessionLocksCommand) ResponsePrefix() string {
+	return "hSL"
 }
 
 // EnquireSessionLocks queries the connection's session locks.
 //
 // Response is a LocksResponse.
 func (o *Oxtel) EnquireSessionLocks() (LocksResponse, error) {
-	val, err := o.sendCommandExpectResponse("hSL", "")
+	return o.EnquireSessionLocksContext(context.Background())
+}
+
+// EnquireSessionLocksContext is like EnquireSessionLocks but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireSessionLocksContext(ctx context.Context) (LocksResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireSessionLocksCommand{})
 	if err != nil {
 		return LocksResponse{}, err
 	}
@@ -69,15 +110,37 @@ func (o *Oxtel) EnquireSessionLocks() (LocksResponse, error) {
 // EnquireSessionLocks_AsString returns the command string used to query the connection's session locks.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireSessionLocksCommand instead.
 func EnquireSessionLocks_AsString() string {
-	return "hSL"
+	return EnquireSessionLocksCommand{}.Encode()
+}
+
+// EnquireGlobalSessionLocksCommand is the Enquiry sent by EnquireGlobalSessionLocks.
+type EnquireGlobalSessionLocksCommand struct{}
+
+func (c EnquireGlobalSessionLocksCommand) Encode() string {
+	return "hGSL"
+}
+
+func (c EnquireGlobalSessionLocksCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireGlobalSessionLocksCommand) ResponsePrefix() string {
+	return "hGSL"
 }
 
 // EnquireGlobalSessionLocks queries the connection's session locks.
 //
 // Response is a LocksResponse.
 func (o *Oxtel) EnquireGlobalSessionLocks() (LocksResponse, error) {
-	val, err := o.sendCommandExpectResponse("hGSL", "")
+	return o.EnquireGlobalSessionLocksContext(context.Background())
+}
+
+// EnquireGlobalSessionLocksContext is like EnquireGlobalSessionLocks but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireGlobalSessionLocksContext(ctx context.Context) (LocksResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireGlobalSessionLocksCommand{})
 	if err != nil {
 		return LocksResponse{}, err
 	}
@@ -114,29 +177,71 @@ func (o *Oxtel) EnquireGlobalSessionLocks() (LocksResponse, error) {
 // EnquireGlobalSessionLocks_AsString returns the command string used to query the connection's session locks.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireGlobalSessionLocksCommand instead.
 func EnquireGlobalSessionLocks_AsString() string {
-	return "hGSL"
+	return EnquireGlobalSessionLocksCommand{}.Encode()
+}
+
+// SetPermanentLocksCommand is the Command sent by SetPermanentLocks.
+type SetPermanentLocksCommand struct {
+	Locks int32
+}
+
+func (c SetPermanentLocksCommand) Encode() string {
+	return fmt.Sprintf("hPL%08x", c.Locks)
+}
+
+func (c SetPermanentLocksCommand) Validate() error {
+	return nil
 }
 
 // SetPermanentLocks sets the permanent lock for the specified item.
 //
 // Use BuildSessionLocks to get the lock bitwise value.
 func (o *Oxtel) SetPermanentLocks(locks int32) error {
-	return o.sendCommand(fmt.Sprintf("hPL%08x", locks))
+	return o.SetPermanentLocksContext(context.Background(), locks)
+}
+
+// SetPermanentLocksContext is like SetPermanentLocks but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetPermanentLocksContext(ctx context.Context, locks int32) error {
+	return o.SendContext(ctx, SetPermanentLocksCommand{Locks: locks})
 }
 
 // SetPermanentLocks_AsString returns the command string used to set the permanent lock for the specified item.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetPermanentLocksCommand instead.
 func SetPermanentLocks_AsString(locks int32) string {
-	return fmt.Sprintf("hPL%08x", locks)
+	return SetPermanentLocksCommand{Locks: locks}.Encode()
+}
+
+// EnquirePermanentLocksCommand is the Enquiry sent by EnquirePermanentLocks.
+type EnquirePermanentLocksCommand struct{}
+
+func (c EnquirePermanentLocksCommand) Encode() string {
+	return "hPL"
+}
+
+func (c EnquirePermanentLocksCommand) Validate() error {
+	return nil
+}
+
+func (c EnquirePermanentLocksCommand) ResponsePrefix() string {
+	return "hPL"
 }
 
 // EnquirePermanentLocks queries the permanent locks.
 //
 // Response is a LocksResponse.
 func (o *Oxtel) EnquirePermanentLocks() (LocksResponse, error) {
-	val, err := o.sendCommandExpectResponse("hPL", "")
+	return o.EnquirePermanentLocksContext(context.Background())
+}
+
+// EnquirePermanentLocksContext is like EnquirePermanentLocks but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquirePermanentLocksContext(ctx context.Context) (LocksResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquirePermanentLocksCommand{})
 	if err != nil {
 		return LocksResponse{}, err
 	}
@@ -172,8 +277,23 @@ func (o *Oxtel) EnquirePermanentLocks() (LocksResponse, error) {
 // EnquirePermanentLocks_AsString returns the command string used to query the permanent locks.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquirePermanentLocksCommand instead.
 func EnquirePermanentLocks_AsString() string {
-	return "hPL"
+	return EnquirePermanentLocksCommand{}.Encode()
+}
+
+// EnableOxtelLockTallyCommand is the Command sent by EnableOxtelLockTally.
+type EnableOxtelLockTallyCommand struct {
+	Enable bool
+}
+
+func (c EnableOxtelLockTallyCommand) Encode() string {
+	return fmt.Sprintf("hOLT%01x", boolToInt(c.Enable))
+}
+
+func (c EnableOxtelLockTallyCommand) Validate() error {
+	return nil
 }
 
 // EnableOxtelLockTally enables or disables the Oxtel Lock Tally for the connection on which the command was received.
@@ -181,21 +301,48 @@ func EnquirePermanentLocks_AsString() string {
 //
 // An unsolicited response will be transmitted for each item and its current lock status.
 func (o *Oxtel) EnableOxtelLockTally(enable bool) error {
-	return o.sendCommand(fmt.Sprintf("hOLT%01x", boolToInt(enable)))
+	return o.EnableOxtelLockTallyContext(context.Background(), enable)
+}
+
+// EnableOxtelLockTallyContext is like EnableOxtelLockTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableOxtelLockTallyContext(ctx context.Context, enable bool) error {
+	return o.SendContext(ctx, EnableOxtelLockTallyCommand{Enable: enable})
 }
 
 // EnableOxtelLockTally_AsString returns the command string used to enable or disable the Oxtel Lock Tally for the connection on which the command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableOxtelLockTallyCommand instead.
 func EnableOxtelLockTally_AsString(enable bool) string {
-	return fmt.Sprintf("hOLT%01x", boolToInt(enable))
+	return EnableOxtelLockTallyCommand{Enable: enable}.Encode()
+}
+
+// EnquireOxtelLockTallyCommand is the Enquiry sent by EnquireOxtelLockTally.
+type EnquireOxtelLockTallyCommand struct{}
+
+func (c EnquireOxtelLockTallyCommand) Encode() string {
+	return "hOLT"
+}
+
+func (c EnquireOxtelLockTallyCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireOxtelLockTallyCommand) ResponsePrefix() string {
+	return "hOLT"
 }
 
 // EnquireOxtelLockTally queries the enable/disable state of the Oxtel Lock tally.
 //
 // Response is a boolean representing if oxtel lock tally is enabled.
 func (o *Oxtel) EnquireOxtelLockTally() (bool, error) {
-	val, err := o.sendCommandExpectResponse("hOLT", "")
+	return o.EnquireOxtelLockTallyContext(context.Background())
+}
+
+// EnquireOxtelLockTallyContext is like EnquireOxtelLockTally but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireOxtelLockTallyContext(ctx context.Context) (bool, error) {
+	val, err := o.EnquireContext(ctx, EnquireOxtelLockTallyCommand{})
 	if err != nil {
 		return false, err
 	}
@@ -206,6 +353,8 @@ func (o *Oxtel) EnquireOxtelLockTally() (bool, error) {
 // EnquireOxtelLockTally_AsString returns the command string used to query the enable/disable state of the Oxtel Lock tally.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireOxtelLockTallyCommand instead.
 func EnquireOxtelLockTally_AsString() string {
-	return "hOLT"
+	return EnquireOxtelLockTallyCommand{}.Encode()
 }
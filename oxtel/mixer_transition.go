@@ -0,0 +1,338 @@
+package oxtel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// mixerEither is the target of a FadeAB started while the mixer is between its inputs, which may end at either.
+const mixerEither = -1
+
+// MixerTransition is an A/B mixer transition started by FadeABAsync, FadeToAAsync, FadeToBAsync,
+// AsymmetricTransitionAsync or FadeToSpecificPositionAsync. It completes when the mixer reaches the destination.
+type MixerTransition struct {
+	// Origin and Destination are the absolute mix positions, from 0 (A) to 512 (B), the transition goes between. A
+	// FadeAB started between the inputs may end at either, so its Destination is left at 0 and Wait reports the input
+	// reached.
+	Origin      uint16
+	Destination uint16
+
+	o     *Oxtel
+	start Timecode
+
+	// onFinish, if set, is called with the outcome before Done is closed.
+	onFinish func(position uint16, err error)
+
+	done       chan struct{}
+	once       sync.Once
+	mu         sync.Mutex
+	position   uint16
+	elapsed    int
+	elapsedErr error
+	err        error
+	cancelled  bool
+}
+
+// Done returns a channel that is closed when the transition completes.
+func (t *MixerTransition) Done() <-chan struct{} {
+	return t.done
+}
+
+// Wait blocks until the transition completes and returns the absolute mix position the mixer ended at.
+//
+// A TransitionReversedError is returned, along with the position, if the mixer went back to the origin instead, such
+// as after Reverse or Cut. A TimeoutError is returned if the mixer has not arrived after 30 seconds.
+func (t *MixerTransition) Wait() (uint16, error) {
+	return t.WaitContext(context.Background())
+}
+
+// WaitContext is like Wait but uses ctx for cancellation and deadlines. The transition carries on if ctx is done first.
+func (t *MixerTransition) WaitContext(ctx context.Context) (uint16, error) {
+	select {
+	case <-t.done:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.position, t.err
+	case <-ctx.Done():
+		return 0, contextError(ctx, "Timed out waiting for the A/B mixer")
+	}
+}
+
+// Elapsed returns the number of frames, by the engine clock, since the transition was started. Once the transition
+// has completed, it is the number of frames it took, or the error enquiring the engine clock returned when it
+// completed. A transition ended by a disconnect has no elapsed time and returns the NotConnectedError.
+func (t *MixerTransition) Elapsed() (int, error) {
+	return t.ElapsedContext(context.Background())
+}
+
+// ElapsedContext is like Elapsed but uses ctx for cancellation and deadlines.
+func (t *MixerTransition) ElapsedContext(ctx context.Context) (int, error) {
+	select {
+	case <-t.done:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return t.elapsed, t.elapsedErr
+	default:
+	}
+	return t.framesSinceStart(ctx)
+}
+
+// Reverse cancels the transition by fading the mixer back to the origin over the specified number of fields
+// (interlaced) or frames (progressive). The transition then completes with a TransitionReversedError. Nothing is sent
+// if the transition has already completed.
+func (t *MixerTransition) Reverse(duration uint16) error {
+	return t.ReverseContext(context.Background(), duration)
+}
+
+// ReverseContext is like Reverse but uses ctx for cancellation and deadlines.
+func (t *MixerTransition) ReverseContext(ctx context.Context, duration uint16) error {
+	return t.cancel(ctx, FadeToSpecificPositionCommand{Destination: t.Origin, Duration: duration})
+}
+
+// Cut cancels the transition by cutting the mixer back to the origin, as described for Reverse.
+func (t *MixerTransition) Cut() error {
+	return t.CutContext(context.Background())
+}
+
+// CutContext is like Cut but uses ctx for cancellation and deadlines.
+func (t *MixerTransition) CutContext(ctx context.Context) error {
+	return t.cancel(ctx, SetAbsoluteMixCommand{Mix: t.Origin})
+}
+
+func (t *MixerTransition) cancel(ctx context.Context, cmd Command) error {
+	select {
+	case <-t.done:
+		return nil
+	default:
+	}
+
+	// The mixer may be back at the origin before it is seen moving, so the origin counts from now on.
+	t.mu.Lock()
+	t.cancelled = true
+	t.mu.Unlock()
+	return t.o.SendContext(ctx, cmd)
+}
+
+func (t *MixerTransition) isCancelled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.cancelled
+}
+
+func (t *MixerTransition) framesSinceStart(ctx context.Context) (int, error) {
+	now, err := t.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	day := framesPerDay(t.start.FieldRate, t.start.Progressive)
+	return ((now.FrameCount()-t.start.FrameCount())%day + day) % day, nil
+}
+
+func (t *MixerTransition) finish(position uint16, err error) {
+	t.once.Do(func() {
+		// Without a connection the engine clock cannot be read, so there is no point waiting for it.
+		elapsed, elapsedErr := 0, err
+		if _, disconnected := err.(*NotConnectedError); !disconnected {
+			elapsed, elapsedErr = t.framesSinceStart(context.Background())
+		}
+
+		t.mu.Lock()
+		t.position, t.elapsed, t.elapsedErr, t.err = position, elapsed, elapsedErr, err
+		t.mu.Unlock()
+
+		if t.onFinish != nil {
+			t.onFinish(position, err)
+		}
+		close(t.done)
+	})
+}
+
+// FadeABAsync is like FadeAB but returns a MixerTransition that completes when the mixer reaches the other input.
+//
+// Completion is taken from the MixerInput of the VideoTally when the video tallies are enabled, and from the mix
+// position reported by EnquireMixMode, which is polled whether they are or not. If the mixer is between its inputs
+// when the fade starts, the transition completes at whichever input it reaches.
+func (o *Oxtel) FadeABAsync(duration uint16) (*MixerTransition, error) {
+	return o.FadeABAsyncContext(context.Background(), duration)
+}
+
+// FadeABAsyncContext is like FadeABAsync but uses ctx for cancellation and deadlines while the fade is started. The
+// transition itself is not bound to ctx.
+func (o *Oxtel) FadeABAsyncContext(ctx context.Context, duration uint16) (*MixerTransition, error) {
+	return o.startMixerTransition(ctx, mixerEither, FadeABCommand{Duration: duration}, nil)
+}
+
+// FadeToAAsync is like FadeToA but returns a MixerTransition, as described for FadeABAsync.
+func (o *Oxtel) FadeToAAsync(duration uint16) (*MixerTransition, error) {
+	return o.FadeToAAsyncContext(context.Background(), duration)
+}
+
+// FadeToAAsyncContext is like FadeToAAsync but uses ctx for cancellation and deadlines while the fade is started.
+func (o *Oxtel) FadeToAAsyncContext(ctx context.Context, duration uint16) (*MixerTransition, error) {
+	return o.startMixerTransition(ctx, 0, FadeToACommand{Duration: duration}, nil)
+}
+
+// FadeToBAsync is like FadeToB but returns a MixerTransition, as described for FadeABAsync.
+func (o *Oxtel) FadeToBAsync(duration uint16) (*MixerTransition, error) {
+	return o.FadeToBAsyncContext(context.Background(), duration)
+}
+
+// FadeToBAsyncContext is like FadeToBAsync but uses ctx for cancellation and deadlines while the fade is started.
+func (o *Oxtel) FadeToBAsyncContext(ctx context.Context, duration uint16) (*MixerTransition, error) {
+	return o.startMixerTransition(ctx, 512, FadeToBCommand{Duration: duration}, nil)
+}
+
+// AsymmetricTransitionAsync is like AsymmetricTransition but returns a MixerTransition, as described for FadeABAsync.
+// The destination must be OXTEL_MIXER_A or OXTEL_MIXER_B.
+func (o *Oxtel) AsymmetricTransitionAsync(destination OxtelMixerInput, downDuration uint16, upDuration uint16) (*MixerTransition, error) {
+	return o.AsymmetricTransitionAsyncContext(context.Background(), destination, downDuration, upDuration)
+}
+
+// AsymmetricTransitionAsyncContext is like AsymmetricTransitionAsync but uses ctx for cancellation and deadlines while
+// the transition is started.
+func (o *Oxtel) AsymmetricTransitionAsyncContext(ctx context.Context, destination OxtelMixerInput, downDuration uint16, upDuration uint16) (*MixerTransition, error) {
+	target := 0
+	switch destination {
+	case OXTEL_MIXER_A:
+	case OXTEL_MIXER_B:
+		target = 512
+	default:
+		return nil, &InvalidParametersError{
+			BaseError: BaseError{
+				Message: "Destination must be the A or B input of the mixer",
+			},
+		}
+	}
+	return o.startMixerTransition(ctx, target, AsymmetricTransitionCommand{Destination: destination, DownDuration: downDuration, UpDuration: upDuration}, nil)
+}
+
+// FadeToSpecificPositionAsync is like FadeToSpecificPosition but returns a MixerTransition, as described for
+// FadeABAsync. A VideoTally only reports the mixer being at A, at B or in between, so completion at other positions is
+// taken from EnquireMixMode alone.
+func (o *Oxtel) FadeToSpecificPositionAsync(destination uint16, duration uint16) (*MixerTransition, error) {
+	return o.FadeToSpecificPositionAsyncContext(context.Background(), destination, duration)
+}
+
+// FadeToSpecificPositionAsyncContext is like FadeToSpecificPositionAsync but uses ctx for cancellation and deadlines
+// while the fade is started.
+func (o *Oxtel) FadeToSpecificPositionAsyncContext(ctx context.Context, destination uint16, duration uint16) (*MixerTransition, error) {
+	return o.startMixerTransition(ctx, int(destination), FadeToSpecificPositionCommand{Destination: destination, Duration: duration}, nil)
+}
+
+// startMixerTransition sends cmd and follows the mixer to target, an absolute mix position or mixerEither for the
+// input opposite to the one visible. onFinish may be nil.
+func (o *Oxtel) startMixerTransition(ctx context.Context, target int, cmd Command, onFinish func(position uint16, err error)) (*MixerTransition, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	mode, err := o.EnquireMixModeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if target == mixerEither {
+		switch mode.ABMixAngle {
+		case 0:
+			target = 512
+		case 512:
+			target = 0
+		}
+	}
+	start, err := o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := o.Subscribe(SubscriptionOptions{
+		Types:    []OxtelTallyType{OXTEL_TALLY_VIDEO},
+		Overflow: OXTEL_OVERFLOW_DROP_OLDEST,
+	})
+	if err := o.SendContext(ctx, cmd); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	t := &MixerTransition{Origin: mode.ABMixAngle, o: o, start: start, onFinish: onFinish, done: make(chan struct{})}
+	if target != mixerEither {
+		t.Destination = uint16(target)
+	}
+	go o.watchMixer(t, sub, target)
+	return t, nil
+}
+
+// watchMixer completes t once the mixer reaches target. As for watchKeyer, a return to the origin only counts once the
+// mixer has been seen moving or the transition has been cancelled.
+func (o *Oxtel) watchMixer(t *MixerTransition, sub *Subscription, target int) {
+	defer sub.Close()
+
+	poll := time.NewTicker(keyerPollInterval)
+	defer poll.Stop()
+	timeout := time.NewTimer(keyerTransitionTimeout)
+	defer timeout.Stop()
+
+	moving := false
+	arrived := func(position uint16) bool {
+		switch {
+		case int(position) == target || (target == mixerEither && (position == 0 || position == 512)):
+			t.finish(position, nil)
+			return true
+		case position != t.Origin:
+			moving = true
+		case moving || t.isCancelled():
+			t.finish(position, &TransitionReversedError{
+				BaseError: BaseError{
+					Message: "A/B mixer transition was reversed",
+				},
+			})
+			return true
+		}
+		return false
+	}
+
+	for {
+		select {
+		case tally, ok := <-sub.C:
+			if !ok {
+				t.finish(t.Origin, &NotConnectedError{
+					BaseError: BaseError{
+						Message: "Disconnected during the A/B mixer transition",
+					},
+				})
+				return
+			}
+			v, ok := tally.(VideoTally)
+			if !ok {
+				continue
+			}
+			switch OxtelMixerInput(v.MixerInput) {
+			case OXTEL_MIXER_A:
+				if arrived(0) {
+					return
+				}
+			case OXTEL_MIXER_B:
+				if arrived(512) {
+					return
+				}
+			default:
+				// A mixer resting between its inputs reports the same, so this only shows movement from A or B.
+				if t.Origin == 0 || t.Origin == 512 {
+					moving = true
+				}
+			}
+		case <-poll.C:
+			mode, err := o.EnquireMixMode()
+			if err == nil && arrived(mode.ABMixAngle) {
+				return
+			}
+		case <-timeout.C:
+			t.finish(t.Origin, &TimeoutError{
+				BaseError: BaseError{
+					Message: "A/B mixer transition did not complete",
+				},
+			})
+			return
+		}
+	}
+}
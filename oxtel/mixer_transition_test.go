@@ -0,0 +1,128 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+// waitForMixerMove waits for the mixer of the fake engine to move away from origin.
+func waitForMixerMove(t *testing.T, srv *oxteltest.Server, origin uint16) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.MixerPosition() == origin {
+		if time.Now().After(deadline) {
+			t.Fatalf("mixer did not move from %d", origin)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMixerTransition(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	if err := client.EnableVideoTallies(true); err != nil {
+		t.Fatal(err)
+	}
+
+	fade, err := client.FadeToBAsync(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := fade.Wait(); err != nil || position != 512 {
+		t.Fatalf("fade to B ended at %d, %v", position, err)
+	}
+	if elapsed, err := fade.Elapsed(); err != nil || elapsed < 20 {
+		t.Errorf("fade of 20 frames took %d frames, %v", elapsed, err)
+	}
+
+	back, err := client.FadeABAsync(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := back.Wait(); err != nil || position != 0 {
+		t.Fatalf("fade A/B ended at %d, %v", position, err)
+	}
+
+	partial, err := client.FadeToSpecificPositionAsync(200, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := partial.Wait(); err != nil || position != 200 {
+		t.Fatalf("fade to 200 ended at %d, %v", position, err)
+	}
+
+	// Reversing fades back to where the transition started.
+	slow, err := client.FadeToBAsync(500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForMixerMove(t, srv, 200)
+	if err := slow.Reverse(10); err != nil {
+		t.Fatal(err)
+	}
+	position, err := slow.Wait()
+	if _, ok := err.(*TransitionReversedError); !ok || position != 200 {
+		t.Errorf("reversed fade ended at %d, %v", position, err)
+	}
+
+	if _, err := client.AsymmetricTransitionAsync(OXTEL_MIXER_IN_BETWEEN, 10, 10); err == nil {
+		t.Error("expected an in-between destination to be rejected")
+	}
+}
+
+func TestMixerTransitionPolling(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	// Without video tallies, completion comes from polling the mix position.
+	take, err := client.AsymmetricTransitionAsync(OXTEL_MIXER_B, 200, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForMixerMove(t, srv, 0)
+	if err := take.Cut(); err != nil {
+		t.Fatal(err)
+	}
+	position, err := take.Wait()
+	if _, ok := err.(*TransitionReversedError); !ok || position != 0 {
+		t.Errorf("cut transition ended at %d, %v", position, err)
+	}
+
+	fade, err := client.FadeToBAsync(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-fade.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("fade did not complete")
+	}
+	if position, err := fade.Wait(); err != nil || position != 512 {
+		t.Errorf("fade to B ended at %d, %v", position, err)
+	}
+}
+
+func TestMixerTransitionDisconnect(t *testing.T) {
+	_, client := connectTallyClient(t)
+
+	fade, err := client.FadeToBAsync(500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Disconnect()
+
+	select {
+	case <-fade.Done():
+	case <-time.After(DefaultResponseTimeout / 2):
+		t.Fatal("transition did not complete on disconnect")
+	}
+	if _, err := fade.Wait(); err == nil {
+		t.Fatal("expected an error after a disconnect")
+	} else if _, ok := err.(*NotConnectedError); !ok {
+		t.Fatalf("expected a NotConnectedError, got %T", err)
+	}
+	if _, err := fade.Elapsed(); err == nil {
+		t.Error("expected no elapsed time after a disconnect")
+	}
+}
@@ -3,26 +3,44 @@ package oxtel
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
-	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// DefaultResponseTimeout is how long an enquiry waits for its response when the context passed to it has no deadline.
+// It also applies to the methods without a Context suffix.
+const DefaultResponseTimeout = 5 * time.Second
+
 type Oxtel struct {
 	address     string
 	port        uint16
+	mu          sync.Mutex
+	writeMu     sync.Mutex
 	conn        net.Conn
-	reader      bufio.Reader
-	rxMessages  chan string
-	lastCommand string
+	pending     []*pendingRequest
 	Unsolicited chan interface{}
+	closed      bool
 	closeOnce   sync.Once
 	ctx         context.Context
 	cancelFunc  context.CancelFunc
+
+	reconnectPolicy *ReconnectPolicy
+	stateCallbacks  []*stateCallback
+	armed           armedState
+	timing          scheduleTiming
+	ledger          *ScheduleLedger
+	listMu          sync.Mutex
+	schemas         *SchemaRegistry
+	textPacketSize  int
+
+	tallies            tallyBus
+	unsolicitedDropped atomic.Uint64
 }
 
 func NewOxtel(address string, port uint16) *Oxtel {
@@ -30,473 +48,314 @@ func NewOxtel(address string, port uint16) *Oxtel {
 		address:     address,
 		port:        port,
 		conn:        nil,
-		rxMessages:  make(chan string),
 		Unsolicited: make(chan interface{}),
 	}
 }
 
 func (o *Oxtel) Connect() error {
-	address := net.JoinHostPort(o.address, fmt.Sprintf("%d", o.port))
-
-	c, err := net.Dial("tcp", address)
+	c, err := o.dial()
 	if err != nil {
 		return err
 	}
 
+	o.mu.Lock()
 	o.conn = c
-	o.reader = *bufio.NewReader(c)
-
 	o.ctx, o.cancelFunc = context.WithCancel(context.Background())
+	o.mu.Unlock()
+	o.timing.clear()
 
-	go o.rxLoop()
+	go o.rxLoop(c)
+	o.notifyConnectionState(OXTEL_CONNECTION_STATE_CONNECTED, nil)
 	return nil
 }
 
+func (o *Oxtel) dial() (net.Conn, error) {
+	address := net.JoinHostPort(o.address, fmt.Sprintf("%d", o.port))
+
+	return net.Dial("tcp", address)
+}
+
 func (o *Oxtel) Disconnect() error {
-	if o.conn == nil {
+	o.mu.Lock()
+	c := o.conn
+	o.conn = nil
+	started := o.cancelFunc != nil
+	o.mu.Unlock()
+
+	if !started {
 		return nil
 	}
 
 	o.closeOnce.Do(func() {
-		if o.cancelFunc != nil {
-			o.cancelFunc()
-		}
+		o.cancelFunc()
 
-		close(o.rxMessages)
+		o.mu.Lock()
+		o.closed = true
 		close(o.Unsolicited)
-		_ = o.conn.Close()
+		o.mu.Unlock()
+
+		o.tallies.close()
 	})
+	o.failPending("Disconnected from the Oxtel engine")
 
-	o.conn = nil
+	if c != nil {
+		_ = c.Close()
+	}
 	return nil
 }
 
-func (o *Oxtel) rxLoop() {
+func (o *Oxtel) rxLoop(c net.Conn) {
+	reader := bufio.NewReader(c)
 	for {
-		select {
-		case <-o.ctx.Done():
-			return
-		default:
-
-			line, err := o.reader.ReadString(':')
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				o.Disconnect()
-				break
+		line, err := reader.ReadString(':')
+		if err != nil {
+			if o.ctx.Err() != nil {
+				return
 			}
+			o.connectionLost(c, err)
+			return
+		}
 
-			line = strings.TrimSpace(line)
-			if len(line) > 0 {
-				o.handleMessage(line)
-			}
+		line = strings.TrimSpace(line)
+		if len(line) > 0 {
+			o.handleMessage(line)
 		}
 	}
 }
 
 func (o *Oxtel) handleMessage(message string) {
-	if len(o.lastCommand) > 0 && message[:len(o.lastCommand)] == o.lastCommand {
-		o.rxMessages <- message
+	if req := o.takePending(message); req != nil {
+		req.response <- message
 	} else {
 		cleanMessage := message[:len(message)-1]
-		// Non-blocking channel sending
-		var outval interface{}
-
-		if message[0] == '3' {
-			data := cleanMessage[1:]
-			layer, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer from KeyerPositionTally")
-			}
-			direction, err := strconv.ParseUint(string(data[2]), 10, 8)
-			if err != nil {
-				panic("unable to parse direction from KeyerPositionTally")
-			}
-
-			outval = KeyerPositionTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				Layer:     OxtelLayer(layer),
-				Direction: OxtelDirection(direction),
-			}
-		} else if message[:2] == "Y9" {
-			data := cleanMessage[2:]
-			layer, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer from ImageLoadTally")
-			}
-
-			filename := data[1:]
-
-			outval = ImageLoadTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				Layer:    OxtelLayer(layer),
-				Template: filename,
-			}
-		} else if message[:2] == "YA" {
-			data := cleanMessage[2:]
-			layer, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer from ImagePreloadTally")
-			}
-
-			filename := data[1:]
-
-			outval = ImagePreloadTally{
+		outval, err := decodeUnsolicited(cleanMessage)
+		if err != nil {
+			outval = MalformedTally{
 				UnsolicitedMessage: UnsolicitedMessage{
 					Raw: cleanMessage,
 				},
-				Layer:    OxtelLayer(layer),
-				Template: filename,
-			}
-		} else if message[:2] == "YB" {
-			data := cleanMessage[2:]
-
-			u1, err := strconv.ParseBool(string(data[0]))
-			if err != nil {
-				panic("unable to parse unused1 from MediaTallies")
-			}
-
-			u2, err := strconv.ParseBool(string(data[1]))
-			if err != nil {
-				panic("unable to parse unused2 from MediaTallies")
+				Err: err,
 			}
-
-			u3, err := strconv.ParseBool(string(data[2]))
-			if err != nil {
-				panic("unable to parse unused3 from MediaTallies")
-			}
-
-			u4, err := strconv.ParseBool(string(data[3]))
-			if err != nil {
-				panic("unable to parse unused4 from MediaTallies")
+		} else if outval == nil {
+			outval = UnsolicitedMessage{
+				Raw: message,
 			}
+		}
 
-			u5, err := strconv.ParseBool(string(data[4]))
-			if err != nil {
-				panic("unable to parse unused5 from MediaTallies")
-			}
+		o.tallies.publish(outval)
 
-			images, err := strconv.ParseBool(string(data[5]))
-			if err != nil {
-				panic("unable to parse images from MediaTallies")
+		// Send to the channel non-blocking
+		o.mu.Lock()
+		if !o.closed {
+			select {
+			case o.Unsolicited <- outval:
+			default:
+				o.unsolicitedDropped.Add(1)
 			}
+		}
+		o.mu.Unlock()
+	}
+}
 
-			action, err := strconv.ParseUint(string(data[6]), 16, 8)
-			if err != nil {
-				panic("unable to parse action from MediaTallies")
-			}
+// DroppedUnsolicited returns the number of messages dropped because nobody was receiving from the Unsolicited
+// channel. Use Subscribe to queue tallies instead.
+func (o *Oxtel) DroppedUnsolicited() uint64 {
+	return o.unsolicitedDropped.Load()
+}
 
-			filename := data[7:]
+func (o *Oxtel) sendCommand(cmd string) error {
+	return o.sendCommandContext(context.Background(), cmd)
+}
 
-			outval = MediaTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				MediaType: MediaTallies{
-					Unused1: u1,
-					Unused2: u2,
-					Unused3: u3,
-					Unused4: u4,
-					Unused5: u5,
-					Images:  images,
-				},
-				Action:   OxtelMediaTallies(action),
-				Filename: filename,
-			}
-		} else if message[:2] == "YS" {
-			data := cleanMessage[2:]
-			layer, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer from PlayStateTally")
-			}
+func (o *Oxtel) sendCommandContext(ctx context.Context, cmd string) error {
+	o.writeMu.Lock()
+	defer o.writeMu.Unlock()
 
-			state, err := strconv.ParseUint(string(data[1]), 16, 8)
-			if err != nil {
-				panic("unable to parse state from PlayStateTally")
-			}
+	return o.write(ctx, cmd)
+}
 
-			outval = PlayStateTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				Layer: OxtelLayer(layer),
-				State: OxtelPlayStateTally(state),
-			}
-		} else if message[:2] == "Y6" {
-			data := cleanMessage[2:]
-			abmix, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse a/b mix from VideoTally")
-			}
-			layer0, err := strconv.ParseUint(string(data[1]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 0 keyer from VideoTally")
-			}
-			layer1, err := strconv.ParseUint(string(data[2]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 1 keyer from VideoTally")
-			}
-			mixerA, err := strconv.ParseUint(string(data[3]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 0 keyer from VideoTally")
-			}
-			mixerB, err := strconv.ParseUint(string(data[4]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 0 keyer from VideoTally")
-			}
-			u1, err := strconv.ParseUint(string(data[5:7]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 0 keyer from VideoTally")
-			}
-			u2, err := strconv.ParseUint(string(data[7:9]), 16, 8)
-			if err != nil {
-				panic("unable to parse layer 0 keyer from VideoTally")
-			}
+// write escapes the commands and writes them in a single write. o.writeMu must be held.
+func (o *Oxtel) write(ctx context.Context, cmds ...string) error {
+	if ctx.Err() != nil {
+		return contextError(ctx, "Cancelled before sending command")
+	}
 
-			outval = VideoTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				MixerInput:   uint8(abmix),
-				Layer0:       OxtelDirection(layer0),
-				Layer1:       OxtelDirection(layer1),
-				MixerASource: OxtelVideoSource(mixerA),
-				MixerBSource: OxtelVideoSource(mixerB),
-				Unused1:      uint8(u1),
-				Unused2:      uint8(u2),
-			}
-		} else if message[:3] == "jAY" {
-			data := cleanMessage[3:]
-			source, err := strconv.ParseUint(string(data[0]), 16, 8)
-			if err != nil {
-				panic("unable to parse source from AudioProfileTally")
-			}
-			profile, err := strconv.ParseUint(string(data[1:3]), 16, 8)
-			if err != nil {
-				panic("unable to parse profile from AudioProfileTally")
-			}
+	var b strings.Builder
+	for _, cmd := range cmds {
+		b.WriteString(escapeCommand(cmd))
+		b.WriteString(":")
+	}
+	cmdBytes := []byte(b.String())
+
+	o.mu.Lock()
+	c := o.conn
+	reconnect := o.reconnectPolicy != nil
+	o.mu.Unlock()
+
+	if c == nil {
+		return &NotConnectedError{
+			BaseError: BaseError{
+				Message: "Not connected to the Oxtel engine",
+			},
+		}
+	}
 
-			outval = AudioProfileTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				Source:  OxtelAudioSource(source),
-				Profile: uint8(profile),
-			}
-		} else if message[:4] == "hOLY" {
-			data := cleanMessage[4:]
-			session, err := strconv.ParseUint(string(data[:8]), 16, 32)
-			if err != nil {
-				panic("unable to parse session locks from LockTally")
-			}
-			permanent, err := strconv.ParseUint(string(data[8:16]), 16, 32)
-			if err != nil {
-				panic("unable to parse permanent lock from LockTally")
-			}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.SetWriteDeadline(deadline)
+		defer c.SetWriteDeadline(time.Time{})
+	}
 
-			sMixer := (session & 0x00000001) != 0
-			sLayer0 := (session & 0x00000100) != 0
-			sLayer1 := (session & 0x00000200) != 0
-			sLayer2 := (session & 0x00000400) != 0
-			sLayer3 := (session & 0x00000800) != 0
-			sLayer4 := (session & 0x00001000) != 0
-			sLayer5 := (session & 0x00002000) != 0
-			sLayer6 := (session & 0x00004000) != 0
-			sLayer7 := (session & 0x00008000) != 0
-
-			pMixer := (permanent & 0x00000001) != 0
-			pLayer0 := (permanent & 0x00000100) != 0
-			pLayer1 := (permanent & 0x00000200) != 0
-			pLayer2 := (permanent & 0x00000400) != 0
-			pLayer3 := (permanent & 0x00000800) != 0
-			pLayer4 := (permanent & 0x00001000) != 0
-			pLayer5 := (permanent & 0x00002000) != 0
-			pLayer6 := (permanent & 0x00004000) != 0
-			pLayer7 := (permanent & 0x00008000) != 0
-
-			outval = LockTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				SessionLocks: LocksResponse{
-					Mixer:  sMixer,
-					Layer0: sLayer0,
-					Layer1: sLayer1,
-					Layer2: sLayer2,
-					Layer3: sLayer3,
-					Layer4: sLayer4,
-					Layer5: sLayer5,
-					Layer6: sLayer6,
-					Layer7: sLayer7,
-				},
-				PermanentLocks: LocksResponse{
-					Mixer:  pMixer,
-					Layer0: pLayer0,
-					Layer1: pLayer1,
-					Layer2: pLayer2,
-					Layer3: pLayer3,
-					Layer4: pLayer4,
-					Layer5: pLayer5,
-					Layer6: pLayer6,
-					Layer7: pLayer7,
-				},
-			}
-		} else if message[:4] == "hXSY" {
-			data := cleanMessage[4:]
-			direction, err := strconv.ParseUint(string(data[:2]), 16, 8)
-			if err != nil {
-				panic("unable to parse direction from ExternalIOSourceChangedTally")
-			}
-			ioID, err := strconv.ParseUint(string(data[2:4]), 16, 8)
-			if err != nil {
-				panic("unable to parse io id from ExternalIOSourceChangedTally")
-			}
-			ioType, err := strconv.ParseUint(string(data[4:6]), 16, 8)
-			if err != nil {
-				panic("unable to parse io type from ExternalIOSourceChangedTally")
-			}
-			configId, err := strconv.ParseUint(string(data[6:8]), 16, 8)
-			if err != nil {
-				panic("unable to parse config id from ExternalIOSourceChangedTally")
-			}
-			state, err := strconv.ParseUint(string(data[8:10]), 16, 8)
-			if err != nil {
-				panic("unable to parse state from ExternalIOSourceChangedTally")
-			}
+	_, err := c.Write(cmdBytes)
+	if err != nil {
+		if err == io.EOF && !reconnect {
+			o.Disconnect()
+		}
+		if ctx.Err() != nil {
+			return contextError(ctx, "Timed out sending command")
+		}
+	}
+	return err
+}
 
-			outval = ExternalIOSourceChangedTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				IODirection:     OxtelExternalIODirection(direction),
-				IOId:            OxtelExternalIOId(ioID),
-				IOType:          OxtelExternalIOType(ioType),
-				ConfigurationId: uint8(configId),
-				State:           uint8(state),
-			}
-		} else if message[:5] == "hXDCY" {
-			data := cleanMessage[5:]
-			direction, err := strconv.ParseUint(string(data[:2]), 16, 8)
-			if err != nil {
-				panic("unable to parse direction from ExternalIODynamicConfigChangedTally")
-			}
-			ioId, err := strconv.ParseUint(string(data[2:4]), 16, 8)
-			if err != nil {
-				panic("unable to parse io id from ExternalIODynamicConfigChangedTally")
-			}
-			ioType, err := strconv.ParseUint(string(data[4:6]), 16, 8)
-			if err != nil {
-				panic("unable to parse io type from ExternalIODynamicConfigChangedTally")
-			}
+// pendingRequest is an enquiry waiting for its response. Responses echo the enquiry prefix, so they are matched to
+// pending requests by prefix, in the order the requests were sent.
+type pendingRequest struct {
+	prefix string
+	// matches, if set, must also accept the data following prefix for a message to be the response.
+	matches  func(data string) bool
+	response chan string
+	err      chan error
+}
 
-			parts := strings.Split(data, ",")
+// takePending removes and returns the oldest pending request with the longest prefix matching message, or nil when
+// the message is unsolicited. A request whose enquiry tells its response apart from a tally of the same prefix only
+// matches a response.
+func (o *Oxtel) takePending(message string) *pendingRequest {
+	o.mu.Lock()
+	defer o.mu.Unlock()
 
-			var localInterface *string
-			var ipAddress *string
-			var port *uint32
-			var ipAddress2 *string
-			var port2 *uint32
-			var sdfFileName *string
+	match := -1
+	for i, req := range o.pending {
+		if !req.accepts(message) {
+			continue
+		}
+		if match < 0 || len(req.prefix) > len(o.pending[match].prefix) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil
+	}
 
-			if ioType != OXTEL_EXT_IO_TYPE_SDI {
-				localInterface = &parts[1]
-			}
-			if ioType == OXTEL_EXT_IO_TYPE_2022_6 || ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
-				ipAddress = &parts[2]
-				tmpPort, err := strconv.ParseUint(parts[3], 10, 32)
-				if err != nil {
-					panic("unable to parse port from ExternalIODynamicConfigChangedTally")
-				}
-				w := uint32(tmpPort)
-				port = &w
-			}
-			if ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
-				ipAddress2 = &parts[4]
-				tmpPort, err := strconv.ParseUint(parts[5], 10, 32)
-				if err != nil {
-					panic("unable to parse port2 from ExternalIODynamicConfigChangedTally")
-				}
-				w := uint32(tmpPort)
-				port2 = &w
-			}
+	req := o.pending[match]
+	o.pending = append(o.pending[:match], o.pending[match+1:]...)
+	return req
+}
 
-			if ioType == OXTEL_EXT_IO_TYPE_2110 {
-				sdfFileName = &parts[2]
-			}
+// accepts reports whether message, with its terminator, is a response to req.
+func (req *pendingRequest) accepts(message string) bool {
+	if !strings.HasPrefix(message, req.prefix) {
+		return false
+	}
+	if req.matches == nil {
+		return true
+	}
+	return req.matches(strings.TrimSuffix(message[len(req.prefix):], ":"))
+}
 
-			outval = ExternalIODynamicConfigChangedTally{
-				UnsolicitedMessage: UnsolicitedMessage{
-					Raw: cleanMessage,
-				},
-				IODirection:    OxtelExternalIODirection(direction),
-				IOId:           OxtelExternalIOId(ioId),
-				IOType:         OxtelExternalIOType(ioType),
-				LocalInterface: localInterface,
-				IPAddress:      ipAddress,
-				Port:           port,
-				IPAddress2:     ipAddress2,
-				Port2:          port2,
-				SDPFileName:    sdfFileName,
-			}
-		} else {
-			outval = UnsolicitedMessage{
-				Raw: message,
-			}
-		}
+// removePending removes a request that gave up waiting. It returns false if a response was already delivered.
+func (o *Oxtel) removePending(req *pendingRequest) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
 
-		// Send to the channel non-blocking
-		select {
-		case o.Unsolicited <- outval:
-		default:
+	for i, r := range o.pending {
+		if r == req {
+			o.pending = append(o.pending[:i], o.pending[i+1:]...)
+			return true
 		}
 	}
+	return false
 }
 
-func (o *Oxtel) sendCommand(cmd string) error {
-	escapedCmd := strings.ReplaceAll(cmd, "\\", "\\5C")
-	escapedCmd = strings.ReplaceAll(escapedCmd, "|", "\\7C")
-	escapedCmd = strings.ReplaceAll(escapedCmd, ";", "\\3B")
-	escapedCmd = strings.ReplaceAll(escapedCmd, ":", "\\3A")
-
-	escapedCmd += ":"
-	cmdBytes := []byte(escapedCmd)
+// failPending fails every request still waiting for a response, as a closed connection will not deliver them.
+func (o *Oxtel) failPending(message string) {
+	o.mu.Lock()
+	pending := o.pending
+	o.pending = nil
+	o.mu.Unlock()
 
-	_, err := o.conn.Write(cmdBytes)
-	if err != nil {
-		if err == io.EOF {
-			o.Disconnect()
+	for _, req := range pending {
+		req.err <- &NotConnectedError{
+			BaseError: BaseError{
+				Message: message,
+			},
 		}
 	}
-	return err
 }
 
-func (o *Oxtel) sendCommandExpectResponse(cmd string, data string) (string, error) {
-	o.lastCommand = cmd
-	err := o.sendCommand(cmd + data)
+// sendCommandExpectResponseContext sends an enquiry and waits for the response echoing prefix. The returned data
+// follows the prefix. When ctx has no deadline, DefaultResponseTimeout applies.
+//
+// Enquiries may be sent concurrently. A request that times out is forgotten, so a response arriving after its deadline
+// is treated as unsolicited.
+func (o *Oxtel) sendCommandExpectResponseContext(ctx context.Context, prefix string, matches func(data string) bool, cmd string) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultResponseTimeout)
+		defer cancel()
+	}
+
+	req := &pendingRequest{
+		prefix:   prefix,
+		matches:  matches,
+		response: make(chan string, 1),
+		err:      make(chan error, 1),
+	}
+
+	// Registering and writing under writeMu keeps the pending queue in the order the engine receives the enquiries.
+	o.writeMu.Lock()
+	o.mu.Lock()
+	o.pending = append(o.pending, req)
+	o.mu.Unlock()
+	err := o.write(ctx, cmd)
+	o.writeMu.Unlock()
+
 	if err != nil && err != io.EOF {
+		o.removePending(req)
 		return "", err
 	}
 
-	timeout := time.After(5 * time.Second)
-	for {
-		select {
-		case unsolicited := <-o.rxMessages:
-			if unsolicited[:len(cmd)] == cmd {
-				return unsolicited[len(cmd) : len(unsolicited)-1], nil
-			}
-		case <-timeout:
-			return "", &TimeoutError{
-				BaseError: BaseError{
-					Message: "Timed out waiting for response",
-				},
+	select {
+	case response := <-req.response:
+		return response[len(prefix) : len(response)-1], nil
+	case err := <-req.err:
+		return "", err
+	case <-ctx.Done():
+		if !o.removePending(req) {
+			select {
+			case response := <-req.response:
+				return response[len(prefix) : len(response)-1], nil
+			case err := <-req.err:
+				return "", err
 			}
 		}
+		return "", contextError(ctx, "Timed out waiting for response")
+	}
+}
+
+// contextError converts the error of a done context. A deadline becomes a TimeoutError wrapping
+// context.DeadlineExceeded; cancellation is returned as is.
+func contextError(ctx context.Context, message string) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{
+			BaseError: BaseError{
+				Message: message,
+			},
+			Err: err,
+		}
 	}
+	return err
 }
 
 func (o *Oxtel) NewOxtelLayer(value uint8) OxtelLayer {
@@ -594,3 +453,7 @@ func (o *Oxtel) NewOxtelExternalIODirection(value uint8) OxtelExternalIODirectio
 func (o *Oxtel) NewOxtelExternalIOId(value uint8) OxtelExternalIOId {
 	return OxtelExternalIOId(value)
 }
+
+func (o *Oxtel) NewOxtelConnectionState(value uint8) OxtelConnectionState {
+	return OxtelConnectionState(value)
+}
@@ -2,18 +2,33 @@ package oxtel
 
 import (
 	"testing"
-)
+	"time"
 
-var oxtel = NewOxtel("10.43.192.11", 9100)
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
 
 const templateName = "1080i60-EAS-Warning.swf"
 const layer = OXTEL_LAYER_0
 
+// connectTestClient is connectTallyClient with templateName added to the engine.
+func connectTestClient(t *testing.T) (*oxteltest.Server, *Oxtel) {
+	t.Helper()
+
+	srv, client := connectTallyClient(t)
+	srv.AddFile(templateName)
+	return srv, client
+}
+
 func TestConnection(t *testing.T) {
-	err := oxtel.Connect()
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	err := client.Connect()
 	if err != nil {
 		t.Fatal(err)
 	}
+	defer client.Disconnect()
 }
 
 func TestChannelMasks(t *testing.T) {
@@ -24,389 +39,435 @@ func TestChannelMasks(t *testing.T) {
 	}
 }
 
-// func TestEnquireMediaTallies(t *testing.T) {
-// 	val, err := oxtel.EnquireMediaTallies()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Images {
-// 		t.Fatal("Media Tallies should be off")
-// 	}
-// }
-
-// func TestMediaTallies(t *testing.T) {
-// 	s := MediaTallies{
-// 		Images: true,
-// 	}
-// 	err := oxtel.EnableMediaTallies(s)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquirePlayStateTally(t *testing.T) {
-// 	val, err := oxtel.EnquirePlayStateTally()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val {
-// 		t.Fatal("Play State Tally should be off")
-// 	}
-// }
-
-// func TestEnablePlayStateTally(t *testing.T) {
-// 	err := oxtel.EnablePlayStateTally(true)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquireFileInfo(t *testing.T) {
-// 	val, err := oxtel.EnquireFileInfo(templateName)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if !val.Exists || val.Filename != templateName {
-// 		t.Fatalf("Unexpected file info response: %v", val)
-// 	}
-// }
-
-// func TestEnquireExtendedFileInfo(t *testing.T) {
-// 	val, err := oxtel.EnquireExtendedFileInformation(templateName)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if !val.Exists || val.Filename != templateName {
-// 		t.Fatalf("Unexpected file info response: %v", val)
-// 	}
-// }
-
-// func TestValidateTemplate(t *testing.T) {
-// 	val, err := oxtel.ValidateTemplate(templateName)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Filename != templateName || !val.FileExists {
-// 		t.Fatal("validate template failed")
-// 	}
-// }
-
-// func TestPreloadImage(t *testing.T) {
-// 	err := oxtel.PreloadImage(layer, templateName)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquirePreloadImage(t *testing.T) {
-// 	fmt.Println("Sleeping to ensure Oxtel processes the preload command")
-// 	time.Sleep(3 * time.Second)
-
-// 	val, err := oxtel.EnquirePreloadImage(layer)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Layer != 0 || val.Filename != templateName {
-// 		t.Fatalf("Incorrect template preloaded: %v", val)
-// 	}
-// }
-
-// func TestLoadImage(t *testing.T) {
-// 	err := oxtel.LoadImage(0, "1080i60-EAS-Warning.swf")
-
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquireLoadImage(t *testing.T) {
-// 	fmt.Println("Sleeping to ensure Oxtel processes the load command")
-// 	time.Sleep(3 * time.Second)
-
-// 	val, err := oxtel.EnquireLoadImage(layer)
-
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Layer != 0 || val.Filename != templateName {
-// 		t.Fatalf("Incorrect template loaded: %v", val)
-// 	}
-// }
-
-// func TestSetImagePosition(t *testing.T) {
-// 	err := oxtel.SetImagePosition(layer, 10, 10)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquireImagePosition(t *testing.T) {
-// 	fmt.Println("Sleeping to ensure Oxtel processes the set position command")
-// 	time.Sleep(3 * time.Second)
-
-// 	val, err := oxtel.EnquireImagePosition(layer)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Layer != 0 || val.XOffset != 10 || val.YOffset != 10 {
-// 		t.Fatalf("Invalid position image: %v", val)
-// 	}
-// }
-
-// func TestEraseStore(t *testing.T) {
-// 	err := oxtel.EraseStore(layer)
-
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestSelectMixerInput(t *testing.T) {
-// 	arc := OXTEL_ARC_DEFAULT
-// 	err := oxtel.SelectMixerInput(0, OXTEL_VIDEO_SOURCE_EXT_IN_3, &arc)
-
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// }
-
-// func TestEnquireMixerInput(t *testing.T) {
-// 	time.Sleep(2 * time.Second)
-// 	val, err := oxtel.EnquireMixerInput(0)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Input != 0 || val.Source != OXTEL_VIDEO_SOURCE_EXT_IN_3 {
-// 		t.Fatalf("Invalid mixer input %v", val)
-// 	}
-// }
-
-// func TestEnquireMixMode(t *testing.T) {
-// 	val, err := oxtel.EnquireMixMode()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.WipeSoftness != 0 {
-// 		t.Fatalf("Invalid mix mode response %v", val)
-// 	}
-// }
-
-// func TestSetColorGenerator(t *testing.T) {
-// 	err := oxtel.SetColorGeneratorColor(0, 255, 0, 0)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquireColorGeneratorColor(t *testing.T) {
-// 	time.Sleep(2 * time.Second)
-// 	val, err := oxtel.EnquireColorGeneratorColor(0)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Unit != 0 || val.Red != 255 || val.Green != 0 || val.Blue != 0 {
-// 		t.Fatalf("Invalid color generate response %v", val)
-// 	}
-// }
-
-// func TestEnquireVideoTallies(t *testing.T) {
-// 	val, err := oxtel.EnquireVideoTallies()
-
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val {
-// 		t.Fatal("video tally should be false")
-// 	}
-// }
-
-// func TestEnquireAudioProfile(t *testing.T) {
-// 	val, err := oxtel.EnquireAudioProfile(0)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Profile != 0 || val.Source != 0 {
-// 		t.Fatalf("invalid audio profile %v", val)
-// 	}
-// }
-
-// func TestAudioFollowingVideo(t *testing.T) {
-// 	err := oxtel.SetAudioABFollowVideoAB(true)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestGetLoudness(t *testing.T) {
-// 	val, err := oxtel.GetAudioLoudness(0, nil)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Channel1 {
-// 		t.Fatal("audio loudness program failed")
-// 	}
-// }
-
-// func TestGetLoudnessLicenseStatus(t *testing.T) {
-// 	val, err := oxtel.GetLoudnessLicenseStatus()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	_ = val
-// }
-
-// func TestEnquireAudioFollowingVideo(t *testing.T) {
-// 	time.Sleep(2 * time.Second)
-// 	val, err := oxtel.EnquireAudioABFollowVideoAB()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if !val.Enabled {
-// 		t.Fatal("audio should be following video")
-// 	}
-// }
-
-// func TestEnquireAudioGain(t *testing.T) {
-// 	mask := MakeEightChannelMask()
-// 	val, err := oxtel.EnquireAudioGain(OXTEL_AUDIO_OUTPUT_EXT_IN_3, mask)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Source != OXTEL_AUDIO_SOURCE_EXT_IN_3 || val.Gain != 0 {
-// 		t.Fatal("audio gain failed")
-// 	}
-// }
-
-// func TestEnquireLatency(t *testing.T) {
-// 	val, err := oxtel.EnquireLatency(OXTEL_LATENCY_SOURCE_OXTEL)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Source != OXTEL_LATENCY_SOURCE_OXTEL {
-// 		t.Fatal("latency response failed")
-// 	}
-// }
-
-// func TestEnquireNumberOfGraphicLayers(t *testing.T) {
-// 	val, err := oxtel.EnquireNumberOfGraphicLayers()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val != 8 {
-// 		t.Fatal("number of graphic layers failed")
-// 	}
-// }
-
-// func TestEnquireSystemStatus(t *testing.T) {
-// 	val, err := oxtel.EnquireSystemStatus()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.VideoStandard != OXTEL_VIDEO_STANDARD_1080I_5994 {
-// 		t.Fatal("system status failed")
-// 	}
-
-// }
-
-// func TestFadeKeyer(t *testing.T) {
-// 	err := oxtel.FadeKeyer(OXTEL_LAYER_0, OXTEL_DIR_UP, nil)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-// }
-
-// func TestEnquireVideoLayerStatus(t *testing.T) {
-// 	time.Sleep(2 * time.Second)
-// 	val, err := oxtel.EnquireVideoLayerStatus(OXTEL_LAYER_0)
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	oxtel.FadeKeyer(OXTEL_LAYER_0, OXTEL_DIR_DOWN, nil)
-
-// 	if val.LayerFaderAngle != 512 {
-// 		t.Fatal("video layer status failed")
-// 	}
-// }
-
-// func TestEnquireCommandAvailability(t *testing.T) {
-// 	val, err := oxtel.EnquireCommandAvailability('X', '3')
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.CommandByte1 != 'X' || val.CommandByte2 != '3' || !val.Supported {
-// 		t.Fatal("command availability failed")
-// 	}
-// }
-
-// func TestEnquireSlaveLayerStatus(t *testing.T) {
-// 	val, err := oxtel.EnquireSlaveLayerStatus()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val.Layer7State {
-// 		t.Fatal("layer status failed")
-// 	}
-// }
-
-// func TestEnquireFullVersionNumber(t *testing.T) {
-// 	val, err := oxtel.EnquireFullVersionNumber()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// if val.Major == 0 || len(val.AsString) == 0 {
-// 		t.Fatal("full version failed")
-// 	}
-// }
-
-// func TestEnquireProductName(t *testing.T) {
-// 	val, err := oxtel.EnquireProductName()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if len(val) == 0 {
-// 		t.Fatal("product name failed")
-// 	}
-// }
-
-// func TestEnquireMediaPortName(t *testing.T) {
-// 	val, err := oxtel.EnquireMediaPortName()
-// 	if err != nil {
-// 		t.Fatal(err)
-// 	}
-
-// 	if val[:3] != "Tap" {
-// 		t.Fatal("media port name failed")
-// 	}
-// }
+func TestEnquireMediaTallies(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireMediaTallies()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Images {
+		t.Fatal("Media Tallies should be off")
+	}
+}
+
+func TestMediaTallies(t *testing.T) {
+	_, client := connectTestClient(t)
+	s := MediaTallies{
+		Images: true,
+	}
+	err := client.EnableMediaTallies(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquirePlayStateTally(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquirePlayStateTally()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val {
+		t.Fatal("Play State Tally should be off")
+	}
+}
+
+func TestEnablePlayStateTally(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.EnablePlayStateTally(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquireFileInfo(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireFileInfo(templateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !val.Exists || val.Filename != templateName {
+		t.Fatalf("Unexpected file info response: %v", val)
+	}
+}
+
+func TestEnquireExtendedFileInfo(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireExtendedFileInformation(templateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !val.Exists || val.Filename != templateName {
+		t.Fatalf("Unexpected file info response: %v", val)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.ValidateTemplate(templateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Filename != templateName || !val.FileExists {
+		t.Fatal("validate template failed")
+	}
+}
+
+func TestPreloadImage(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.PreloadImage(layer, templateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquirePreloadImage(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.PreloadImage(layer, templateName); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquirePreloadImage(layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Layer != 0 || val.Filename != templateName {
+		t.Fatalf("Incorrect template preloaded: %v", val)
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.LoadImage(0, "1080i60-EAS-Warning.swf")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquireLoadImage(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.LoadImage(layer, templateName); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireLoadImage(layer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Layer != 0 || val.Filename != templateName {
+		t.Fatalf("Incorrect template loaded: %v", val)
+	}
+}
+
+func TestSetImagePosition(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.SetImagePosition(layer, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquireImagePosition(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.SetImagePosition(layer, 10, 10); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireImagePosition(layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Layer != 0 || val.XOffset != 10 || val.YOffset != 10 {
+		t.Fatalf("Invalid position image: %v", val)
+	}
+}
+
+func TestEraseStore(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.EraseStore(layer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelectMixerInput(t *testing.T) {
+	_, client := connectTestClient(t)
+	arc := OXTEL_ARC_DEFAULT
+	err := client.SelectMixerInput(0, OXTEL_VIDEO_SOURCE_EXT_IN_3, &arc)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+}
+
+func TestEnquireMixerInput(t *testing.T) {
+	_, client := connectTestClient(t)
+	arc := OXTEL_ARC_DEFAULT
+	if err := client.SelectMixerInput(0, OXTEL_VIDEO_SOURCE_EXT_IN_3, &arc); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireMixerInput(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Input != 0 || val.Source != OXTEL_VIDEO_SOURCE_EXT_IN_3 {
+		t.Fatalf("Invalid mixer input %v", val)
+	}
+}
+
+func TestEnquireMixMode(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireMixMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.WipeSoftness != 0 {
+		t.Fatalf("Invalid mix mode response %v", val)
+	}
+}
+
+func TestSetColorGenerator(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.SetColorGeneratorColor(0, 255, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquireColorGeneratorColor(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.SetColorGeneratorColor(0, 255, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireColorGeneratorColor(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Unit != 0 || val.Red != 255 || val.Green != 0 || val.Blue != 0 {
+		t.Fatalf("Invalid color generate response %v", val)
+	}
+}
+
+func TestEnquireVideoTallies(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireVideoTallies()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val {
+		t.Fatal("video tally should be false")
+	}
+}
+
+func TestEnquireAudioProfile(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireAudioProfile(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Profile != 0 || val.Source != 0 {
+		t.Fatalf("invalid audio profile %v", val)
+	}
+}
+
+func TestAudioFollowingVideo(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.SetAudioABFollowVideoAB(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetLoudness(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.GetAudioLoudness(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Channel1 {
+		t.Fatal("audio loudness program failed")
+	}
+}
+
+func TestGetLoudnessLicenseStatus(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.GetLoudnessLicenseStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = val
+}
+
+func TestEnquireAudioFollowingVideo(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.SetAudioABFollowVideoAB(true); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireAudioABFollowVideoAB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !val.Enabled {
+		t.Fatal("audio should be following video")
+	}
+}
+
+func TestEnquireAudioGain(t *testing.T) {
+	_, client := connectTestClient(t)
+	mask := MakeEightChannelMask()
+	val, err := client.EnquireAudioGain(OXTEL_AUDIO_OUTPUT_EXT_IN_3, mask)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Source != OXTEL_AUDIO_SOURCE_EXT_IN_3 || val.Gain != 0 {
+		t.Fatal("audio gain failed")
+	}
+}
+
+func TestEnquireLatency(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireLatency(OXTEL_LATENCY_SOURCE_OXTEL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Source != OXTEL_LATENCY_SOURCE_OXTEL {
+		t.Fatal("latency response failed")
+	}
+}
+
+func TestEnquireNumberOfGraphicLayers(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireNumberOfGraphicLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val != 8 {
+		t.Fatal("number of graphic layers failed")
+	}
+}
+
+func TestEnquireSystemStatus(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireSystemStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.VideoStandard != OXTEL_VIDEO_STANDARD_1080I_5994 {
+		t.Fatal("system status failed")
+	}
+
+}
+
+func TestFadeKeyer(t *testing.T) {
+	_, client := connectTestClient(t)
+	err := client.FadeKeyer(OXTEL_LAYER_0, OXTEL_DIR_UP, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnquireVideoLayerStatus(t *testing.T) {
+	_, client := connectTestClient(t)
+	if err := client.FadeKeyer(OXTEL_LAYER_0, OXTEL_DIR_UP, nil); err != nil {
+		t.Fatal(err)
+	}
+	val, err := client.EnquireVideoLayerStatus(OXTEL_LAYER_0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.FadeKeyer(OXTEL_LAYER_0, OXTEL_DIR_DOWN, nil)
+
+	if val.LayerFaderAngle != 512 {
+		t.Fatal("video layer status failed")
+	}
+}
+
+func TestEnquireCommandAvailability(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireCommandAvailability('X', '3')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.CommandByte1 != 'X' || val.CommandByte2 != '3' || !val.Supported {
+		t.Fatal("command availability failed")
+	}
+}
+
+func TestEnquireSlaveLayerStatus(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireSlaveLayerStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Layer7State {
+		t.Fatal("layer status failed")
+	}
+}
+
+func TestEnquireFullVersionNumber(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireFullVersionNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val.Major == 0 || len(val.AsString) == 0 {
+		t.Fatal("full version failed")
+	}
+}
+
+func TestEnquireProductName(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireProductName()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(val) == 0 {
+		t.Fatal("product name failed")
+	}
+}
+
+func TestEnquireMediaPortName(t *testing.T) {
+	_, client := connectTestClient(t)
+	val, err := client.EnquireMediaPortName()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val[:3] != "Tap" {
+		t.Fatal("media port name failed")
+	}
+}
 
 func TestTemperature(t *testing.T) {
-	val, err := oxtel.EnquireTemperature()
+	_, client := connectTestClient(t)
+	val, err := client.EnquireTemperature()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -416,9 +477,65 @@ func TestTemperature(t *testing.T) {
 	}
 }
 
-// Last Function to call
+func TestImageLoadTally(t *testing.T) {
+	_, client := connectTestClient(t)
+
+	sub := client.Subscribe(SubscriptionOptions{Types: []OxtelTallyType{OXTEL_TALLY_IMAGE_LOAD}})
+	defer sub.Close()
+
+	if err := client.EnableVideoTallies(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadImage(OXTEL_LAYER_2, templateName); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-sub.C:
+		tally, ok := msg.(ImageLoadTally)
+		if !ok || tally.Layer != OXTEL_LAYER_2 || tally.Template != templateName {
+			t.Fatalf("Unexpected image load tally: %v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for image load tally")
+	}
+
+	if err := client.EnableVideoTallies(false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddScheduledCommand(t *testing.T) {
+	srv, client := connectTestClient(t)
+	srv.SetTime(10, 0, 0, 0)
+	err := client.AddScheduledCommand(Timecode{Hours: 10, Seconds: 1}, LoadImage_AsString(OXTEL_LAYER_3, templateName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		val, err := client.EnquireLoadImage(OXTEL_LAYER_3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val.Filename == templateName {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("scheduled command was not executed")
+}
+
 func TestDisconnect(t *testing.T) {
-	err := oxtel.Disconnect()
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Disconnect()
 
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,1458 @@
+package oxteltest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	emptyLayer    = ">Empty<"
+	defaultFolder = "$VIDEO"
+	fullAngle     = 512
+)
+
+type layer struct {
+	loaded         string
+	preloaded      string
+	x              int64
+	y              int64
+	angle          float64
+	velocity       float64
+	transitionRate uint64
+	playing        bool
+	fields         map[uint64]string
+	preloadFields  map[uint64]string
+	images         map[uint64]string
+}
+
+// keyerState reports the keyer position the way KeyerPositionTally does: 0 down, 1 up, 2 in transition.
+func (l *layer) keyerState() uint8 {
+	switch {
+	case l.velocity != 0:
+		return 2
+	case l.angle >= fullAngle:
+		return 1
+	case l.angle <= 0:
+		return 0
+	}
+	return 2
+}
+
+type scheduled struct {
+	frame int
+	cmd   string
+	conn  *conn
+}
+
+type file struct {
+	name          string
+	missingAssets uint16
+}
+
+type ioConfig struct {
+	name string
+	rest string
+}
+
+type ioSource struct {
+	ioType   uint64
+	configId uint64
+}
+
+type engine struct {
+	s *Server
+
+	layers []*layer
+
+	mixerPosition  float64
+	mixerVelocity  float64
+	mixerTarget    float64
+	mixerSources   [2]uint64
+	mixerArc       [2]uint64
+	mixerRate      uint64
+	transitionType uint64
+	colors         map[uint64][3]uint64
+
+	audioFollowVideo bool
+	audioPosition    uint64
+	audioMixMode     uint64
+	audioFadeRate    uint64
+	audioProfiles    map[uint64]uint64
+	audioGains       map[uint64]map[int]int64
+	dolbyProfiles    map[uint64]uint64
+	loudness         map[uint64][]string
+	loudnessProfiles map[uint64]uint64
+
+	permanentLocks uint32
+
+	folders       map[string][]file
+	latencies     map[uint64]int64
+	numLayers     int
+	videoStandard uint64
+	unsupported   map[string]bool
+	version       string
+	productName   string
+	portName      string
+
+	frame    int
+	schedule []scheduled
+
+	ioConfigs      map[uint64][]ioConfig
+	ioSources      map[string]ioSource
+	ioDynamic      map[string]string
+	externalInputs []ExternalInput
+	outputs        []ExternalInput
+
+	lastVideoTally string
+}
+
+// ExternalInput is an entry returned by the hXIN and hOUT enquiries.
+type ExternalInput struct {
+	Name string
+	Id   uint8
+}
+
+func newEngine(s *Server) *engine {
+	e := &engine{
+		s:                s,
+		colors:           make(map[uint64][3]uint64),
+		audioProfiles:    make(map[uint64]uint64),
+		audioGains:       make(map[uint64]map[int]int64),
+		dolbyProfiles:    make(map[uint64]uint64),
+		loudness:         make(map[uint64][]string),
+		loudnessProfiles: make(map[uint64]uint64),
+		folders:          map[string][]file{defaultFolder: nil},
+		latencies:        map[uint64]int64{0: 2, 1: 1, 3: 3, 4: 3, 5: 3},
+		numLayers:        8,
+		videoStandard:    2,
+		unsupported:      make(map[string]bool),
+		version:          "10.1.0.0.1234",
+		productName:      "Spectrum X",
+		portName:         "Tap 1",
+		mixerSources:     [2]uint64{0x0, 0x6},
+		ioConfigs: map[uint64][]ioConfig{
+			0x0: {{name: "SDI"}},
+			0x1: {{name: "2022-6 A", rest: "eth0,239.0.0.1,5000"}},
+			0x3: {{name: "2110 A", rest: "eth0,a.sdp"}},
+			0x4: {{name: "2022-6 A", rest: "eth0,239.0.0.1,5000,239.0.1.1,5000"}},
+		},
+		ioSources: make(map[string]ioSource),
+		ioDynamic: make(map[string]string),
+		externalInputs: []ExternalInput{
+			{Name: "Ext In 1", Id: 0x1},
+			{Name: "Ext In 2", Id: 0x7},
+			{Name: "Ext In 3", Id: 0x8},
+			{Name: "Ext In 4", Id: 0x9},
+		},
+		outputs: []ExternalInput{
+			{Name: "Primary", Id: 0x0},
+			{Name: "Secondary", Id: 0x1},
+		},
+	}
+
+	for i := 0; i < 8; i++ {
+		e.layers = append(e.layers, newLayer())
+	}
+	return e
+}
+
+func newLayer() *layer {
+	return &layer{
+		loaded:        emptyLayer,
+		preloaded:     emptyLayer,
+		fields:        make(map[uint64]string),
+		preloadFields: make(map[uint64]string),
+		images:        make(map[uint64]string),
+	}
+}
+
+type handlerEntry struct {
+	prefix string
+	fn     func(e *engine, c *conn, args string)
+}
+
+// commandTable maps command prefixes to their handlers. Longer prefixes are matched first so that, for example,
+// "jALA" is not handled as "jAL".
+var commandTable []handlerEntry
+
+func init() {
+	commandTable = []handlerEntry{
+		{"1", (*engine).fadeKeyer},
+		{"3", (*engine).cutKeyer},
+		{"@", (*engine).setFaderAngle},
+		{"A", (*engine).eraseStore},
+		{"B", (*engine).setTransitionDuration},
+		{"G", (*engine).imagePosition},
+		{"M", (*engine).systemStatus},
+		{"N", (*engine).videoLayerStatus},
+		{"R0", (*engine).loadImage},
+		{"R3", (*engine).fileInfo},
+		{"R4", (*engine).queryFirstFile},
+		{"R5", (*engine).querySubsequentFile},
+		{"R6", (*engine).extendedFileInfo},
+		{"R7", (*engine).preloadImage},
+		{"RA", (*engine).validateTemplate},
+		{"S0", (*engine).startAnimation},
+		{"S1", (*engine).stopAnimation},
+		{"S2", (*engine).ignore},
+		{"S4", (*engine).startAnimation},
+		{"U0", (*engine).cutToA},
+		{"U1", (*engine).cutToB},
+		{"U2", (*engine).fadeToA},
+		{"U3", (*engine).fadeToB},
+		{"U4", (*engine).cutAB},
+		{"U5", (*engine).fadeAB},
+		{"U6", (*engine).setTransitionType},
+		{"U8", (*engine).asymmetricVFadeAB},
+		{"U9", (*engine).setAbsoluteMix},
+		{"UA", (*engine).asymmetricTransition},
+		{"UC", (*engine).fadeToPosition},
+		{"UE", (*engine).mixerInput},
+		{"UZ", (*engine).colorGenerator},
+		{"Ua", (*engine).mixMode},
+		{"X0", (*engine).temperature},
+		{"X3", (*engine).commandAvailability},
+		{"XA", (*engine).slaveLayerStatus},
+		{"Xb", (*engine).fullVersion},
+		{"Xn", (*engine).productNameEnquiry},
+		{"Y6", (*engine).videoTallies},
+		{"YB", (*engine).mediaTalliesCommand},
+		{"YS", (*engine).playStateTallyCommand},
+		{"Z0", (*engine).updateTextField},
+		{"Z4", (*engine).changeImage},
+		{"Zf", (*engine).ignore},
+		{"Zg", (*engine).ignore},
+		{"hCSI", (*engine).ignore},
+		{"hDA", (*engine).dolbyProfile},
+		{"hDE", (*engine).ignore},
+		{"hDP", (*engine).ignore},
+		{"hEXTIO", (*engine).externalIOSupported},
+		{"hGSL", (*engine).globalSessionLocks},
+		{"hKWM", (*engine).ignore},
+		{"hLAT", (*engine).latency},
+		{"hNGL", (*engine).numberOfGraphicLayers},
+		{"hOLT", (*engine).lockTallyCommand},
+		{"hOUT", (*engine).outputsEnquiry},
+		{"hPL", (*engine).permanentLocksCommand},
+		{"hSL", (*engine).sessionLocksCommand},
+		{"hTN", (*engine).mediaPortName},
+		{"hXDC", (*engine).externalIODynamicConfig},
+		{"hXIN", (*engine).externalInputsEnquiry},
+		{"hXIOT", (*engine).externalIOTallyCommand},
+		{"hXNC", (*engine).externalIOConfigurations},
+		{"hXS", (*engine).externalIOSource},
+		{"hZ0", (*engine).updatePreloadedTextField},
+		{"i0", (*engine).addScheduledCommand},
+		{"i2", (*engine).deleteAllScheduledCommands},
+		{"ix", (*engine).currentTime},
+		{"j31", (*engine).audioFadeRateCommand},
+		{"j40", (*engine).audioCut},
+		{"j41", (*engine).audioCut},
+		{"j51", (*engine).audioFollowVideoCommand},
+		{"j74", (*engine).audioFollowVideoEnquiry},
+		{"jAG", (*engine).audioGain},
+		{"jAL", (*engine).audioLoudness},
+		{"jALA", (*engine).audioLoudnessProfile},
+		{"jALL", (*engine).loudnessLicense},
+		{"jALP", (*engine).ignore},
+		{"jALR", (*engine).ignore},
+		{"jAP", (*engine).audioProfile},
+		{"jAT", (*engine).audioProfileTalliesCommand},
+		{"ja", (*engine).audioPositionCommand},
+		{"jb", (*engine).audioMixModeCommand},
+		{"jc", (*engine).audioAsymmetric},
+		{"jd", (*engine).audioFadeToPosition},
+	}
+
+	sort.SliceStable(commandTable, func(i, j int) bool {
+		return len(commandTable[i].prefix) > len(commandTable[j].prefix)
+	})
+}
+
+// handle executes a single command on behalf of c. c is nil for scheduled commands whose connection has closed.
+// Server.mu must be held.
+func (e *engine) handle(c *conn, cmd string) {
+	for _, h := range commandTable {
+		if strings.HasPrefix(cmd, h.prefix) {
+			h.fn(e, c, cmd[len(h.prefix):])
+			return
+		}
+	}
+}
+
+func (e *engine) dropConn(c *conn) {
+	for i := range e.schedule {
+		if e.schedule[i].conn == c {
+			e.schedule[i].conn = nil
+		}
+	}
+	if c.sessionLocks != 0 {
+		c.sessionLocks = 0
+		e.sendLockTally()
+	}
+}
+
+func (e *engine) ignore(c *conn, args string) {}
+
+func (e *engine) layer(s string) (*layer, uint64, bool) {
+	if len(s) == 0 {
+		return nil, 0, false
+	}
+	n, ok := parseHex(s[:1])
+	if !ok || int(n) >= len(e.layers) {
+		return nil, 0, false
+	}
+	return e.layers[n], n, true
+}
+
+// tick advances the engine by one field/frame.
+func (e *engine) tick() {
+	for i, l := range e.layers {
+		if l.velocity == 0 {
+			continue
+		}
+		l.angle += l.velocity
+		if l.angle >= fullAngle || l.angle <= 0 {
+			if l.angle > fullAngle {
+				l.angle = fullAngle
+			}
+			if l.angle < 0 {
+				l.angle = 0
+			}
+			l.velocity = 0
+			e.sendKeyerTally(uint64(i))
+		}
+	}
+
+	if e.mixerVelocity != 0 {
+		e.mixerPosition += e.mixerVelocity
+		if (e.mixerVelocity > 0 && e.mixerPosition >= e.mixerTarget) || (e.mixerVelocity < 0 && e.mixerPosition <= e.mixerTarget) {
+			e.mixerPosition = e.mixerTarget
+			e.mixerVelocity = 0
+		}
+	}
+	e.sendVideoTally()
+
+	e.frame = (e.frame + 1) % e.framesPerDay()
+
+	var due []scheduled
+	remaining := e.schedule[:0]
+	for _, sc := range e.schedule {
+		if sc.frame == e.frame {
+			due = append(due, sc)
+		} else {
+			remaining = append(remaining, sc)
+		}
+	}
+	e.schedule = remaining
+
+	for _, sc := range due {
+		e.s.commands = append(e.s.commands, sc.cmd)
+		e.handle(sc.conn, sc.cmd)
+	}
+}
+
+// Keyers
+
+func (e *engine) fadeKeyer(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return
+	}
+	l, n, ok := e.layer(fields[0])
+	if !ok {
+		return
+	}
+	dir, ok := parseHex(fields[1])
+	if !ok {
+		return
+	}
+
+	rate := l.transitionRate
+	if len(fields) > 2 {
+		if rate, ok = parseHex(fields[2]); !ok {
+			return
+		}
+	}
+
+	e.moveKeyer(n, l, dir, rate)
+}
+
+func (e *engine) cutKeyer(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return
+	}
+	l, n, ok := e.layer(fields[0])
+	if !ok {
+		return
+	}
+	dir, ok := parseHex(fields[1])
+	if !ok {
+		return
+	}
+
+	e.moveKeyer(n, l, dir, 0)
+}
+
+func (e *engine) moveKeyer(n uint64, l *layer, dir uint64, rate uint64) {
+	up := dir == 1
+	if dir == 2 {
+		up = l.angle < fullAngle
+		if l.velocity != 0 {
+			up = l.velocity < 0
+		}
+	}
+
+	if rate == 0 {
+		l.velocity = 0
+		if up {
+			l.angle = fullAngle
+		} else {
+			l.angle = 0
+		}
+		e.sendKeyerTally(n)
+		return
+	}
+
+	if l.velocity != 0 {
+		// A reverse command during a fade reverses the fade and continues at the same rate.
+		if up != (l.velocity > 0) {
+			l.velocity = -l.velocity
+			e.sendKeyerTally(n)
+		}
+		return
+	}
+
+	if (up && l.angle >= fullAngle) || (!up && l.angle <= 0) {
+		e.sendKeyerTally(n)
+		return
+	}
+
+	step := float64(fullAngle) / float64(rate)
+	if up {
+		l.velocity = step
+	} else {
+		l.velocity = -step
+	}
+	e.sendKeyerTally(n)
+}
+
+func (e *engine) setFaderAngle(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		return
+	}
+	l, n, ok := e.layer(fields[0])
+	if !ok {
+		return
+	}
+	angle, ok := parseHex(fields[2])
+	if !ok || angle > fullAngle {
+		return
+	}
+
+	l.velocity = 0
+	l.angle = float64(angle)
+	e.sendKeyerTally(n)
+}
+
+func (e *engine) setTransitionDuration(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		return
+	}
+	l, _, ok := e.layer(fields[0])
+	if !ok {
+		return
+	}
+	if rate, ok := parseHex(fields[2]); ok {
+		l.transitionRate = rate
+	}
+}
+
+func (e *engine) videoLayerStatus(c *conn, args string) {
+	l, _, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	c.write(fmt.Sprintf("N%03x000000000000", int(l.angle)))
+}
+
+func (e *engine) slaveLayerStatus(c *conn, args string) {
+	msg := "XA"
+	for _, l := range e.layers {
+		if l.angle >= fullAngle {
+			msg += "1"
+		} else {
+			msg += "0"
+		}
+	}
+	c.write(msg + "00000000")
+}
+
+// Templates
+
+func (e *engine) loadImage(c *conn, args string) {
+	l, n, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	if len(args) == 1 {
+		c.write(fmt.Sprintf("R0%x%s", n, l.loaded))
+		return
+	}
+
+	name := args[1:]
+	if l.preloaded == name {
+		l.loaded = name
+		l.fields = l.preloadFields
+		l.preloaded = emptyLayer
+		l.preloadFields = make(map[uint64]string)
+	} else if e.fileExists(name) {
+		l.loaded = name
+		l.fields = make(map[uint64]string)
+		l.preloaded = emptyLayer
+		l.preloadFields = make(map[uint64]string)
+	} else {
+		l.loaded = emptyLayer
+		l.fields = make(map[uint64]string)
+	}
+	l.images = make(map[uint64]string)
+
+	e.eachTally(func(c *conn) bool { return c.videoTallies }, fmt.Sprintf("Y9%x%s", n, l.loaded))
+	e.setPlaying(n, l, l.loaded != emptyLayer)
+}
+
+func (e *engine) preloadImage(c *conn, args string) {
+	l, n, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	if len(args) == 1 {
+		c.write(fmt.Sprintf("R7%x%s", n, l.preloaded))
+		return
+	}
+
+	name := args[1:]
+	if !e.fileExists(name) {
+		return
+	}
+	l.preloaded = name
+	l.preloadFields = make(map[uint64]string)
+	e.eachTally(func(c *conn) bool { return c.videoTallies }, fmt.Sprintf("YA%x%s", n, name))
+}
+
+func (e *engine) eraseStore(c *conn, args string) {
+	l, n, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	l.loaded = emptyLayer
+	l.fields = make(map[uint64]string)
+	l.images = make(map[uint64]string)
+	e.eachTally(func(c *conn) bool { return c.videoTallies }, fmt.Sprintf("Y9%x%s", n, l.loaded))
+	e.setPlaying(n, l, false)
+}
+
+func (e *engine) imagePosition(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return
+	}
+	l, n, ok := e.layer(fields[0])
+	if !ok {
+		return
+	}
+	if len(fields) == 1 {
+		c.write(fmt.Sprintf("G%x %x %x", n, l.x, l.y))
+		return
+	}
+	if len(fields) < 3 {
+		return
+	}
+
+	x, err := strconv.ParseInt(fields[1], 16, 64)
+	if err != nil {
+		return
+	}
+	y, err := strconv.ParseInt(fields[2], 16, 64)
+	if err != nil {
+		return
+	}
+	l.x, l.y = x, y
+}
+
+func (e *engine) startAnimation(c *conn, args string) {
+	l, n, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	e.setPlaying(n, l, l.loaded != emptyLayer)
+}
+
+func (e *engine) stopAnimation(c *conn, args string) {
+	l, n, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	e.setPlaying(n, l, false)
+}
+
+func (e *engine) setPlaying(n uint64, l *layer, playing bool) {
+	if l.playing == playing {
+		return
+	}
+	l.playing = playing
+	e.eachTally(func(c *conn) bool { return c.playStateTally }, fmt.Sprintf("YS%x%x", n, boolToHex(playing)))
+}
+
+func (e *engine) updateTextField(c *conn, args string) {
+	if len(args) < 3 {
+		return
+	}
+	l, _, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	field, ok := parseHex(args[1:3])
+	if !ok {
+		return
+	}
+	if len(args) == 3 {
+		// RenderBox
+		return
+	}
+	updateField(l.fields, field, args[3:])
+}
+
+func (e *engine) updatePreloadedTextField(c *conn, args string) {
+	if len(args) < 4 {
+		return
+	}
+	l, _, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	field, ok := parseHex(args[1:3])
+	if !ok {
+		return
+	}
+	updateField(l.preloadFields, field, args[3:])
+}
+
+func updateField(fields map[uint64]string, field uint64, data string) {
+	flags, ok := parseHex(data[:1])
+	if !ok {
+		return
+	}
+	if flags&0x2 != 0 {
+		fields[field] += data[1:]
+	} else {
+		fields[field] = data[1:]
+	}
+}
+
+func (e *engine) changeImage(c *conn, args string) {
+	if len(args) < 3 {
+		return
+	}
+	l, _, ok := e.layer(args)
+	if !ok {
+		return
+	}
+	field, ok := parseHex(args[1:3])
+	if !ok {
+		return
+	}
+	l.images[field] = args[3:]
+}
+
+// Files
+
+func (e *engine) findFile(name string) (file, bool) {
+	// A name qualified with a folder name alias, as in $LOGOS\logo.png, is only looked for in that folder.
+	if alias, bare, ok := strings.Cut(name, `\`); ok && strings.HasPrefix(alias, "$") {
+		for _, f := range e.folders[alias] {
+			if f.name == bare {
+				return f, true
+			}
+		}
+		return file{}, false
+	}
+
+	for _, files := range e.folders {
+		for _, f := range files {
+			if f.name == name {
+				return f, true
+			}
+		}
+	}
+	return file{}, false
+}
+
+func (e *engine) fileExists(name string) bool {
+	_, ok := e.findFile(name)
+	return ok
+}
+
+func (e *engine) fileInfo(c *conn, args string) {
+	c.write(fmt.Sprintf("R3%x%s", boolToHex(e.fileExists(args)), args))
+}
+
+func (e *engine) extendedFileInfo(c *conn, args string) {
+	if e.fileExists(args) {
+		c.write(fmt.Sprintf("R610000007804380000000000100010010%s", args))
+		return
+	}
+	c.write(fmt.Sprintf("R600000000000000000000000000000000%s", args))
+}
+
+func (e *engine) validateTemplate(c *conn, args string) {
+	f, ok := e.findFile(args)
+	c.write(fmt.Sprintf("RA%s|%x%04x", args, boolToHex(ok), f.missingAssets))
+}
+
+func (e *engine) queryFirstFile(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	c.queryFolder = args
+	c.queryIndex = 0
+	e.queryNextFile(c, "R4")
+}
+
+func (e *engine) querySubsequentFile(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if c.queryFolder != args {
+		c.queryFolder = args
+		c.queryIndex = 0
+	}
+	e.queryNextFile(c, "R5")
+}
+
+func (e *engine) queryNextFile(c *conn, prefix string) {
+	files := e.folders[c.queryFolder]
+	if c.queryIndex >= len(files) {
+		c.write(prefix + "1 ")
+		return
+	}
+	c.write(prefix + "0 " + files[c.queryIndex].name)
+	c.queryIndex++
+}
+
+func (e *engine) mediaTalliesCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write("YB" + c.mediaTallies)
+		return
+	}
+	if len(args) != 6 || strings.Trim(args, "01") != "" {
+		return
+	}
+	c.mediaTallies = args
+}
+
+func (e *engine) sendMediaTally(action uint8, name string) {
+	e.s.eachConn(func(c *conn) {
+		if c.mediaTallies[5] == '1' {
+			c.write(fmt.Sprintf("YB%s%x%s", c.mediaTallies, action, name))
+		}
+	})
+}
+
+// Mixer
+
+func (e *engine) mixerInputState() uint8 {
+	switch {
+	case e.mixerVelocity == 0 && e.mixerPosition <= 0:
+		return 0
+	case e.mixerVelocity == 0 && e.mixerPosition >= fullAngle:
+		return 1
+	}
+	return 2
+}
+
+func (e *engine) moveMixer(target float64, duration uint64) {
+	e.mixerRate = duration
+	if duration == 0 || e.transitionType == 0x05 {
+		e.mixerVelocity = 0
+		e.mixerPosition = target
+		e.sendVideoTally()
+		return
+	}
+
+	e.mixerTarget = target
+	e.mixerVelocity = (target - e.mixerPosition) / float64(duration)
+	if e.mixerVelocity == 0 {
+		e.mixerPosition = target
+	}
+	e.sendVideoTally()
+}
+
+// mixerDestination returns the position opposite to the visible input.
+func (e *engine) mixerDestination() float64 {
+	if e.mixerVelocity != 0 {
+		if e.mixerVelocity > 0 {
+			return 0
+		}
+		return fullAngle
+	}
+	if e.mixerPosition >= fullAngle/2 {
+		return 0
+	}
+	return fullAngle
+}
+
+func (e *engine) cutToA(c *conn, args string) { e.moveMixer(0, 0) }
+func (e *engine) cutToB(c *conn, args string) { e.moveMixer(fullAngle, 0) }
+func (e *engine) cutAB(c *conn, args string)  { e.moveMixer(e.mixerDestination(), 0) }
+
+func (e *engine) fadeToA(c *conn, args string) {
+	if d, ok := parseHex(args); ok {
+		e.moveMixer(0, d)
+	}
+}
+
+func (e *engine) fadeToB(c *conn, args string) {
+	if d, ok := parseHex(args); ok {
+		e.moveMixer(fullAngle, d)
+	}
+}
+
+func (e *engine) fadeAB(c *conn, args string) {
+	if d, ok := parseHex(args); ok {
+		e.moveMixer(e.mixerDestination(), d)
+	}
+}
+
+func (e *engine) asymmetricVFadeAB(c *conn, args string) {
+	if len(args) != 6 {
+		return
+	}
+	down, ok1 := parseHex(args[:3])
+	up, ok2 := parseHex(args[3:])
+	if ok1 && ok2 {
+		e.moveMixer(e.mixerDestination(), down+up)
+	}
+}
+
+func (e *engine) asymmetricTransition(c *conn, args string) {
+	if len(args) != 7 {
+		return
+	}
+	dest, ok1 := parseHex(args[:1])
+	down, ok2 := parseHex(args[1:4])
+	up, ok3 := parseHex(args[4:])
+	if !ok1 || !ok2 || !ok3 {
+		return
+	}
+	target := 0.0
+	if dest == 1 {
+		target = fullAngle
+	}
+	e.moveMixer(target, down+up)
+}
+
+func (e *engine) setAbsoluteMix(c *conn, args string) {
+	if mix, ok := parseHex(args); ok && mix <= fullAngle {
+		e.moveMixer(float64(mix), 0)
+	}
+}
+
+func (e *engine) fadeToPosition(c *conn, args string) {
+	if len(args) != 6 {
+		return
+	}
+	pos, ok1 := parseHex(args[:3])
+	d, ok2 := parseHex(args[3:])
+	if ok1 && ok2 && pos <= fullAngle {
+		e.moveMixer(float64(pos), d)
+	}
+}
+
+func (e *engine) setTransitionType(c *conn, args string) {
+	if t, ok := parseHex(args); ok {
+		e.transitionType = t
+	}
+}
+
+func (e *engine) mixerInput(c *conn, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return
+	}
+	input, ok := parseHex(fields[0])
+	if !ok || input > 1 {
+		return
+	}
+	if len(fields) == 1 {
+		c.write(fmt.Sprintf("UE%x%x", input, e.mixerSources[input]))
+		return
+	}
+
+	source, ok := parseHex(fields[1])
+	if !ok {
+		return
+	}
+	e.mixerSources[input] = source
+	e.mixerArc[input] = 0
+	if len(fields) > 2 {
+		if arc, ok := parseHex(fields[2]); ok {
+			e.mixerArc[input] = arc
+		}
+	}
+	e.sendVideoTally()
+}
+
+func (e *engine) mixMode(c *conn, args string) {
+	c.write(fmt.Sprintf("Ua%02x%03x000%03x000000", e.transitionType, e.mixerRate, int(e.mixerPosition)))
+}
+
+func (e *engine) colorGenerator(c *conn, args string) {
+	if len(args) == 0 {
+		return
+	}
+	unit, ok := parseHex(args[:1])
+	if !ok {
+		return
+	}
+	if len(args) == 1 {
+		rgb := e.colors[unit]
+		c.write(fmt.Sprintf("UZ%x%02x%02x%02x", unit, rgb[0], rgb[1], rgb[2]))
+		return
+	}
+	if len(args) != 7 {
+		return
+	}
+	r, ok1 := parseHex(args[1:3])
+	g, ok2 := parseHex(args[3:5])
+	b, ok3 := parseHex(args[5:7])
+	if ok1 && ok2 && ok3 {
+		e.colors[unit] = [3]uint64{r, g, b}
+	}
+}
+
+// Tallies
+
+func (e *engine) eachTally(enabled func(c *conn) bool, msg string) {
+	e.s.eachConn(func(c *conn) {
+		if enabled(c) {
+			c.write(msg)
+		}
+	})
+}
+
+func (e *engine) sendKeyerTally(n uint64) {
+	l := e.layers[n]
+	e.eachTally(func(c *conn) bool { return c.videoTallies }, fmt.Sprintf("3%x %d", n, l.keyerState()))
+	e.sendVideoTally()
+}
+
+// sendVideoTally emits a VideoTally when the mixer or the first two keyers have changed since the last one.
+func (e *engine) sendVideoTally() {
+	msg := fmt.Sprintf("Y6%x%x%x%x%x0000", e.mixerInputState(), e.layers[0].keyerState(), e.layers[1].keyerState(),
+		e.mixerSources[0], e.mixerSources[1])
+	if msg == e.lastVideoTally {
+		return
+	}
+	e.lastVideoTally = msg
+	e.eachTally(func(c *conn) bool { return c.videoTallies }, msg)
+}
+
+func (e *engine) videoTallies(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("Y6%x", boolToHex(c.videoTallies)))
+		return
+	}
+	c.videoTallies = args == "1"
+}
+
+func (e *engine) playStateTallyCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("YS%x", boolToHex(c.playStateTally)))
+		return
+	}
+
+	enable := args == "1"
+	if enable && !c.playStateTally {
+		for n, l := range e.layers {
+			c.write(fmt.Sprintf("YS%x%x", n, boolToHex(l.playing)))
+		}
+	}
+	c.playStateTally = enable
+}
+
+// Locks
+
+func (e *engine) globalSessionLockBits() uint32 {
+	var locks uint32
+	e.s.eachConn(func(c *conn) {
+		locks |= c.sessionLocks
+	})
+	return locks
+}
+
+func (e *engine) sendLockTally() {
+	e.eachTally(func(c *conn) bool { return c.lockTally },
+		fmt.Sprintf("hOLY%08x%08x", e.globalSessionLockBits(), e.permanentLocks))
+}
+
+func (e *engine) sessionLocksCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("hSL%08x", c.sessionLocks))
+		return
+	}
+	locks, ok := parseHex(args)
+	if !ok {
+		return
+	}
+	c.sessionLocks = uint32(locks)
+	e.sendLockTally()
+}
+
+func (e *engine) globalSessionLocks(c *conn, args string) {
+	c.write(fmt.Sprintf("hGSL%08x", e.globalSessionLockBits()))
+}
+
+func (e *engine) permanentLocksCommand(c *conn, args string) {
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("hPL%08x", e.permanentLocks))
+		return
+	}
+	locks, ok := parseHex(args)
+	if !ok {
+		return
+	}
+	e.permanentLocks = uint32(locks)
+	e.sendLockTally()
+}
+
+func (e *engine) lockTallyCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("hOLT%x", boolToHex(c.lockTally)))
+		return
+	}
+
+	enable := args == "1"
+	if enable && !c.lockTally {
+		c.write(fmt.Sprintf("hOLY%08x%08x", e.globalSessionLockBits(), e.permanentLocks))
+	}
+	c.lockTally = enable
+}
+
+// System
+
+func (e *engine) temperature(c *conn, args string) {
+	c.write("X00.0")
+}
+
+func (e *engine) commandAvailability(c *conn, args string) {
+	c.write(fmt.Sprintf("X3%s%x", args, boolToHex(!e.unsupported[args])))
+}
+
+func (e *engine) fullVersion(c *conn, args string) {
+	c.write("Xb" + e.version)
+}
+
+func (e *engine) productNameEnquiry(c *conn, args string) {
+	c.write("Xn" + e.productName)
+}
+
+func (e *engine) mediaPortName(c *conn, args string) {
+	c.write("hTN" + e.portName)
+}
+
+func (e *engine) systemStatus(c *conn, args string) {
+	c.write(fmt.Sprintf("M1%03x%03x%d000%03x%03x0000000", 0xa, 0x1, e.videoStandard,
+		e.layers[0].transitionRate, e.layers[1].transitionRate))
+}
+
+func (e *engine) latency(c *conn, args string) {
+	source, ok := parseHex(args)
+	if !ok {
+		return
+	}
+	c.write(fmt.Sprintf("hLAT%x%x", source, e.latencies[source]))
+}
+
+func (e *engine) numberOfGraphicLayers(c *conn, args string) {
+	c.write(fmt.Sprintf("hNGL%d", e.numLayers))
+}
+
+// Scheduler
+
+func (e *engine) addScheduledCommand(c *conn, args string) {
+	idx := strings.Index(args, ";")
+	if idx != 8 {
+		return
+	}
+	frame, ok := e.parseTimecode(args[:8])
+	if !ok {
+		return
+	}
+
+	cmd := args[idx+1:]
+	day := e.framesPerDay()
+	delta := (frame - e.frame + day) % day
+	if delta == 0 || delta > day-60*e.fps() {
+		e.s.commands = append(e.s.commands, cmd)
+		e.handle(c, cmd)
+		return
+	}
+	e.schedule = append(e.schedule, scheduled{frame: frame, cmd: cmd, conn: c})
+}
+
+func (e *engine) deleteAllScheduledCommands(c *conn, args string) {
+	e.schedule = nil
+}
+
+func (e *engine) currentTime(c *conn, args string) {
+	c.write(fmt.Sprintf("ix%d%s", e.fieldRate(), e.formatTimecode(e.frame)))
+}
+
+// Audio
+
+func (e *engine) audioFadeRateCommand(c *conn, args string) {
+	if d, ok := parseHex(args); ok {
+		e.audioFadeRate = d
+	}
+}
+
+func (e *engine) audioCut(c *conn, args string) {
+	if dest, ok := parseHex(args); ok {
+		e.audioPosition = dest * fullAngle
+	}
+}
+
+func (e *engine) audioFollowVideoCommand(c *conn, args string) {
+	e.audioFollowVideo = args == "1"
+}
+
+func (e *engine) audioFollowVideoEnquiry(c *conn, args string) {
+	c.write(fmt.Sprintf("j740%x", boolToHex(e.audioFollowVideo)))
+}
+
+func (e *engine) audioPositionCommand(c *conn, args string) {
+	if mix, ok := parseHex(args); ok && mix <= fullAngle {
+		e.audioPosition = mix
+	}
+}
+
+func (e *engine) audioMixModeCommand(c *conn, args string) {
+	if mode, ok := parseHex(args); ok {
+		e.audioMixMode = mode
+	}
+}
+
+func (e *engine) audioAsymmetric(c *conn, args string) {
+	if len(args) != 7 {
+		return
+	}
+	if dest, ok := parseHex(args[:1]); ok {
+		e.audioPosition = dest * fullAngle
+	}
+}
+
+func (e *engine) audioFadeToPosition(c *conn, args string) {
+	if len(args) != 6 {
+		return
+	}
+	if mix, ok := parseHex(args[:3]); ok && mix <= fullAngle {
+		e.audioPosition = mix
+	}
+}
+
+func (e *engine) audioGain(c *conn, args string) {
+	if len(args) < 6 {
+		return
+	}
+	output, ok1 := parseHex(args[:2])
+	mask, ok2 := parseHex(args[2:6])
+	if !ok1 || !ok2 {
+		return
+	}
+
+	gains := e.audioGains[output]
+	if gains == nil {
+		gains = make(map[int]int64)
+		e.audioGains[output] = gains
+	}
+
+	if len(args) == 6 {
+		var gain int64
+		for ch := 0; ch < 16; ch++ {
+			if mask&(1<<ch) != 0 {
+				gain = gains[ch]
+				break
+			}
+		}
+		c.write(fmt.Sprintf("jAG%02x%04X%d", output, mask, gain))
+		return
+	}
+
+	gain, err := strconv.ParseInt(args[6:], 10, 64)
+	if err != nil || gain < -100 || gain > 30 {
+		return
+	}
+	for ch := 0; ch < 16; ch++ {
+		if mask&(1<<ch) != 0 {
+			gains[ch] = gain
+		}
+	}
+}
+
+func (e *engine) audioProfile(c *conn, args string) {
+	if len(args) == 0 {
+		return
+	}
+	source, ok := parseHex(args[:1])
+	if !ok {
+		return
+	}
+	if len(args) == 1 {
+		c.write(fmt.Sprintf("jAP%x%02x", source, e.audioProfiles[source]))
+		return
+	}
+	profile, ok := parseHex(args[1:])
+	if !ok {
+		return
+	}
+	e.audioProfiles[source] = profile
+	e.eachTally(func(c *conn) bool { return c.audioProfileTallies }, fmt.Sprintf("jAY%x%02x", source, profile))
+}
+
+func (e *engine) audioProfileTalliesCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("jAT%x", boolToHex(c.audioProfileTallies)))
+		return
+	}
+	c.audioProfileTallies = args == "1"
+}
+
+func (e *engine) dolbyProfile(c *conn, args string) {
+	if len(args) == 0 {
+		return
+	}
+	input, ok := parseHex(args[:1])
+	if !ok {
+		return
+	}
+	if len(args) == 1 {
+		c.write(fmt.Sprintf("hDA%x", e.dolbyProfiles[input]))
+		return
+	}
+	if profile, ok := parseHex(args[1:]); ok {
+		e.dolbyProfiles[input] = profile
+	}
+}
+
+var programChannels = map[string]int{"01": 1, "02": 2, "03": 6, "04": 8}
+
+func (e *engine) audioLoudness(c *conn, args string) {
+	switch len(args) {
+	case 2:
+		sdi, ok := parseHex(args)
+		if !ok {
+			return
+		}
+		msg := "jAL"
+		for _, program := range e.loudness[sdi] {
+			msg += program[4:]
+		}
+		c.write(msg)
+	case 4:
+		sdi, ok := parseHex(args[:2])
+		if !ok {
+			return
+		}
+		for _, program := range e.loudness[sdi] {
+			for i := 4; i+2 <= len(program); i += 2 {
+				if program[i:i+2] == args[2:] {
+					c.write(fmt.Sprintf("jAL%x%s", sdi, program))
+					return
+				}
+			}
+		}
+		c.write(fmt.Sprintf("jAL%x0000", sdi))
+	default:
+		// SetAudioLoudness: one or two programs of SDI, program, preset and channel numbers.
+		for len(args) >= 8 {
+			sdi, ok := parseHex(args[:2])
+			if !ok {
+				return
+			}
+			n := 8
+			extra := programChannels[args[2:4]] - 1
+			for extra > 0 && len(args) >= n+2 {
+				n += 2
+				extra--
+			}
+			e.loudness[sdi] = append(e.loudness[sdi], args[2:n])
+			args = args[n:]
+		}
+	}
+}
+
+func (e *engine) audioLoudnessProfile(c *conn, args string) {
+	sdi, ok := parseHex(args[:min(2, len(args))])
+	if !ok {
+		return
+	}
+	if len(args) == 2 {
+		c.write(fmt.Sprintf("jALA%02x", e.loudnessProfiles[sdi]))
+		return
+	}
+	if profile, ok := parseHex(args[2:]); ok {
+		e.loudnessProfiles[sdi] = profile
+	}
+}
+
+func (e *engine) loudnessLicense(c *conn, args string) {
+	c.write("jALL01")
+}
+
+// External IO
+
+func (e *engine) externalIOSupported(c *conn, args string) {
+	c.write("hEXTIO1")
+}
+
+func (e *engine) externalIOTallyCommand(c *conn, args string) {
+	if c == nil {
+		return
+	}
+	if len(args) == 0 {
+		c.write(fmt.Sprintf("hXIOT%x", boolToHex(c.externalIOTally)))
+		return
+	}
+
+	enable := args == "1"
+	if enable && !c.externalIOTally {
+		for _, key := range sortedKeys(e.ioSources) {
+			src := e.ioSources[key]
+			c.write(fmt.Sprintf("hXSY%s%02x%02x00", key, src.ioType, src.configId))
+		}
+	}
+	c.externalIOTally = enable
+}
+
+func (e *engine) externalIOConfigurations(c *conn, args string) {
+	if len(args) != 4 && len(args) != 6 {
+		return
+	}
+	ioType, ok := parseHex(args[:2])
+	if !ok {
+		return
+	}
+	configs := e.ioConfigs[ioType]
+	if len(args) == 4 {
+		c.write(fmt.Sprintf("hXNC%s%02x", args, len(configs)))
+		return
+	}
+
+	index, ok := parseHex(args[4:])
+	if !ok || int(index) >= len(configs) {
+		return
+	}
+	cfg := configs[index]
+	msg := fmt.Sprintf("hXNC%s%02x%s", args, index+1, cfg.name)
+	if cfg.rest != "" {
+		msg += "," + cfg.rest
+	}
+	c.write(msg)
+}
+
+func (e *engine) externalIOSource(c *conn, args string) {
+	switch len(args) {
+	case 4:
+		src := e.ioSources[args]
+		c.write(fmt.Sprintf("hXS%s%02x%02x00", args, src.ioType, src.configId))
+	case 10:
+		ioType, ok1 := parseHex(args[4:6])
+		configId, ok2 := parseHex(args[6:8])
+		if !ok1 || !ok2 {
+			return
+		}
+		e.ioSources[args[:4]] = ioSource{ioType: ioType, configId: configId}
+		e.eachTally(func(c *conn) bool { return c.externalIOTally },
+			fmt.Sprintf("hXSY%s%02x%02x00", args[:4], ioType, configId))
+	}
+}
+
+func (e *engine) externalIODynamicConfig(c *conn, args string) {
+	if len(args) == 6 {
+		rest, ok := e.ioDynamic[args]
+		if !ok {
+			ioType, _ := parseHex(args[4:6])
+			if cfgs := e.ioConfigs[ioType]; len(cfgs) > 0 && cfgs[0].rest != "" {
+				rest = cfgs[0].rest
+			} else {
+				rest = "eth0"
+			}
+		}
+		c.write(fmt.Sprintf("hXDC%s,%s", args, rest))
+		return
+	}
+
+	idx := strings.Index(args, ",")
+	if idx != 8 {
+		return
+	}
+	key, rest := args[:6], args[idx+1:]
+	e.ioDynamic[key] = rest
+	e.eachTally(func(c *conn) bool { return c.externalIOTally }, fmt.Sprintf("hXDCY%s,%s", key, rest))
+}
+
+func (e *engine) externalInputsEnquiry(c *conn, args string) {
+	msg := fmt.Sprintf("hXIN%x", len(e.externalInputs))
+	for _, in := range e.externalInputs {
+		msg += fmt.Sprintf(";%s;%d", in.Name, in.Id)
+	}
+	c.write(msg)
+}
+
+func (e *engine) outputsEnquiry(c *conn, args string) {
+	msg := fmt.Sprintf("hOUT%x", len(e.outputs))
+	for _, out := range e.outputs {
+		msg += fmt.Sprintf(";%s;%d", out.Name, out.Id)
+	}
+	c.write(msg)
+}
+
+func boolToHex(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,363 @@
+// Package oxteltest provides an in-process fake Oxtel engine for testing clients of the oxtel package.
+//
+// The fake speaks the ':'-terminated Oxtel framing over TCP and keeps enough state (layers, keyers, the A/B mixer,
+// locks, tallies, the VITC clock and the scheduler) to answer every enquiry the oxtel package supports and to emit
+// unsolicited tallies when that state changes.
+package oxteltest
+
+import (
+	"bufio"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFieldDuration is the wall-clock duration of one engine tick when Server.FieldDuration is not set.
+const DefaultFieldDuration = time.Millisecond
+
+// HandlerFunc scripts the response to a command. The command is passed without its ':' terminator and with the
+// protocol escaping already undone. Each returned line is written back to the client with a ':' appended.
+type HandlerFunc func(cmd string) []string
+
+// Server is a fake Oxtel engine listening on a loopback address.
+type Server struct {
+	// FieldDuration is the wall-clock duration of one engine tick. Every tick advances the VITC clock by one frame
+	// and every running transition by one field/frame. It must be set before Start.
+	FieldDuration time.Duration
+
+	listener net.Listener
+	engine   *engine
+
+	mu       sync.Mutex
+	conns    map[*conn]struct{}
+	handlers map[string]HandlerFunc
+	commands []string
+
+	wg      sync.WaitGroup
+	done    chan struct{}
+	started bool
+	closed  bool
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new Server listening on a loopback address but not yet accepting connections or
+// advancing its clock. The caller should call Start when ready and Close when finished.
+func NewUnstartedServer() *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("oxteltest: failed to listen on a port: " + err.Error())
+	}
+
+	s := &Server{
+		listener: l,
+		conns:    make(map[*conn]struct{}),
+		handlers: make(map[string]HandlerFunc),
+		done:     make(chan struct{}),
+	}
+	s.engine = newEngine(s)
+	return s
+}
+
+// Start starts accepting connections and running the engine clock.
+func (s *Server) Start() {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		panic("oxteltest: server already started")
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	if s.FieldDuration <= 0 {
+		s.FieldDuration = DefaultFieldDuration
+	}
+
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go s.tickLoop()
+}
+
+// Close shuts down the server, closes every client connection and blocks until all goroutines have exited.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	_ = s.listener.Close()
+	for c := range s.conns {
+		c.close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// CloseClientConnections closes every open client connection while leaving the server running. Per-connection state
+// such as enabled tallies and session locks is discarded, as it is on a real engine.
+func (s *Server) CloseClientConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.conns {
+		c.close()
+	}
+}
+
+// Addr returns the address the server is listening on, in host:port form.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Host returns the host the server is listening on, for use with oxtel.NewOxtel.
+func (s *Server) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server is listening on, for use with oxtel.NewOxtel.
+func (s *Server) Port() uint16 {
+	return uint16(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Handle registers a handler for every command starting with prefix. Handlers take precedence over the built-in
+// engine and the longest matching prefix wins. A nil handler removes a previous registration.
+func (s *Server) Handle(prefix string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if h == nil {
+		delete(s.handlers, prefix)
+		return
+	}
+	s.handlers[prefix] = h
+}
+
+// Commands returns every command received so far, in order of arrival, without terminators and unescaped.
+// Scheduled commands are recorded again when the scheduler executes them.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]string, len(s.commands))
+	copy(out, s.commands)
+	return out
+}
+
+// ClearCommands discards the record of received commands.
+func (s *Server) ClearCommands() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commands = nil
+}
+
+// Broadcast writes a raw line, followed by ':', to every connected client. It can be used to inject arbitrary or
+// malformed unsolicited messages.
+func (s *Server) Broadcast(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.conns {
+		c.write(line)
+	}
+}
+
+// NumConnections returns the number of currently connected clients.
+func (s *Server) NumConnections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.conns)
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		nc, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := newConn(nc)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = nc.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(2)
+		go func() {
+			defer s.wg.Done()
+			c.writeLoop()
+		}()
+		go func() {
+			defer s.wg.Done()
+			s.readLoop(c)
+		}()
+	}
+}
+
+func (s *Server) readLoop(c *conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.engine.dropConn(c)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	r := bufio.NewReader(c.nc)
+	for {
+		line, err := r.ReadString(':')
+		if err != nil {
+			return
+		}
+
+		cmd := unescape(strings.TrimSpace(strings.TrimSuffix(line, ":")))
+		if len(cmd) == 0 {
+			continue
+		}
+		s.dispatch(c, cmd)
+	}
+}
+
+func (s *Server) dispatch(c *conn, cmd string) {
+	s.mu.Lock()
+	s.commands = append(s.commands, cmd)
+
+	if h := s.handlerFor(cmd); h != nil {
+		s.mu.Unlock()
+		for _, reply := range h(cmd) {
+			c.write(reply)
+		}
+		return
+	}
+
+	s.engine.handle(c, cmd)
+	s.mu.Unlock()
+}
+
+func (s *Server) handlerFor(cmd string) HandlerFunc {
+	prefixes := make([]string, 0, len(s.handlers))
+	for p := range s.handlers {
+		if strings.HasPrefix(cmd, p) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+	return s.handlers[prefixes[0]]
+}
+
+func (s *Server) tickLoop() {
+	defer s.wg.Done()
+
+	t := time.NewTicker(s.FieldDuration)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+			s.mu.Lock()
+			s.engine.tick()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// eachConn calls fn for every connected client. s.mu must be held.
+func (s *Server) eachConn(fn func(c *conn)) {
+	for c := range s.conns {
+		fn(c)
+	}
+}
+
+type conn struct {
+	nc        net.Conn
+	out       chan string
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// Per-connection engine state, guarded by Server.mu.
+	videoTallies        bool
+	playStateTally      bool
+	lockTally           bool
+	externalIOTally     bool
+	audioProfileTallies bool
+	mediaTallies        string
+	sessionLocks        uint32
+	queryFolder         string
+	queryIndex          int
+}
+
+func newConn(nc net.Conn) *conn {
+	return &conn{
+		nc:           nc,
+		out:          make(chan string, 4096),
+		closed:       make(chan struct{}),
+		mediaTallies: "000000",
+	}
+}
+
+func (c *conn) write(line string) {
+	if c == nil {
+		return
+	}
+
+	select {
+	case c.out <- line + ":":
+	case <-c.closed:
+	}
+}
+
+func (c *conn) writeLoop() {
+	for {
+		select {
+		case line := <-c.out:
+			if _, err := c.nc.Write([]byte(line)); err != nil {
+				c.close()
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (c *conn) close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		_ = c.nc.Close()
+	})
+}
+
+var unescaper = strings.NewReplacer("\\5C", "\\", "\\7C", "|", "\\3B", ";", "\\3A", ":")
+
+func unescape(cmd string) string {
+	return unescaper.Replace(cmd)
+}
+
+func parseHex(s string) (uint64, bool) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	return v, err == nil
+}
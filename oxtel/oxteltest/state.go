@@ -0,0 +1,317 @@
+package oxteltest
+
+import "fmt"
+
+// Field rate codes reported by the ix (EnquireCurrentTime) response.
+const (
+	fieldRate50   = 2
+	fieldRate5994 = 3
+)
+
+// fps returns the timecode frame base for the configured video standard. Progressive standards count every frame.
+func (e *engine) fps() int {
+	switch e.videoStandard {
+	case 0, 3:
+		return 25
+	case 1, 2:
+		return 30
+	case 5, 7, 9:
+		return 50
+	}
+	return 60
+}
+
+func (e *engine) fieldRate() int {
+	switch e.videoStandard {
+	case 0, 3, 5, 7, 9:
+		return fieldRate50
+	}
+	return fieldRate5994
+}
+
+// dropFrames returns the number of frame numbers skipped at the start of each minute, other than every tenth one.
+func (e *engine) dropFrames() int {
+	if e.fieldRate() != fieldRate5994 {
+		return 0
+	}
+	return e.fps() / 15
+}
+
+func (e *engine) framesPerDay() int {
+	drop := e.dropFrames()
+	return 144 * (600*e.fps() - 9*drop)
+}
+
+func (e *engine) timecodeToFrame(h, m, s, f int) int {
+	fps := e.fps()
+	minutes := 60*h + m
+	return (minutes*60+s)*fps + f - e.dropFrames()*(minutes-minutes/10)
+}
+
+func (e *engine) formatTimecode(frame int) string {
+	fps := e.fps()
+	if drop := e.dropFrames(); drop > 0 {
+		per10 := 600*fps - 9*drop
+		perMinute := 60*fps - drop
+		d, m := frame/per10, frame%per10
+		frame += 9 * drop * d
+		if m >= drop {
+			frame += drop * ((m - drop) / perMinute)
+		}
+	}
+
+	f := frame % fps
+	s := frame / fps % 60
+	m := frame / fps / 60 % 60
+	h := frame / fps / 3600
+	return fmt.Sprintf("%02d%02d%02d%02d", h, m, s, f)
+}
+
+func (e *engine) parseTimecode(tc string) (int, bool) {
+	var h, m, s, f int
+	if n, err := fmt.Sscanf(tc, "%02d%02d%02d%02d", &h, &m, &s, &f); err != nil || n != 4 {
+		return 0, false
+	}
+	if h > 23 || m > 59 || s > 59 || f >= e.fps() {
+		return 0, false
+	}
+	return e.timecodeToFrame(h, m, s, f), true
+}
+
+// AddFile adds a template to the default ($VIDEO) folder and emits a media tally.
+func (s *Server) AddFile(name string) {
+	s.AddFileTo(defaultFolder, name)
+}
+
+// AddFileTo adds a file to the folder with the given alias and emits a media tally.
+func (s *Server) AddFileTo(alias string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.folders[alias] = append(s.engine.folders[alias], file{name: name})
+	s.engine.sendMediaTally(1, name)
+}
+
+// RemoveFile removes a file from every folder and emits a media tally.
+func (s *Server) RemoveFile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for alias, files := range s.engine.folders {
+		kept := files[:0]
+		for _, f := range files {
+			if f.name != name {
+				kept = append(kept, f)
+			}
+		}
+		s.engine.folders[alias] = kept
+	}
+	s.engine.sendMediaTally(0, name)
+}
+
+// ModifyFile emits a media tally reporting that an existing file was modified.
+func (s *Server) ModifyFile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.sendMediaTally(2, name)
+}
+
+// SetMissingAssets sets the missing asset count reported by ValidateTemplate for a file.
+func (s *Server) SetMissingAssets(name string, missing uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, files := range s.engine.folders {
+		for i := range files {
+			if files[i].name == name {
+				files[i].missingAssets = missing
+			}
+		}
+	}
+}
+
+// LoadedTemplate returns the template on air on a layer, or ">Empty<".
+func (s *Server) LoadedTemplate(layer uint8) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].loaded
+}
+
+// PreloadedTemplate returns the template preloaded on a layer, or ">Empty<".
+func (s *Server) PreloadedTemplate(layer uint8) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].preloaded
+}
+
+// TextField returns the current text of a field of the template on air on a layer.
+func (s *Server) TextField(layer uint8, field uint8) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].fields[uint64(field)]
+}
+
+// PreloadedTextField returns the current text of a field of the template preloaded on a layer.
+func (s *Server) PreloadedTextField(layer uint8, field uint8) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].preloadFields[uint64(field)]
+}
+
+// Image returns the image file assigned to a field of the template on air on a layer.
+func (s *Server) Image(layer uint8, field uint8) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].images[uint64(field)]
+}
+
+// ImagePosition returns the position of a layer.
+func (s *Server) ImagePosition(layer uint8) (x int64, y int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.engine.layers[layer]
+	return l.x, l.y
+}
+
+// FaderAngle returns the keyer fader angle of a layer, from 0 (down) to 512 (up).
+func (s *Server) FaderAngle(layer uint8) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return uint16(s.engine.layers[layer].angle)
+}
+
+// Playing reports whether the template on a layer is playing.
+func (s *Server) Playing(layer uint8) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.layers[layer].playing
+}
+
+// MixerPosition returns the A/B mixer position, from 0 (A) to 512 (B).
+func (s *Server) MixerPosition() uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return uint16(s.engine.mixerPosition)
+}
+
+// MixerSource returns the video source routed to a mixer input (0 = A, 1 = B) and its ARC override.
+func (s *Server) MixerSource(input uint8) (source uint8, arc uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return uint8(s.engine.mixerSources[input]), uint8(s.engine.mixerArc[input])
+}
+
+// AudioPosition returns the audio A/B mixer position, from 0 (A) to 512 (B).
+func (s *Server) AudioPosition() uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return uint16(s.engine.audioPosition)
+}
+
+// AudioGain returns the gain in dB of a channel (1-16) of an audio output.
+func (s *Server) AudioGain(output uint8, channel int) int8 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int8(s.engine.audioGains[uint64(output)][channel-1])
+}
+
+// SetPermanentLocks sets the permanent locks and emits a lock tally.
+func (s *Server) SetPermanentLocks(locks uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.permanentLocks = locks
+	s.engine.sendLockTally()
+}
+
+// GlobalSessionLocks returns the union of the session locks held by every connection.
+func (s *Server) GlobalSessionLocks() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.globalSessionLockBits()
+}
+
+// SetTime sets the VITC clock.
+func (s *Server) SetTime(hours, minutes, seconds, frames int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.frame = s.engine.timecodeToFrame(hours, minutes, seconds, frames) % s.engine.framesPerDay()
+}
+
+// Time returns the VITC clock as "HHMMSSFF".
+func (s *Server) Time() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.engine.formatTimecode(s.engine.frame)
+}
+
+// ScheduledCommands returns the commands still waiting in the scheduler, in the order they were added.
+func (s *Server) ScheduledCommands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]string, 0, len(s.engine.schedule))
+	for _, sc := range s.engine.schedule {
+		out = append(out, s.engine.formatTimecode(sc.frame)+";"+sc.cmd)
+	}
+	return out
+}
+
+// SetVideoStandard sets the video standard reported by the M (EnquireSystemStatus) response. It also sets the clock
+// rate and field rate reported by ix.
+func (s *Server) SetVideoStandard(standard uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.videoStandard = uint64(standard)
+	s.engine.frame %= s.engine.framesPerDay()
+}
+
+// SetLatency sets the latency, in reference frames, reported for a latency source.
+func (s *Server) SetLatency(source uint8, latency int8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.latencies[uint64(source)] = int64(latency)
+}
+
+// SetNumberOfGraphicLayers sets the number of licensed layers reported by hNGL.
+func (s *Server) SetNumberOfGraphicLayers(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.numLayers = n
+}
+
+// SetCommandAvailable sets whether EnquireCommandAvailability reports a command prefix as supported. Every command
+// is reported as supported by default.
+func (s *Server) SetCommandAvailable(prefix string, available bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.unsupported[prefix] = !available
+}
+
+// SetExternalInputs replaces the list returned by the hXIN (EnquireExternalInputs) enquiry.
+func (s *Server) SetExternalInputs(inputs []ExternalInput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.engine.externalInputs = inputs
+}
@@ -0,0 +1,616 @@
+package oxtel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxCommandPrefix is the length of the longest prefix in commandParsers.
+const maxCommandPrefix = 6
+
+// commandParsers maps each command prefix to the function decoding the arguments that follow it. Prefixes shared by a
+// command and its enquiry are told apart by their arguments.
+var commandParsers map[string]func(r *commandReader) Command
+
+func init() {
+	commandParsers = map[string]func(r *commandReader) Command{
+		// Keyers
+		"1": func(r *commandReader) Command {
+			c := FadeKeyerCommand{Layer: OxtelLayer(r.hex(1, 8))}
+			r.space()
+			c.Direction = OxtelDirection(r.decField(8))
+			if !r.empty() {
+				r.space()
+				rate := uint16(r.hexField(16))
+				c.Rate = &rate
+			}
+			return c
+		},
+		"3": func(r *commandReader) Command {
+			c := CutKeyerCommand{Layer: OxtelLayer(r.hex(1, 8))}
+			r.space()
+			c.Direction = OxtelDirection(r.decField(8))
+			return c
+		},
+		"B": func(r *commandReader) Command {
+			c := SetTransitionDurationCommand{Layer: OxtelLayer(r.hex(1, 8))}
+			r.literal(" 1 ")
+			c.Rate = uint16(r.hexField(16))
+			return c
+		},
+		"@": func(r *commandReader) Command {
+			c := SetFaderAngleCommand{Layer: OxtelLayer(r.hex(1, 8))}
+			r.literal(" 1 ")
+			c.Angle = uint16(r.hexField(16))
+			return c
+		},
+
+		// Templates
+		"R0": func(r *commandReader) Command {
+			layer := OxtelLayer(r.hex(1, 8))
+			if r.empty() {
+				return EnquireLoadImageCommand{Layer: layer}
+			}
+			return LoadImageCommand{Layer: layer, TemplateName: r.rest()}
+		},
+		"R7": func(r *commandReader) Command {
+			layer := OxtelLayer(r.hex(1, 8))
+			if r.empty() {
+				return EnquirePreloadImageCommand{Layer: layer}
+			}
+			return PreloadImageCommand{Layer: layer, TemplateName: r.rest()}
+		},
+		"A": func(r *commandReader) Command {
+			return EraseStoreCommand{Layer: OxtelLayer(r.hex(1, 8))}
+		},
+		"G": func(r *commandReader) Command {
+			layer := OxtelLayer(r.hex(1, 8))
+			if r.empty() {
+				return EnquireImagePositionCommand{Layer: layer}
+			}
+			c := SetImagePositionCommand{Layer: layer}
+			r.space()
+			c.XOffset = int16(r.signedHexField(16))
+			r.space()
+			c.YOffset = int16(r.signedHexField(16))
+			return c
+		},
+
+		// Template file management
+		"R3": func(r *commandReader) Command {
+			return EnquireFileInfoCommand{FileName: r.rest()}
+		},
+		"R4": func(r *commandReader) Command {
+			folderName := r.rest()
+			return QueryFirstFileCommand{FolderName: &folderName}
+		},
+		"R5": func(r *commandReader) Command {
+			folderName := r.rest()
+			return QuerySubsequentFileCommand{FolderName: &folderName}
+		},
+		"R6": func(r *commandReader) Command {
+			return EnquireExtendedFileInformationCommand{FileName: r.rest()}
+		},
+		"RA": func(r *commandReader) Command {
+			return ValidateTemplateCommand{FileName: r.rest()}
+		},
+		"YB": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireMediaTalliesCommand{}
+			}
+			data, err := parseMediaTallies(r.rest())
+			r.fail(err)
+			return EnableMediaTalliesCommand{Data: data}
+		},
+
+		// Animations
+		"S0": func(r *commandReader) Command {
+			return StartAnimationCommand{Layer: OxtelLayer(r.hex(1, 8))}
+		},
+		"S1": func(r *commandReader) Command {
+			return StopAnimationCommand{Layer: OxtelLayer(r.hex(1, 8)), Immediate: r.flag()}
+		},
+		"S2": func(r *commandReader) Command {
+			return SelectionAnimationFrameCommand{Layer: OxtelLayer(r.hex(1, 8)), Frame: uint32(r.hexField(32))}
+		},
+		"S4": func(r *commandReader) Command {
+			return RestartAnimationCommand{Layer: OxtelLayer(r.hex(1, 8))}
+		},
+		"YS": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquirePlayStateTallyCommand{}
+			}
+			return EnablePlayStateTallyCommand{Enable: r.flag()}
+		},
+
+		// EasyText
+		"Z0": func(r *commandReader) Command {
+			layer := OxtelLayer(r.hex(1, 8))
+			field := uint8(r.hex(2, 8))
+			if r.empty() {
+				return RenderBoxCommand{Layer: layer, Field: field}
+			}
+			return UpdateTextFieldCommand{Layer: layer, Field: field, Flags: OxtelUpdateTextFieldFlag(r.hex(1, 8)), Text: r.rest()}
+		},
+		"hZ0": func(r *commandReader) Command {
+			return UpdatePreloadedTextFieldCommand{
+				Layer: OxtelLayer(r.hex(1, 8)),
+				Field: uint8(r.hex(2, 8)),
+				Flags: OxtelUpdateTextFieldFlag(r.hex(1, 8)),
+				Text:  r.rest(),
+			}
+		},
+		"Z4": func(r *commandReader) Command {
+			return ChangeImageCommand{Layer: OxtelLayer(r.hex(1, 8)), Field: uint8(r.hex(2, 8)), FileName: r.rest()}
+		},
+		"Zf": func(r *commandReader) Command {
+			return StopTextFieldAnimationCommand{Layer: OxtelLayer(r.hex(1, 8)), Field: uint8(r.hex(2, 8)), Immediate: r.flag()}
+		},
+		"Zg": func(r *commandReader) Command {
+			return PauseRestartStrapCommand{Layer: OxtelLayer(r.hex(1, 8)), Field: uint8(r.hex(2, 8)), Restart: r.flag()}
+		},
+
+		// A/B mixer
+		"U0": func(r *commandReader) Command { return CutToACommand{} },
+		"U1": func(r *commandReader) Command { return CutToBCommand{} },
+		"U2": func(r *commandReader) Command { return FadeToACommand{Duration: uint16(r.hex(3, 16))} },
+		"U3": func(r *commandReader) Command { return FadeToBCommand{Duration: uint16(r.hex(3, 16))} },
+		"U4": func(r *commandReader) Command { return CutABCommand{} },
+		"U5": func(r *commandReader) Command { return FadeABCommand{Duration: uint16(r.hex(3, 16))} },
+		"U6": func(r *commandReader) Command {
+			return SetTransitionTypeCommand{TransitionType: OxtelTransitionType(r.hex(2, 8))}
+		},
+		"U8": func(r *commandReader) Command {
+			return AsymmetricVFadeABCommand{DownDuration: uint16(r.hex(3, 16)), UpDuration: uint16(r.hex(3, 16))}
+		},
+		"U9": func(r *commandReader) Command { return SetAbsoluteMixCommand{Mix: uint16(r.hex(3, 16))} },
+		"UA": func(r *commandReader) Command {
+			return AsymmetricTransitionCommand{
+				Destination:  OxtelMixerInput(r.hex(1, 8)),
+				DownDuration: uint16(r.hex(3, 16)),
+				UpDuration:   uint16(r.hex(3, 16)),
+			}
+		},
+		"UC": func(r *commandReader) Command {
+			return FadeToSpecificPositionCommand{Destination: uint16(r.hex(3, 16)), Duration: uint16(r.hex(3, 16))}
+		},
+		"UE": func(r *commandReader) Command {
+			r.space()
+			input := OxtelMixerInput(r.hexField(8))
+			if r.empty() {
+				return EnquireMixerInputCommand{Input: input}
+			}
+			r.space()
+			c := SelectMixerInputCommand{Input: input, Source: OxtelVideoSource(r.hexField(8))}
+			if !r.empty() {
+				r.space()
+				arc := OxtelARC(r.hexField(8))
+				c.ARC = &arc
+			}
+			return c
+		},
+		"Ua": func(r *commandReader) Command { return EnquireMixModeCommand{} },
+		"UZ": func(r *commandReader) Command {
+			if len(r.args) == 1 {
+				return EnquireColorGeneratorColorCommand{Unit: uint8(r.dec(1, 8))}
+			}
+			return SetColorGeneratorColorCommand{
+				Unit:  uint8(r.hex(1, 8)),
+				Red:   uint8(r.hex(2, 8)),
+				Green: uint8(r.hex(2, 8)),
+				Blue:  uint8(r.hex(2, 8)),
+			}
+		},
+		"Y6": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireVideoTalliesCommand{}
+			}
+			return EnableVideoTalliesCommand{Enable: r.flag()}
+		},
+
+		// Audio
+		"hDP": func(r *commandReader) Command {
+			return AudioPopSuppressionCommand{Source: OxtelAudioSource(r.hex(1, 8)), Enable: r.flag()}
+		},
+		"hDE": func(r *commandReader) Command {
+			return PauseResumeDolbyEncoderCommand{Input: OxtelMixerInput(r.hex(1, 8)), Pause: r.flag()}
+		},
+		"hDA": func(r *commandReader) Command {
+			input := OxtelMixerInput(r.hex(1, 8))
+			if r.empty() {
+				return EnquireDolbyEncoderProfileCommand{Input: input}
+			}
+			return SetDolbyEncoderProfileCommand{Input: input, Profile: uint8(r.hex(1, 8))}
+		},
+
+		// Audio loudness
+		"jAL": func(r *commandReader) Command {
+			if len(r.args) <= 4 {
+				c := GetAudioLoudnessCommand{Sdi: uint8(r.hex(2, 8))}
+				if !r.empty() {
+					channel := uint8(r.hex(2, 8))
+					c.Channel = &channel
+				}
+				return c
+			}
+
+			programs := strings.Split(r.rest(), "jAL")
+			if len(programs) > 2 {
+				r.fail(fmt.Errorf("%d audio programs, at most 2 are supported", len(programs)))
+				return nil
+			}
+			c := SetAudioLoudnessCommand{FirstProgram: parseAudioProgram(r, programs[0])}
+			if len(programs) == 2 {
+				second := parseAudioProgram(r, programs[1])
+				c.SecondProgram = &second
+			}
+			return c
+		},
+		"jALP": func(r *commandReader) Command { return DisableAudioLoudnessCommand{Sdi: uint8(r.hex(2, 8))} },
+		"jALR": func(r *commandReader) Command { return EnableAudioLoudnessCommand{Sdi: uint8(r.hex(2, 8))} },
+		"jALA": func(r *commandReader) Command {
+			sdi := uint8(r.hex(2, 8))
+			if r.empty() {
+				return GetAudioLoudnessProfileCommand{Sdi: sdi}
+			}
+			return ChangeAudioLoudnessProfileCommand{Sdi: sdi, Profile: uint8(r.hex(2, 8))}
+		},
+		"jALL": func(r *commandReader) Command { return GetLoudnessLicenseStatusCommand{} },
+
+		// Audio mixer
+		"j31": func(r *commandReader) Command { return SetAudioABMixerFadeRateCommand{Duration: uint16(r.hex(3, 16))} },
+		"j40": func(r *commandReader) Command { return AudioCutABCommand{Destination: OxtelMixerInput(r.hex(1, 8))} },
+		"j41": func(r *commandReader) Command { return AudioFadeABCommand{Destination: OxtelMixerInput(r.hex(1, 8))} },
+		"j51": func(r *commandReader) Command { return SetAudioABFollowVideoABCommand{Enable: r.flag()} },
+		"j74": func(r *commandReader) Command { return EnquireAudioABFollowVideoABCommand{} },
+		"ja":  func(r *commandReader) Command { return SetAudioABPositionCommand{Mix: uint16(r.hex(3, 16))} },
+		"jb":  func(r *commandReader) Command { return SetAudioABMixModeCommand{Mode: OxtelAudioMixMode(r.hex(1, 8))} },
+		"jc": func(r *commandReader) Command {
+			return AudioABAsymmetricTransitionCommand{
+				Direction: OxtelMixerInput(r.hex(1, 8)),
+				Rate1:     uint16(r.hex(3, 16)),
+				Rate2:     uint16(r.hex(3, 16)),
+			}
+		},
+		"jd": func(r *commandReader) Command {
+			return AudioABFadeToPositionCommand{Mix: uint16(r.hex(3, 16)), Duration: uint16(r.hex(3, 16))}
+		},
+		"jAG": func(r *commandReader) Command {
+			output := OxtelAudioOutput(r.hex(2, 8))
+			mask := ChannelMask(r.hex(4, 16))
+			if r.empty() {
+				return EnquireAudioGainCommand{Output: output, ChannelMask: mask}
+			}
+			gain := int8(r.signedField(8))
+			return SetAudioGainCommand{Output: output, ChannelMask: mask, Gain: &gain}
+		},
+
+		// Audio profiles
+		"jAP": func(r *commandReader) Command {
+			source := OxtelAudioSource(r.hex(1, 8))
+			if r.empty() {
+				return EnquireAudioProfileCommand{Source: source}
+			}
+			return SetAudioProfileCommand{Source: source, Profile: uint8(r.hex(2, 8))}
+		},
+		"jAT": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireAudioProfileTalliesCommand{}
+			}
+			return EnableAudioProfileTalliesCommand{Enable: r.flag()}
+		},
+
+		// Locks
+		"hSL": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireSessionLocksCommand{}
+			}
+			return SetSessionLocksCommand{Locks: int32(r.hex(8, 32))}
+		},
+		"hGSL": func(r *commandReader) Command { return EnquireGlobalSessionLocksCommand{} },
+		"hPL": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquirePermanentLocksCommand{}
+			}
+			return SetPermanentLocksCommand{Locks: int32(r.hex(8, 32))}
+		},
+		"hOLT": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireOxtelLockTallyCommand{}
+			}
+			return EnableOxtelLockTallyCommand{Enable: r.flag()}
+		},
+
+		// Scheduler
+		"i0": func(r *commandReader) Command {
+			tc := Timecode{
+				Hours:   uint8(r.dec(2, 8)),
+				Minutes: uint8(r.dec(2, 8)),
+				Seconds: uint8(r.dec(2, 8)),
+				Frames:  uint8(r.dec(2, 8)),
+			}
+			r.literal(";")
+			if r.err != nil {
+				return nil
+			}
+			cmd, err := parseCommand(r.rest())
+			r.fail(err)
+			return ScheduledCommand{Time: tc, Command: cmd}
+		},
+		"i2": func(r *commandReader) Command { return DeleteAllScheduledCommandsCommand{} },
+		"ix": func(r *commandReader) Command { return EnquireCurrentTimeCommand{} },
+
+		// System status
+		"X0": func(r *commandReader) Command { return EnquireTemperatureCommand{} },
+		"hLAT": func(r *commandReader) Command {
+			return EnquireLatencyCommand{Source: OxtelLatencySource(r.hexField(8))}
+		},
+		"hNGL": func(r *commandReader) Command { return EnquireNumberOfGraphicLayersCommand{} },
+		"M":    func(r *commandReader) Command { return EnquireSystemStatusCommand{} },
+		"N":    func(r *commandReader) Command { return EnquireVideoLayerStatusCommand{Layer: OxtelLayer(r.hex(1, 8))} },
+		"X3": func(r *commandReader) Command {
+			bytes := r.take(2)
+			if r.err != nil {
+				return nil
+			}
+			return EnquireCommandAvailabilityCommand{Byte1: bytes[0], Byte2: bytes[1]}
+		},
+		"XA":  func(r *commandReader) Command { return EnquireSlaveLayerStatusCommand{} },
+		"Xb":  func(r *commandReader) Command { return EnquireFullVersionNumberCommand{} },
+		"Xn":  func(r *commandReader) Command { return EnquireProductNameCommand{} },
+		"hTN": func(r *commandReader) Command { return EnquireMediaPortNameCommand{} },
+
+		// Watermarking
+		"hCSI": func(r *commandReader) Command {
+			return OverrideSDIInputColorSpaceCommand{Input: OxtelMixerInput(r.hex(1, 8)), ColorSpace: OxtelColorSpace(r.hex(1, 8))}
+		},
+		"hKWM": func(r *commandReader) Command {
+			return ChangeKantarWatermarkingChannelNameCommand{
+				Output:          OxtelKantarOutput(r.hex(1, 8)),
+				StartingChannel: uint8(r.hex(2, 8)),
+				AudienceName:    r.rest(),
+			}
+		},
+
+		// External IO
+		"hXNC": func(r *commandReader) Command {
+			ioType := OxtelExternalIOType(r.hex(2, 8))
+			direction := OxtelExternalIODirection(r.hex(2, 8))
+			if r.empty() {
+				return EnquireNumberOfExternalIOConfigurationsCommand{IOType: ioType, Direction: direction}
+			}
+			return EnquireExternalIOConfigurationCommand{IOType: ioType, Direction: direction, Index: uint8(r.hex(2, 8))}
+		},
+		"hXS": func(r *commandReader) Command {
+			direction := OxtelExternalIODirection(r.hex(2, 8))
+			ioId := OxtelExternalIOId(r.hex(2, 8))
+			if r.empty() {
+				return EnquireExternalIOSourceCommand{Direction: direction, IOId: ioId}
+			}
+			return SetExternalIOSourceCommand{
+				Direction:    direction,
+				IOId:         ioId,
+				IOType:       OxtelExternalIOType(r.hex(2, 8)),
+				ConfigId:     uint8(r.hex(2, 8)),
+				ForceRestart: r.hex(2, 8) != 0,
+			}
+		},
+		"hXDC": func(r *commandReader) Command {
+			direction := OxtelExternalIODirection(r.hex(2, 8))
+			ioId := OxtelExternalIOId(r.hex(2, 8))
+			ioType := OxtelExternalIOType(r.hex(2, 8))
+			if r.empty() {
+				return EnquireExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType}
+			}
+
+			parts := strings.Split(r.rest(), ",")
+			flags, err := strconv.ParseUint(parts[0], 16, 16)
+			r.fail(err)
+			c := SetExternalIODynamicConfigurationCommand{Direction: direction, IOId: ioId, IOType: ioType, Flags: uint16(flags)}
+
+			want := map[OxtelExternalIOType]int{
+				OXTEL_EXT_IO_TYPE_2022_6:        4,
+				OXTEL_EXT_IO_TYPE_2022_6_2022_7: 6,
+				OXTEL_EXT_IO_TYPE_2110:          3,
+			}[ioType]
+			if len(parts) != want {
+				r.fail(fmt.Errorf("%d parameters for IO type %d", len(parts)-1, ioType))
+				return nil
+			}
+
+			c.LocalInterface = parts[1]
+			switch ioType {
+			case OXTEL_EXT_IO_TYPE_2022_6:
+				c.IPAddress, c.Port = &parts[2], &parts[3]
+			case OXTEL_EXT_IO_TYPE_2022_6_2022_7:
+				c.IPAddress, c.Port, c.IPAddress2, c.Port2 = &parts[2], &parts[3], &parts[4], &parts[5]
+			case OXTEL_EXT_IO_TYPE_2110:
+				c.SDPFileName = &parts[2]
+			}
+			return c
+		},
+		"hXIOT": func(r *commandReader) Command {
+			if r.empty() {
+				return EnquireExternalIOTallyCommand{}
+			}
+			return EnableExternalIOTallyCommand{Enable: r.flag()}
+		},
+		"hEXTIO": func(r *commandReader) Command { return EnquireExternalIOSupportedCommand{} },
+		"hXIN":   func(r *commandReader) Command { return EnquireExternalInputsCommand{} },
+		"hOUT":   func(r *commandReader) Command { return EnquireOutputsCommand{} },
+	}
+}
+
+// ParseCommand decodes a command string back into the typed Command that encodes to it. It accepts the strings
+// returned by Encode and the _AsString functions as well as escaped commands as sent to the engine, without the ':'
+// terminator. The command of a ScheduledCommand is parsed too.
+//
+// Where a command and an enquiry encode to the same string, such as SetAudioGain without a gain and EnquireAudioGain,
+// the enquiry is returned. The parsed command is not validated.
+func ParseCommand(cmd string) (Command, error) {
+	return parseCommand(unescapeCommand(cmd))
+}
+
+func parseCommand(cmd string) (Command, error) {
+	for n := maxCommandPrefix; n > 0; n-- {
+		if n > len(cmd) {
+			continue
+		}
+		parse, ok := commandParsers[cmd[:n]]
+		if !ok {
+			continue
+		}
+
+		r := &commandReader{args: cmd[n:]}
+		parsed := parse(r)
+		if r.err == nil && !r.empty() {
+			r.err = fmt.Errorf("unexpected trailing arguments %q", r.args)
+		}
+		if r.err != nil {
+			return nil, &InvalidCommandError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Unable to parse command %q: %v", cmd, r.err),
+				},
+			}
+		}
+		return parsed, nil
+	}
+
+	return nil, &InvalidCommandError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf("Unknown command %q", cmd),
+		},
+	}
+}
+
+func parseAudioProgram(r *commandReader, program string) AudioProgram {
+	pr := &commandReader{args: program}
+	p := AudioProgram{
+		Sdi:          uint8(pr.hex(2, 8)),
+		AudioProgram: OxtelAudioProgram(pr.hex(2, 8)),
+		JungerPreset: OxtelJungerPreset(pr.hex(2, 8)),
+		Channel1:     uint8(pr.hex(2, 8)),
+	}
+	for _, channel := range []**uint8{&p.Channel2, &p.Channel3, &p.Channel4, &p.Channel5, &p.Channel6, &p.Channel7, &p.Channel8} {
+		if pr.empty() {
+			break
+		}
+		v := uint8(pr.hex(2, 8))
+		*channel = &v
+	}
+	if pr.err == nil && !pr.empty() {
+		pr.err = fmt.Errorf("more than 8 channels in audio program %q", program)
+	}
+	r.fail(pr.err)
+	return p
+}
+
+// commandReader consumes the arguments of a command one field at a time. The first error is kept in err and every
+// later read returns a zero value.
+type commandReader struct {
+	args string
+	err  error
+}
+
+func (r *commandReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *commandReader) empty() bool {
+	return r.args == ""
+}
+
+func (r *commandReader) take(n int) string {
+	if r.err != nil {
+		return ""
+	}
+	if len(r.args) < n {
+		r.err = fmt.Errorf("expected %d more characters, got %q", n, r.args)
+		return ""
+	}
+	s := r.args[:n]
+	r.args = r.args[n:]
+	return s
+}
+
+func (r *commandReader) rest() string {
+	return r.take(len(r.args))
+}
+
+// field takes everything up to the next space.
+func (r *commandReader) field() string {
+	if i := strings.IndexByte(r.args, ' '); i >= 0 {
+		return r.take(i)
+	}
+	return r.rest()
+}
+
+func (r *commandReader) literal(s string) {
+	if got := r.take(len(s)); r.err == nil && got != s {
+		r.err = fmt.Errorf("expected %q, got %q", s, got)
+	}
+}
+
+func (r *commandReader) space() {
+	r.literal(" ")
+}
+
+func (r *commandReader) parseUint(s string, base int, bitSize int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseUint(s, base, bitSize)
+	r.fail(err)
+	return v
+}
+
+// hex reads an n digit hexadecimal number.
+func (r *commandReader) hex(n int, bitSize int) uint64 {
+	return r.parseUint(r.take(n), 16, bitSize)
+}
+
+// dec reads an n digit decimal number.
+func (r *commandReader) dec(n int, bitSize int) uint64 {
+	return r.parseUint(r.take(n), 10, bitSize)
+}
+
+func (r *commandReader) hexField(bitSize int) uint64 {
+	return r.parseUint(r.field(), 16, bitSize)
+}
+
+func (r *commandReader) decField(bitSize int) uint64 {
+	return r.parseUint(r.field(), 10, bitSize)
+}
+
+// signedHexField reads a hexadecimal field with an optional '-' sign.
+func (r *commandReader) signedHexField(bitSize int) int64 {
+	s := r.field()
+	if r.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 16, bitSize)
+	r.fail(err)
+	return v
+}
+
+func (r *commandReader) signedField(bitSize int) int64 {
+	s := r.field()
+	if r.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, bitSize)
+	r.fail(err)
+	return v
+}
+
+// flag reads a single 0 or 1 digit.
+func (r *commandReader) flag() bool {
+	v := r.hex(1, 8)
+	if r.err == nil && v > 1 {
+		r.err = fmt.Errorf("expected 0 or 1, got %d", v)
+	}
+	return v == 1
+}
@@ -0,0 +1,174 @@
+package oxtel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandRoundTrip(t *testing.T) {
+	rate := uint16(0x40)
+	gain := int8(-12)
+	arc := OxtelARC(2)
+	channel := uint8(3)
+	folder := "$AUDIO"
+	ip, port, ip2, port2 := "239.0.0.1", "5000", "239.0.0.2", "5002"
+	sdp := "out.sdp"
+
+	cmds := []Command{
+		FadeKeyerCommand{Layer: 2, Direction: OXTEL_DIR_UP},
+		FadeKeyerCommand{Layer: 2, Direction: OXTEL_DIR_DOWN, Rate: &rate},
+		CutKeyerCommand{Layer: 7, Direction: OXTEL_DIR_TOGGLE},
+		SetTransitionDurationCommand{Layer: 1, Rate: 0x3e8},
+		SetFaderAngleCommand{Layer: 1, Angle: 0x100},
+		LoadImageCommand{Layer: 3, TemplateName: "lower third.html"},
+		EnquireLoadImageCommand{Layer: 3},
+		PreloadImageCommand{Layer: 4, TemplateName: "bug.html"},
+		EnquirePreloadImageCommand{Layer: 4},
+		EraseStoreCommand{Layer: 5},
+		SetImagePositionCommand{Layer: 0, XOffset: 0x2d0, YOffset: 0x10},
+		SetImagePositionCommand{Layer: 2, XOffset: -0x40, YOffset: -1},
+		EnquireImagePositionCommand{Layer: 0},
+		EnquireFileInfoCommand{FileName: "a.html"},
+		QueryFirstFileCommand{FolderName: &folder},
+		QuerySubsequentFileCommand{FolderName: &folder},
+		EnquireExtendedFileInformationCommand{FileName: "a.html"},
+		ValidateTemplateCommand{FileName: "a.html"},
+		EnableMediaTalliesCommand{Data: MediaTallies{Images: true}},
+		EnquireMediaTalliesCommand{},
+		StartAnimationCommand{Layer: 1},
+		StopAnimationCommand{Layer: 1, Immediate: true},
+		SelectionAnimationFrameCommand{Layer: 1, Frame: 0x1234},
+		RestartAnimationCommand{Layer: 1},
+		EnablePlayStateTallyCommand{Enable: true},
+		EnquirePlayStateTallyCommand{},
+		UpdateTextFieldCommand{Layer: 1, Field: 0x12, Flags: 1, Text: "Score: 1|2; a\\b"},
+		UpdatePreloadedTextFieldCommand{Layer: 1, Field: 2, Text: "Next"},
+		RenderBoxCommand{Layer: 1, Field: 2},
+		ChangeImageCommand{Layer: 1, Field: 2, FileName: "logo.png"},
+		StopTextFieldAnimationCommand{Layer: 1, Field: 2, Immediate: true},
+		PauseRestartStrapCommand{Layer: 1, Field: 2, Restart: true},
+		CutToACommand{},
+		CutToBCommand{},
+		FadeToACommand{Duration: 0x19},
+		FadeToBCommand{Duration: 0x19},
+		CutABCommand{},
+		FadeABCommand{Duration: 0x32},
+		SetTransitionTypeCommand{TransitionType: 1},
+		AsymmetricVFadeABCommand{DownDuration: 0x10, UpDuration: 0x20},
+		SetAbsoluteMixCommand{Mix: 0x80},
+		AsymmetricTransitionCommand{Destination: 1, DownDuration: 0x10, UpDuration: 0x20},
+		FadeToSpecificPositionCommand{Destination: 0x100, Duration: 0x19},
+		SelectMixerInputCommand{Input: 1, Source: 0x11},
+		SelectMixerInputCommand{Input: 1, Source: 0x11, ARC: &arc},
+		EnquireMixerInputCommand{Input: 1},
+		EnquireMixModeCommand{},
+		SetColorGeneratorColorCommand{Unit: 1, Red: 0xff, Green: 0x80, Blue: 0},
+		EnquireColorGeneratorColorCommand{Unit: 1},
+		EnableVideoTalliesCommand{Enable: true},
+		EnquireVideoTalliesCommand{},
+		AudioPopSuppressionCommand{Source: 1, Enable: true},
+		PauseResumeDolbyEncoderCommand{Input: 1, Pause: true},
+		SetDolbyEncoderProfileCommand{Input: 1, Profile: 3},
+		EnquireDolbyEncoderProfileCommand{Input: 1},
+		SetAudioLoudnessCommand{FirstProgram: AudioProgram{Sdi: 1, AudioProgram: 2, JungerPreset: 3, Channel1: 1, Channel2: &channel}},
+		SetAudioLoudnessCommand{
+			FirstProgram:  AudioProgram{Sdi: 1, Channel1: 1},
+			SecondProgram: &AudioProgram{Sdi: 2, Channel1: 2, Channel2: &channel},
+		},
+		GetAudioLoudnessCommand{Sdi: 1},
+		GetAudioLoudnessCommand{Sdi: 1, Channel: &channel},
+		DisableAudioLoudnessCommand{Sdi: 1},
+		EnableAudioLoudnessCommand{Sdi: 1},
+		ChangeAudioLoudnessProfileCommand{Sdi: 1, Profile: 4},
+		GetAudioLoudnessProfileCommand{Sdi: 1},
+		GetLoudnessLicenseStatusCommand{},
+		SetAudioABMixerFadeRateCommand{Duration: 0x19},
+		AudioCutABCommand{Destination: 1},
+		AudioFadeABCommand{Destination: 0},
+		SetAudioABFollowVideoABCommand{Enable: true},
+		EnquireAudioABFollowVideoABCommand{},
+		SetAudioABPositionCommand{Mix: 0x80},
+		SetAudioABMixModeCommand{Mode: 1},
+		AudioABAsymmetricTransitionCommand{Direction: 1, Rate1: 0x10, Rate2: 0x20},
+		AudioABFadeToPositionCommand{Mix: 0x80, Duration: 0x19},
+		SetAudioGainCommand{Output: 1, ChannelMask: MakeFourChannelMask(), Gain: &gain},
+		EnquireAudioGainCommand{Output: 1, ChannelMask: MakeTwoChannelMask()},
+		SetAudioProfileCommand{Source: 1, Profile: 0x0f},
+		EnquireAudioProfileCommand{Source: 1},
+		EnableAudioProfileTalliesCommand{Enable: true},
+		EnquireAudioProfileTalliesCommand{},
+		SetSessionLocksCommand{Locks: 0x101},
+		EnquireSessionLocksCommand{},
+		EnquireGlobalSessionLocksCommand{},
+		SetPermanentLocksCommand{Locks: 0x1},
+		EnquirePermanentLocksCommand{},
+		EnableOxtelLockTallyCommand{Enable: true},
+		EnquireOxtelLockTallyCommand{},
+		ScheduledCommand{Time: Timecode{Hours: 10, Minutes: 1, Seconds: 2, Frames: 3}, Command: LoadImageCommand{Layer: 1, TemplateName: "a:b.html"}},
+		DeleteAllScheduledCommandsCommand{},
+		EnquireCurrentTimeCommand{},
+		EnquireTemperatureCommand{},
+		EnquireLatencyCommand{Source: 1},
+		EnquireNumberOfGraphicLayersCommand{},
+		EnquireSystemStatusCommand{},
+		EnquireVideoLayerStatusCommand{Layer: 1},
+		EnquireCommandAvailabilityCommand{Byte1: 'R', Byte2: '0'},
+		EnquireSlaveLayerStatusCommand{},
+		EnquireFullVersionNumberCommand{},
+		EnquireProductNameCommand{},
+		EnquireMediaPortNameCommand{},
+		OverrideSDIInputColorSpaceCommand{Input: 1, ColorSpace: 1},
+		ChangeKantarWatermarkingChannelNameCommand{Output: 1, StartingChannel: 4, AudienceName: "Main"},
+		EnquireNumberOfExternalIOConfigurationsCommand{IOType: OXTEL_EXT_IO_TYPE_2110, Direction: 1},
+		EnquireExternalIOConfigurationCommand{IOType: OXTEL_EXT_IO_TYPE_2110, Direction: 1, Index: 2},
+		SetExternalIOSourceCommand{Direction: 1, IOId: 2, IOType: OXTEL_EXT_IO_TYPE_2022_6, ConfigId: 3, ForceRestart: true},
+		EnquireExternalIOSourceCommand{Direction: 1, IOId: 2},
+		SetExternalIODynamicConfigurationCommand{Direction: 1, IOId: 2, IOType: OXTEL_EXT_IO_TYPE_2022_6, Flags: 0x1ff, LocalInterface: "eth0", IPAddress: &ip, Port: &port},
+		SetExternalIODynamicConfigurationCommand{Direction: 1, IOId: 2, IOType: OXTEL_EXT_IO_TYPE_2022_6_2022_7, LocalInterface: "eth0", IPAddress: &ip, Port: &port, IPAddress2: &ip2, Port2: &port2},
+		SetExternalIODynamicConfigurationCommand{Direction: 1, IOId: 2, IOType: OXTEL_EXT_IO_TYPE_2110, LocalInterface: "eth1", SDPFileName: &sdp},
+		EnquireExternalIODynamicConfigurationCommand{Direction: 1, IOId: 2, IOType: OXTEL_EXT_IO_TYPE_2110},
+		EnableExternalIOTallyCommand{Enable: true},
+		EnquireExternalIOTallyCommand{},
+		EnquireExternalIOSupportedCommand{},
+		EnquireExternalInputsCommand{},
+		EnquireOutputsCommand{},
+	}
+
+	for _, cmd := range cmds {
+		for _, encoded := range []string{cmd.Encode(), escapeCommand(cmd.Encode())} {
+			parsed, err := ParseCommand(encoded)
+			if err != nil {
+				t.Errorf("%T: %v", cmd, err)
+				continue
+			}
+			if !reflect.DeepEqual(parsed, cmd) {
+				t.Errorf("%q parsed as %#v, want %#v", encoded, parsed, cmd)
+			}
+		}
+	}
+}
+
+func TestParseCommandAsString(t *testing.T) {
+	scheduled := AddScheduledCommand_AsString(Timecode{Hours: 23, Minutes: 59}, FadeKeyer_AsString(0, OXTEL_DIR_UP, nil))
+
+	cmd, err := ParseCommand(escapeCommand(scheduled))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ScheduledCommand{
+		Time:    Timecode{Hours: 23, Minutes: 59},
+		Command: FadeKeyerCommand{Layer: 0, Direction: OXTEL_DIR_UP},
+	}
+	if !reflect.DeepEqual(cmd, want) {
+		t.Fatalf("got %#v, want %#v", cmd, want)
+	}
+}
+
+func TestParseCommandErrors(t *testing.T) {
+	for _, s := range []string{"", "?", "R", "1x 1", "U2zz", "Y62", "i0999999;U0", "i000000000;?", "YB0101", "U0 extra", "hXDC010203ff,eth0"} {
+		_, err := ParseCommand(s)
+		if _, ok := err.(*InvalidCommandError); !ok {
+			t.Errorf("%q: expected an InvalidCommandError, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package oxtel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PreflightCheck lists what a show needs from the engine.
+type PreflightCheck struct {
+	Templates []string
+	Layers    []OxtelLayer
+
+	// Mixer checks that the A/B mixer is not locked by another client.
+	Mixer bool
+
+	// Commands are the two-character prefixes of the commands the show sends, such as "R0" for LoadImage.
+	Commands []string
+}
+
+// PreflightReport is the readiness of the engine for a PreflightCheck. Ready is false if any item has a problem.
+type PreflightReport struct {
+	Ready     bool                  `json:"ready"`
+	Templates []TemplateReadiness   `json:"templates"`
+	Layers    []LayerReadiness      `json:"layers"`
+	Mixer     *MixerReadiness       `json:"mixer,omitempty"`
+	Commands  []CommandAvailability `json:"commands"`
+}
+
+// TemplateReadiness is the result of ValidateTemplate and EnquireFileInfo for a template.
+type TemplateReadiness struct {
+	Name          string   `json:"name"`
+	Valid         bool     `json:"valid"`
+	FileExists    bool     `json:"file_exists"`
+	MissingAssets int16    `json:"missing_assets"`
+	Problems      []string `json:"problems,omitempty"`
+}
+
+// LayerReadiness reports whether a graphic layer exists and whether another client holds a lock on it. Session locks
+// this connection holds as well are not counted.
+type LayerReadiness struct {
+	Layer           OxtelLayer `json:"layer"`
+	Exists          bool       `json:"exists"`
+	SessionLocked   bool       `json:"session_locked"`
+	PermanentLocked bool       `json:"permanent_locked"`
+	Problems        []string   `json:"problems,omitempty"`
+}
+
+// MixerReadiness reports whether another client holds a lock on the A/B mixer.
+type MixerReadiness struct {
+	SessionLocked   bool     `json:"session_locked"`
+	PermanentLocked bool     `json:"permanent_locked"`
+	Problems        []string `json:"problems,omitempty"`
+}
+
+// CommandAvailability is the result of EnquireCommandAvailability for a command prefix.
+type CommandAvailability struct {
+	Command   string `json:"command"`
+	Available bool   `json:"available"`
+}
+
+// Preflight checks that the engine is ready for a show: every template exists with all of its assets, every layer
+// exists and is not locked by another client, the mixer is not locked if it is needed and every command is available.
+//
+// Problems found are recorded in the report; an error is only returned if an enquiry fails. Command prefixes that are
+// not two characters long are reported as unavailable without asking the engine.
+func (o *Oxtel) Preflight(check PreflightCheck) (PreflightReport, error) {
+	return o.PreflightContext(context.Background(), check)
+}
+
+// PreflightContext is like Preflight but uses ctx for cancellation and deadlines.
+func (o *Oxtel) PreflightContext(ctx context.Context, check PreflightCheck) (PreflightReport, error) {
+	var report PreflightReport
+
+	for _, name := range check.Templates {
+		t := TemplateReadiness{Name: name}
+
+		validation, err := o.ValidateTemplateContext(ctx, name)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+		info, err := o.EnquireFileInfoContext(ctx, name)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+		t.Valid = validation.FileExists
+		t.FileExists = info.Exists
+		t.MissingAssets = validation.MissingAssets
+
+		if !t.Valid || !t.FileExists {
+			t.Problems = append(t.Problems, "template does not exist")
+		}
+		if t.MissingAssets > 0 {
+			t.Problems = append(t.Problems, fmt.Sprintf("%d missing assets", t.MissingAssets))
+		}
+		report.Templates = append(report.Templates, t)
+	}
+
+	if len(check.Layers) > 0 || check.Mixer {
+		n, err := o.EnquireNumberOfGraphicLayersContext(ctx)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+		own, err := o.EnquireSessionLocksContext(ctx)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+		global, err := o.EnquireGlobalSessionLocksContext(ctx)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+		permanent, err := o.EnquirePermanentLocksContext(ctx)
+		if err != nil {
+			return PreflightReport{}, err
+		}
+
+		for _, layer := range check.Layers {
+			l := LayerReadiness{
+				Layer:           layer,
+				Exists:          int(layer) < n,
+				SessionLocked:   lockedLayer(global, layer) && !lockedLayer(own, layer),
+				PermanentLocked: lockedLayer(permanent, layer),
+			}
+			if !l.Exists {
+				l.Problems = append(l.Problems, fmt.Sprintf("engine has %d graphic layers", n))
+			}
+			l.Problems = append(l.Problems, lockProblems(l.SessionLocked, l.PermanentLocked)...)
+			report.Layers = append(report.Layers, l)
+		}
+
+		if check.Mixer {
+			report.Mixer = &MixerReadiness{
+				SessionLocked:   global.Mixer && !own.Mixer,
+				PermanentLocked: permanent.Mixer,
+			}
+			report.Mixer.Problems = lockProblems(report.Mixer.SessionLocked, report.Mixer.PermanentLocked)
+		}
+	}
+
+	for _, command := range check.Commands {
+		c := CommandAvailability{Command: command}
+		if len(command) == 2 {
+			availability, err := o.EnquireCommandAvailabilityContext(ctx, command[0], command[1])
+			if err != nil {
+				return PreflightReport{}, err
+			}
+			c.Available = availability.Supported
+		}
+		report.Commands = append(report.Commands, c)
+	}
+
+	report.Ready = report.problems() == 0
+	return report, nil
+}
+
+// JSON returns the report as indented JSON.
+func (r PreflightReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// String returns the report as text, one line for each item checked.
+func (r PreflightReport) String() string {
+	var b strings.Builder
+
+	if r.Ready {
+		b.WriteString("READY\n")
+	} else {
+		fmt.Fprintf(&b, "NOT READY: %d problems\n", r.problems())
+	}
+
+	for _, t := range r.Templates {
+		fmt.Fprintf(&b, "template %s: %s\n", t.Name, preflightStatus(t.Problems))
+	}
+	for _, l := range r.Layers {
+		fmt.Fprintf(&b, "layer %d: %s\n", l.Layer, preflightStatus(l.Problems))
+	}
+	if r.Mixer != nil {
+		fmt.Fprintf(&b, "mixer: %s\n", preflightStatus(r.Mixer.Problems))
+	}
+	for _, c := range r.Commands {
+		if c.Available {
+			fmt.Fprintf(&b, "command %s: ok\n", c.Command)
+		} else {
+			fmt.Fprintf(&b, "command %s: not available\n", c.Command)
+		}
+	}
+
+	return b.String()
+}
+
+func (r PreflightReport) problems() int {
+	n := 0
+	for _, t := range r.Templates {
+		n += len(t.Problems)
+	}
+	for _, l := range r.Layers {
+		n += len(l.Problems)
+	}
+	if r.Mixer != nil {
+		n += len(r.Mixer.Problems)
+	}
+	for _, c := range r.Commands {
+		if !c.Available {
+			n++
+		}
+	}
+	return n
+}
+
+func preflightStatus(problems []string) string {
+	if len(problems) == 0 {
+		return "ok"
+	}
+	return strings.Join(problems, ", ")
+}
+
+func lockProblems(session bool, permanent bool) []string {
+	var problems []string
+	if session {
+		problems = append(problems, "session locked by another client")
+	}
+	if permanent {
+		problems = append(problems, "permanently locked")
+	}
+	return problems
+}
+
+func lockedLayer(locks LocksResponse, layer OxtelLayer) bool {
+	switch layer {
+	case OXTEL_LAYER_0:
+		return locks.Layer0
+	case OXTEL_LAYER_1:
+		return locks.Layer1
+	case OXTEL_LAYER_2:
+		return locks.Layer2
+	case OXTEL_LAYER_3:
+		return locks.Layer3
+	case OXTEL_LAYER_4:
+		return locks.Layer4
+	case OXTEL_LAYER_5:
+		return locks.Layer5
+	case OXTEL_LAYER_6:
+		return locks.Layer6
+	case OXTEL_LAYER_7:
+		return locks.Layer7
+	}
+	return false
+}
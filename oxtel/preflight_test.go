@@ -0,0 +1,84 @@
+package oxtel
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPreflight(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+	srv.AddFile("bug.html")
+	srv.SetMissingAssets("bug.html", 2)
+	srv.SetNumberOfGraphicLayers(4)
+	srv.SetPermanentLocks(0x00000001)
+	srv.SetCommandAvailable("R7", false)
+
+	other := NewOxtel(srv.Host(), srv.Port())
+	if err := other.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Disconnect()
+	if err := other.SetSessionLocks(BuildSessionLocks(false, false, true, false, false, false, false, false, false)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetSessionLocks(BuildSessionLocks(false, false, false, true, false, false, false, false, false)); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := client.Preflight(PreflightCheck{
+		Templates: []string{"lower.html", "bug.html", "missing.html"},
+		Layers:    []OxtelLayer{OXTEL_LAYER_1, OXTEL_LAYER_2, OXTEL_LAYER_5},
+		Mixer:     true,
+		Commands:  []string{"R0", "R7"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Ready {
+		t.Error("expected the report not to be ready")
+	}
+	if got := report.Templates[1]; got.MissingAssets != 2 || len(got.Problems) != 1 {
+		t.Errorf("bug.html is %+v", got)
+	}
+	if got := report.Templates[2]; got.Valid || got.FileExists {
+		t.Errorf("missing.html is %+v", got)
+	}
+	if got := report.Layers[0]; !got.SessionLocked || len(got.Problems) != 1 {
+		t.Errorf("layer 1 is %+v", got)
+	}
+	if got := report.Layers[1]; got.SessionLocked || len(got.Problems) != 0 {
+		t.Errorf("layer 2 is %+v", got)
+	}
+	if got := report.Layers[2]; got.Exists {
+		t.Errorf("layer 5 is %+v", got)
+	}
+	if got := report.Mixer; got == nil || !got.PermanentLocked {
+		t.Errorf("mixer is %+v", got)
+	}
+	if !report.Commands[0].Available || report.Commands[1].Available {
+		t.Errorf("commands are %+v", report.Commands)
+	}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded PreflightReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Templates[1].MissingAssets != 2 {
+		t.Errorf("JSON report is %s", data)
+	}
+
+	text := report.String()
+	for _, want := range []string{"NOT READY: 6 problems", "template lower.html: ok", "layer 1: session locked by another client",
+		"mixer: permanently locked", "command R7: not available"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text report does not contain %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// PreloadAndTake preloads a template on a layer, fills in its text fields and, once the engine reports the preload
+// complete with an ImagePreloadTally, loads it on air and waits for the ImageLoadTally.
+//
+// The video tallies are enabled if they are not already, and left enabled. Before anything is sent, the template is
+// checked with ValidateTemplate and a MissingTemplateError is returned if it does not exist. A PreloadReplacedError
+// is returned if another template is preloaded on the layer before the take, in which case the fields may have been
+// lost. A TimeoutError is returned if a tally does not arrive in time.
+func (o *Oxtel) PreloadAndTake(layer OxtelLayer, templateName string, fields map[uint8]string) error {
+	return o.PreloadAndTakeContext(context.Background(), layer, templateName, fields)
+}
+
+// PreloadAndTakeContext is like PreloadAndTake but uses ctx for cancellation and deadlines. When ctx has no deadline,
+// DefaultResponseTimeout applies to each wait for a tally.
+func (o *Oxtel) PreloadAndTakeContext(ctx context.Context, layer OxtelLayer, templateName string, fields map[uint8]string) error {
+	validation, err := o.ValidateTemplateContext(ctx, templateName)
+	if err != nil {
+		return err
+	}
+	if !validation.FileExists {
+		return &MissingTemplateError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Template %s does not exist", templateName),
+			},
+		}
+	}
+
+	if err := o.enableVideoTalliesContext(ctx); err != nil {
+		return err
+	}
+
+	sub := o.Subscribe(SubscriptionOptions{
+		Types:    []OxtelTallyType{OXTEL_TALLY_IMAGE_PRELOAD, OXTEL_TALLY_IMAGE_LOAD},
+		Overflow: OXTEL_OVERFLOW_DROP_OLDEST,
+	})
+	defer sub.Close()
+
+	numbers := make([]uint8, 0, len(fields))
+	for n := range fields {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	cmds := []Command{PreloadImageCommand{Layer: layer, TemplateName: templateName}}
+	for _, n := range numbers {
+		cmds = append(cmds, UpdatePreloadedTextFieldCommand{Layer: layer, Field: n, Text: fields[n]})
+	}
+	if err := o.SendBatchContext(ctx, o.splitText(cmds...)...); err != nil {
+		return err
+	}
+
+	// Preloads by other clients may be reported before ours, but none may follow it until ours is loaded.
+	preloaded := false
+	replaced := func(tally ImagePreloadTally) error {
+		if preloaded && tally.Template != templateName {
+			return &PreloadReplacedError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("Template %s preloaded on layer %d was replaced by %s", templateName, layer, tally.Template),
+				},
+			}
+		}
+		return nil
+	}
+
+	err = o.waitForTally(ctx, sub, fmt.Sprintf("Timed out waiting for %s to preload", templateName), func(tally interface{}) (bool, error) {
+		t, ok := tally.(ImagePreloadTally)
+		if !ok || t.Layer != layer {
+			return false, nil
+		}
+		if t.Template == templateName {
+			preloaded = true
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Anything queued since is checked before taking, so a replaced preload is not taken.
+	for len(sub.C) > 0 {
+		if t, ok := (<-sub.C).(ImagePreloadTally); ok && t.Layer == layer {
+			if err := replaced(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := o.SendContext(ctx, LoadImageCommand{Layer: layer, TemplateName: templateName}); err != nil {
+		return err
+	}
+
+	return o.waitForTally(ctx, sub, fmt.Sprintf("Timed out waiting for %s to load", templateName), func(tally interface{}) (bool, error) {
+		switch t := tally.(type) {
+		case ImagePreloadTally:
+			if t.Layer == layer {
+				return false, replaced(t)
+			}
+		case ImageLoadTally:
+			return t.Layer == layer && t.Template == templateName, nil
+		}
+		return false, nil
+	})
+}
+
+// waitForTally receives tallies from sub until done reports true or an error.
+func (o *Oxtel) waitForTally(ctx context.Context, sub *Subscription, timeout string,
+	done func(tally interface{}) (bool, error)) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultResponseTimeout)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case tally, ok := <-sub.C:
+			if !ok {
+				return &NotConnectedError{
+					BaseError: BaseError{
+						Message: "Disconnected while waiting for a tally",
+					},
+				}
+			}
+			if finished, err := done(tally); finished || err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return contextError(ctx, timeout)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package oxtel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPreloadAndTake(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+
+	if err := client.PreloadAndTake(OXTEL_LAYER_1, "lower.html", map[uint8]string{1: "Jane", 2: "Host"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.LoadedTemplate(1); got != "lower.html" {
+		t.Errorf("layer 1 has %q loaded", got)
+	}
+	if got := srv.TextField(1, 2); got != "Host" {
+		t.Errorf("field 2 on layer 1 is %q", got)
+	}
+
+	err := client.PreloadAndTake(OXTEL_LAYER_2, "missing.html", nil)
+	if _, ok := err.(*MissingTemplateError); !ok {
+		t.Errorf("expected a MissingTemplateError, got %v", err)
+	}
+
+	srv.Handle("R7", func(cmd string) []string { return nil })
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	err = client.PreloadAndTakeContext(ctx, OXTEL_LAYER_2, "lower.html", nil)
+	cancel()
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Errorf("expected a TimeoutError, got %v", err)
+	}
+	srv.Handle("R7", nil)
+
+	// Another client preloads over the template just as it is taken.
+	srv.Handle("R0", func(cmd string) []string { return []string{"YA2other.html"} })
+	err = client.PreloadAndTake(OXTEL_LAYER_2, "lower.html", nil)
+	if _, ok := err.(*PreloadReplacedError); !ok {
+		t.Errorf("expected a PreloadReplacedError, got %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package oxtel
+
+import (
+	"net"
+	"time"
+)
+
+// ReconnectPolicy controls how the connection to the engine is re-established after it is lost.
+//
+// The delay before the first attempt is InitialBackoff. Each failed attempt multiplies the delay by Multiplier,
+// up to MaxBackoff. A MaxAttempts of 0 retries forever.
+type ReconnectPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	MaxAttempts    int
+}
+
+// DefaultReconnectPolicy returns a policy that retries forever, starting at 500ms and backing off to 30s.
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		MaxAttempts:    0,
+	}
+}
+
+// armedState records the per-connection settings that the engine forgets when a connection closes, so that they
+// can be restored after a reconnect.
+type armedState struct {
+	videoTallies        bool
+	playStateTally      bool
+	lockTally           bool
+	externalIOTally     bool
+	audioProfileTallies bool
+	mediaTallies        MediaTallies
+	sessionLocks        int32
+}
+
+// SetReconnectPolicy enables automatic reconnection when the connection to the engine is lost. A nil policy disables
+// it, which is the default: a lost connection disconnects the client and closes the Unsolicited channel.
+//
+// After a reconnect, the tallies enabled with EnableVideoTallies, EnablePlayStateTally, EnableOxtelLockTally,
+// EnableMediaTallies, EnableExternalIOTally and EnableAudioProfileTallies and the locks set with SetSessionLocks are
+// sent again, as the engine releases them when a connection closes.
+//
+// Commands sent while the client is reconnecting fail with a NotConnectedError.
+func (o *Oxtel) SetReconnectPolicy(policy *ReconnectPolicy) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.reconnectPolicy = policy
+}
+
+// OnConnectionStateChange registers a callback that is invoked whenever the connection state changes, and returns a
+// function that unregisters it.
+//
+// The err parameter is set for OXTEL_CONNECTION_STATE_LOST, failed reconnection attempts and
+// OXTEL_CONNECTION_STATE_CLOSED when reconnection gives up. Callbacks are invoked synchronously from the goroutine
+// managing the connection and must not block.
+func (o *Oxtel) OnConnectionStateChange(callback func(state OxtelConnectionState, err error)) (unregister func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	cb := &stateCallback{fn: callback}
+	o.stateCallbacks = append(o.stateCallbacks, cb)
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		for i, c := range o.stateCallbacks {
+			if c == cb {
+				o.stateCallbacks = append(o.stateCallbacks[:i], o.stateCallbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// stateCallback is a callback registered with OnConnectionStateChange. It is held by pointer so that it can be
+// unregistered.
+type stateCallback struct {
+	fn func(state OxtelConnectionState, err error)
+}
+
+func (o *Oxtel) notifyConnectionState(state OxtelConnectionState, err error) {
+	o.mu.Lock()
+	callbacks := make([]*stateCallback, len(o.stateCallbacks))
+	copy(callbacks, o.stateCallbacks)
+	o.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback.fn(state, err)
+	}
+}
+
+func (o *Oxtel) connectionLost(c net.Conn, err error) {
+	o.mu.Lock()
+	policy := o.reconnectPolicy
+	if o.conn == c {
+		o.conn = nil
+	}
+	o.mu.Unlock()
+	_ = c.Close()
+	o.failPending("Connection to the Oxtel engine was lost")
+
+	if policy == nil {
+		o.Disconnect()
+		o.notifyConnectionState(OXTEL_CONNECTION_STATE_CLOSED, err)
+		return
+	}
+
+	o.notifyConnectionState(OXTEL_CONNECTION_STATE_LOST, err)
+	o.reconnect(*policy)
+}
+
+func (o *Oxtel) reconnect(policy ReconnectPolicy) {
+	backoff := policy.InitialBackoff
+	var lastErr error
+
+	for attempt := 1; policy.MaxAttempts == 0 || attempt <= policy.MaxAttempts; attempt++ {
+		o.notifyConnectionState(OXTEL_CONNECTION_STATE_RECONNECTING, lastErr)
+
+		select {
+		case <-o.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = time.Duration(float64(backoff) * policy.Multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+
+		c, err := o.dial()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		o.mu.Lock()
+		if o.ctx.Err() != nil {
+			o.mu.Unlock()
+			_ = c.Close()
+			return
+		}
+		o.conn = c
+		o.mu.Unlock()
+		o.timing.clear()
+
+		go o.rxLoop(c)
+
+		if err := o.rearm(); err != nil {
+			// The new rxLoop notices the broken connection and starts over.
+			return
+		}
+
+		o.notifyConnectionState(OXTEL_CONNECTION_STATE_CONNECTED, nil)
+		return
+	}
+
+	o.Disconnect()
+	o.notifyConnectionState(OXTEL_CONNECTION_STATE_CLOSED, lastErr)
+}
+
+// rearm restores the tallies and session locks that were active on the previous connection.
+func (o *Oxtel) rearm() error {
+	o.mu.Lock()
+	armed := o.armed
+	o.mu.Unlock()
+
+	var cmds []string
+	if armed.videoTallies {
+		cmds = append(cmds, EnableVideoTallies_AsString(true))
+	}
+	if armed.playStateTally {
+		cmds = append(cmds, EnablePlayStateTally_AsString(true))
+	}
+	if armed.lockTally {
+		cmds = append(cmds, EnableOxtelLockTally_AsString(true))
+	}
+	if armed.mediaTallies != (MediaTallies{}) {
+		cmds = append(cmds, EnableMediaTallies_AsString(armed.mediaTallies))
+	}
+	if armed.externalIOTally {
+		cmds = append(cmds, EnableExternalIOTally_AsString(true))
+	}
+	if armed.audioProfileTallies {
+		cmds = append(cmds, EnableAudioProfileTallies_AsString(true))
+	}
+	if armed.sessionLocks != 0 {
+		cmds = append(cmds, SetSessionLocks_AsString(armed.sessionLocks))
+	}
+
+	for _, cmd := range cmds {
+		if err := o.sendCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordArmedState remembers the tallies and locks set by cmd so that rearm can restore them.
+func (o *Oxtel) recordArmedState(cmd Command) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	switch c := cmd.(type) {
+	case EnableVideoTalliesCommand:
+		o.armed.videoTallies = c.Enable
+	case EnablePlayStateTallyCommand:
+		o.armed.playStateTally = c.Enable
+	case EnableOxtelLockTallyCommand:
+		o.armed.lockTally = c.Enable
+	case EnableMediaTalliesCommand:
+		o.armed.mediaTallies = c.Data
+	case EnableExternalIOTallyCommand:
+		o.armed.externalIOTally = c.Enable
+	case EnableAudioProfileTalliesCommand:
+		o.armed.audioProfileTallies = c.Enable
+	case SetSessionLocksCommand:
+		o.armed.sessionLocks = c.Locks
+	}
+}
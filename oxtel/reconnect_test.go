@@ -0,0 +1,139 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func waitForConnectionState(t *testing.T, states chan OxtelConnectionState, want OxtelConnectionState) {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for connection state %d", want)
+		}
+	}
+}
+
+func TestReconnectRearmsTalliesAndLocks(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	client.SetReconnectPolicy(&ReconnectPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     2,
+	})
+
+	states := make(chan OxtelConnectionState, 16)
+	client.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		states <- state
+	})
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+	waitForConnectionState(t, states, OXTEL_CONNECTION_STATE_CONNECTED)
+
+	locks := BuildSessionLocks(true, true, false, false, false, false, false, false, false)
+	if err := client.EnableVideoTallies(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetSessionLocks(locks); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireSessionLocks(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.CloseClientConnections()
+	waitForConnectionState(t, states, OXTEL_CONNECTION_STATE_LOST)
+	srv.ClearCommands()
+	waitForConnectionState(t, states, OXTEL_CONNECTION_STATE_CONNECTED)
+
+	enabled, err := client.EnquireVideoTallies()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enabled {
+		t.Fatal("video tallies were not re-enabled after reconnect")
+	}
+	if srv.GlobalSessionLocks() != uint32(locks) {
+		t.Fatalf("session locks were not restored after reconnect: %08x", srv.GlobalSessionLocks())
+	}
+}
+
+func TestReconnectGivesUp(t *testing.T) {
+	srv := oxteltest.NewServer()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	client.SetReconnectPolicy(&ReconnectPolicy{
+		InitialBackoff: time.Millisecond,
+		Multiplier:     1,
+		MaxAttempts:    3,
+	})
+
+	states := make(chan OxtelConnectionState, 16)
+	client.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		states <- state
+	})
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	waitForConnectionState(t, states, OXTEL_CONNECTION_STATE_CLOSED)
+
+	if err := client.CutAB(); err == nil {
+		t.Fatal("expected an error sending on a closed client")
+	}
+}
+
+func TestOnConnectionStateChangeUnregister(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+
+	kept := make(chan OxtelConnectionState, 16)
+	removed := make(chan OxtelConnectionState, 16)
+	client.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		kept <- state
+	})
+	unregister := client.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		removed <- state
+	})
+	unregister()
+	unregister()
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	waitForConnectionState(t, kept, OXTEL_CONNECTION_STATE_CONNECTED)
+	select {
+	case state := <-removed:
+		t.Fatalf("unregistered callback was invoked with state %d", state)
+	default:
+	}
+}
+
+// stateCallbackCount returns the number of callbacks registered with OnConnectionStateChange.
+func stateCallbackCount(o *Oxtel) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.stateCallbacks)
+}
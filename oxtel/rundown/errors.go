@@ -0,0 +1,41 @@
+package rundown
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryansavara/go-oxtel/oxtel"
+)
+
+type InvalidRundownError struct {
+	oxtel.BaseError
+}
+
+type UnknownEventError struct {
+	oxtel.BaseError
+}
+
+type EventStateError struct {
+	oxtel.BaseError
+}
+
+func invalidRundown(format string, a ...interface{}) error {
+	return &InvalidRundownError{
+		BaseError: oxtel.BaseError{
+			Message: fmt.Sprintf(format, a...),
+		},
+	}
+}
+
+func eventStateError(format string, a ...interface{}) error {
+	return &EventStateError{
+		BaseError: oxtel.BaseError{
+			Message: fmt.Sprintf(format, a...),
+		},
+	}
+}
+
+// message returns the text of err without the prefix added by oxtel.BaseError.
+func message(err error) string {
+	return strings.TrimPrefix(err.Error(), "Error: ")
+}
@@ -0,0 +1,608 @@
+package rundown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel"
+)
+
+// EventStatus is the progress of an event through the Executor.
+type EventStatus uint8
+
+const (
+	RUNDOWN_EVENT_PENDING   EventStatus = 0
+	RUNDOWN_EVENT_SCHEDULED EventStatus = 1
+	RUNDOWN_EVENT_PRELOADED EventStatus = 2
+	RUNDOWN_EVENT_LOADED    EventStatus = 3
+	RUNDOWN_EVENT_DONE      EventStatus = 4
+	RUNDOWN_EVENT_HELD      EventStatus = 5
+	RUNDOWN_EVENT_SKIPPED   EventStatus = 6
+	RUNDOWN_EVENT_MISSED    EventStatus = 7
+	RUNDOWN_EVENT_FAILED    EventStatus = 8
+)
+
+// DefaultPreroll is how long before an event its template is preloaded if Options does not set Preroll.
+const DefaultPreroll = 2 * time.Second
+
+// missedAfter is how far ahead an event can be before it is taken to be in the past instead.
+const missedAfter = 12 * time.Hour
+
+// Options configures an Executor.
+type Options struct {
+	// Preroll is how long before an event its template is preloaded. DefaultPreroll is used if it is zero.
+	Preroll time.Duration
+
+	// Ledger records the commands scheduled by the Executor. If it is nil, the ledger already recording the commands
+	// scheduled through the Oxtel is used, and a ledger without a file is created only if there is none.
+	Ledger *oxtel.ScheduleLedger
+}
+
+// EventState is an event and its progress.
+type EventState struct {
+	Event  Event
+	Status EventStatus
+
+	// Err is set when Status is RUNDOWN_EVENT_FAILED.
+	Err error
+}
+
+// Executor schedules the events of a rundown on the engine and follows them on air.
+//
+// Events are scheduled with a ScheduleLedger so that a single event can be held or skipped while the rundown runs.
+// Their progress is reported from the image preload, image load and keyer position tallies: an event is
+// RUNDOWN_EVENT_PRELOADED once its template is preloaded, RUNDOWN_EVENT_LOADED once it is loaded and
+// RUNDOWN_EVENT_DONE once the keyer has finished its move, or once the template is loaded if the event does not move
+// the keyer. Events that neither load a template nor move a keyer stay RUNDOWN_EVENT_SCHEDULED.
+type Executor struct {
+	o       *oxtel.Oxtel
+	ledger  *oxtel.ScheduleLedger
+	preroll time.Duration
+
+	// opMu serialises the operations that change the schedule on the engine.
+	opMu sync.Mutex
+
+	mu        sync.Mutex
+	events    []*event
+	nextID    int
+	callbacks []func(state EventState)
+	sub       *oxtel.Subscription
+}
+
+type event struct {
+	EventState
+	ids []uint64
+}
+
+// NewExecutor returns an Executor for the events. Nothing is scheduled until Start is called.
+func NewExecutor(o *oxtel.Oxtel, events []Event, opts Options) (*Executor, error) {
+	e := &Executor{
+		o:       o,
+		ledger:  opts.Ledger,
+		preroll: opts.Preroll,
+	}
+	if e.preroll == 0 {
+		e.preroll = DefaultPreroll
+	}
+	if e.ledger == nil {
+		e.ledger = o.ScheduleLedger()
+	}
+	if e.ledger == nil {
+		var err error
+		if e.ledger, err = oxtel.NewScheduleLedger(o, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ev := range events {
+		if _, err := e.add(ev); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// OnStatus registers a callback that is invoked whenever the status of an event changes.
+//
+// Callbacks are invoked synchronously, some of them from the goroutine following tallies. Tallies are queued for that
+// goroutine, so a slow callback delays later statuses but not the engine connection. Callbacks must not call the
+// methods of the Executor that talk to the engine.
+func (e *Executor) OnStatus(callback func(state EventState)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.callbacks = append(e.callbacks, callback)
+}
+
+// Events returns every event and its status, in the order they were added.
+func (e *Executor) Events() []EventState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	states := make([]EventState, len(e.events))
+	for i, ev := range e.events {
+		states[i] = ev.EventState
+	}
+	return states
+}
+
+// Status returns the state of a single event.
+func (e *Executor) Status(id string) (EventState, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ev, err := e.find(id)
+	if err != nil {
+		return EventState{}, err
+	}
+	return ev.EventState, nil
+}
+
+// Start enables the video tallies and schedules every pending event. An event due more than twelve hours ahead is
+// taken to have passed and is RUNDOWN_EVENT_MISSED.
+func (e *Executor) Start() error {
+	return e.StartContext(context.Background())
+}
+
+// StartContext is like Start but uses ctx for cancellation and deadlines.
+func (e *Executor) StartContext(ctx context.Context) error {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	e.mu.Lock()
+	running := e.sub != nil
+	e.mu.Unlock()
+	if running {
+		return eventStateError("The rundown is already running")
+	}
+
+	if err := e.o.EnableVideoTalliesContext(ctx, true); err != nil {
+		return err
+	}
+	sub := e.o.Subscribe(oxtel.SubscriptionOptions{
+		Types: []oxtel.OxtelTallyType{
+			oxtel.OXTEL_TALLY_IMAGE_PRELOAD,
+			oxtel.OXTEL_TALLY_IMAGE_LOAD,
+			oxtel.OXTEL_TALLY_KEYER_POSITION,
+		},
+	})
+	e.mu.Lock()
+	e.sub = sub
+	e.mu.Unlock()
+	go e.run(sub)
+
+	now, err := e.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	var pending []*event
+	for _, ev := range e.events {
+		if ev.Status == RUNDOWN_EVENT_PENDING {
+			pending = append(pending, ev)
+		}
+	}
+	e.mu.Unlock()
+
+	var firstErr error
+	for _, ev := range pending {
+		if err := e.schedule(ctx, ev, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Stop cancels the scheduled commands of every event that has not been taken, returning it to
+// RUNDOWN_EVENT_PENDING, and stops following tallies.
+func (e *Executor) Stop() error {
+	return e.StopContext(context.Background())
+}
+
+// StopContext is like Stop but uses ctx for cancellation and deadlines.
+func (e *Executor) StopContext(ctx context.Context) error {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	e.mu.Lock()
+	var stopped []*event
+	for _, ev := range e.events {
+		if ev.Status == RUNDOWN_EVENT_SCHEDULED || ev.Status == RUNDOWN_EVENT_PRELOADED {
+			stopped = append(stopped, ev)
+		}
+	}
+	sub := e.sub
+	e.sub = nil
+	e.mu.Unlock()
+
+	if sub != nil {
+		sub.Close()
+	}
+	return e.cancel(ctx, stopped, RUNDOWN_EVENT_PENDING)
+}
+
+// Hold stops an event that has not been taken from being taken. It can be scheduled again with Release or taken
+// immediately with Take.
+func (e *Executor) Hold(id string) error {
+	return e.HoldContext(context.Background(), id)
+}
+
+// HoldContext is like Hold but uses ctx for cancellation and deadlines.
+func (e *Executor) HoldContext(ctx context.Context, id string) error {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	ev, err := e.lookup(id, RUNDOWN_EVENT_SCHEDULED, RUNDOWN_EVENT_PRELOADED)
+	if err != nil {
+		return err
+	}
+	return e.cancel(ctx, []*event{ev}, RUNDOWN_EVENT_HELD)
+}
+
+// Release schedules a held event again at the given time.
+func (e *Executor) Release(id string, tc oxtel.Timecode) error {
+	return e.ReleaseContext(context.Background(), id, tc)
+}
+
+// ReleaseContext is like Release but uses ctx for cancellation and deadlines.
+func (e *Executor) ReleaseContext(ctx context.Context, id string, tc oxtel.Timecode) error {
+	if err := tc.Validate(); err != nil {
+		return err
+	}
+
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	ev, err := e.lookup(id, RUNDOWN_EVENT_HELD)
+	if err != nil {
+		return err
+	}
+	now, err := e.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	ev.Event.Time = tc
+	e.mu.Unlock()
+	return e.schedule(ctx, ev, now)
+}
+
+// Take preloads and takes a held event immediately.
+func (e *Executor) Take(id string) error {
+	return e.TakeContext(context.Background(), id)
+}
+
+// TakeContext is like Take but uses ctx for cancellation and deadlines.
+func (e *Executor) TakeContext(ctx context.Context, id string) error {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	ev, err := e.lookup(id, RUNDOWN_EVENT_HELD)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	preload, take := ev.Event.commands()
+	e.mu.Unlock()
+
+	e.setStatus(ev, RUNDOWN_EVENT_SCHEDULED, nil)
+	if err := e.o.SendBatchContext(ctx, append(preload, take...)...); err != nil {
+		e.setStatus(ev, RUNDOWN_EVENT_FAILED, err)
+		return err
+	}
+	return nil
+}
+
+// Skip stops an event that has not been taken from ever being taken.
+func (e *Executor) Skip(id string) error {
+	return e.SkipContext(context.Background(), id)
+}
+
+// SkipContext is like Skip but uses ctx for cancellation and deadlines.
+func (e *Executor) SkipContext(ctx context.Context, id string) error {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	ev, err := e.lookup(id, RUNDOWN_EVENT_PENDING, RUNDOWN_EVENT_SCHEDULED, RUNDOWN_EVENT_PRELOADED, RUNDOWN_EVENT_HELD)
+	if err != nil {
+		return err
+	}
+	return e.cancel(ctx, []*event{ev}, RUNDOWN_EVENT_SKIPPED)
+}
+
+// Insert adds an event to the rundown, scheduling it if the rundown is running, and returns its ID.
+func (e *Executor) Insert(ev Event) (string, error) {
+	return e.InsertContext(context.Background(), ev)
+}
+
+// InsertContext is like Insert but uses ctx for cancellation and deadlines.
+func (e *Executor) InsertContext(ctx context.Context, ev Event) (string, error) {
+	e.opMu.Lock()
+	defer e.opMu.Unlock()
+
+	added, err := e.add(ev)
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.Lock()
+	running := e.sub != nil
+	e.mu.Unlock()
+	if !running {
+		return added.Event.ID, nil
+	}
+
+	now, err := e.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return added.Event.ID, err
+	}
+	return added.Event.ID, e.schedule(ctx, added, now)
+}
+
+// add validates an event, gives it an ID if it has none and appends it to the rundown.
+func (e *Executor) add(ev Event) (*event, error) {
+	if err := ev.Validate(); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if ev.ID == "" {
+		for {
+			e.nextID++
+			ev.ID = fmt.Sprint(e.nextID)
+			if _, err := e.find(ev.ID); err != nil {
+				break
+			}
+		}
+	} else if _, err := e.find(ev.ID); err == nil {
+		return nil, invalidRundown("Event %s is already in the rundown", ev.ID)
+	}
+
+	added := &event{EventState: EventState{Event: ev}}
+	e.events = append(e.events, added)
+	return added, nil
+}
+
+// schedule adds the commands of an event to the ledger. The preload is sent immediately if its time has passed.
+func (e *Executor) schedule(ctx context.Context, ev *event, now oxtel.Timecode) error {
+	e.mu.Lock()
+	tc := ev.Event.Time
+	preload, take := ev.Event.commands()
+	e.mu.Unlock()
+
+	if tc.FieldRate == 0 {
+		tc.FieldRate, tc.Progressive = now.FieldRate, now.Progressive
+	}
+	if now.Until(tc) > missedAfter {
+		e.setStatus(ev, RUNDOWN_EVENT_MISSED, nil)
+		return nil
+	}
+
+	e.setStatus(ev, RUNDOWN_EVENT_SCHEDULED, nil)
+
+	var ids []uint64
+	if len(preload) > 0 {
+		if at := tc.Add(-e.preroll); now.Until(at) > now.Until(tc) {
+			if err := e.o.SendBatchContext(ctx, preload...); err != nil {
+				e.setStatus(ev, RUNDOWN_EVENT_FAILED, err)
+				return err
+			}
+		} else {
+			preloadIDs, err := e.ledger.AddBatchContext(ctx, at, preload...)
+			if err != nil {
+				e.setStatus(ev, RUNDOWN_EVENT_FAILED, err)
+				return err
+			}
+			ids = append(ids, preloadIDs...)
+		}
+	}
+
+	takeIDs, err := e.ledger.AddBatchContext(ctx, tc, take...)
+	if err != nil {
+		_ = e.ledger.CancelContext(ctx, ids...)
+		e.setStatus(ev, RUNDOWN_EVENT_FAILED, err)
+		return err
+	}
+
+	e.mu.Lock()
+	ev.ids = append(ids, takeIDs...)
+	e.mu.Unlock()
+	return nil
+}
+
+// cancel removes the commands of the events still waiting in the scheduler and sets their status.
+func (e *Executor) cancel(ctx context.Context, events []*event, status EventStatus) error {
+	entries, err := e.ledger.ListContext(ctx)
+	if err != nil {
+		return err
+	}
+	waiting := make(map[uint64]bool, len(entries))
+	for _, entry := range entries {
+		waiting[entry.ID] = true
+	}
+
+	var ids []uint64
+	e.mu.Lock()
+	for _, ev := range events {
+		for _, id := range ev.ids {
+			if waiting[id] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	if err := e.ledger.CancelContext(ctx, ids...); err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		e.mu.Lock()
+		ev.ids = nil
+		e.mu.Unlock()
+		e.setStatus(ev, status, nil)
+	}
+	return nil
+}
+
+// lookup returns an event, if its status is one of statuses.
+func (e *Executor) lookup(id string, statuses ...EventStatus) (*event, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ev, err := e.find(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if ev.Status == status {
+			return ev, nil
+		}
+	}
+	return nil, eventStateError("Event %s cannot be changed while its status is %d", id, ev.Status)
+}
+
+// find returns an event by ID. e.mu must be held.
+func (e *Executor) find(id string) (*event, error) {
+	for _, ev := range e.events {
+		if ev.Event.ID == id {
+			return ev, nil
+		}
+	}
+	return nil, &UnknownEventError{
+		BaseError: oxtel.BaseError{
+			Message: fmt.Sprintf("No event %s in the rundown", id),
+		},
+	}
+}
+
+func (e *Executor) setStatus(ev *event, status EventStatus, err error) {
+	e.mu.Lock()
+	ev.Status, ev.Err = status, err
+	state, callbacks := ev.EventState, e.callbacksLocked()
+	e.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(state)
+	}
+}
+
+func (e *Executor) callbacksLocked() []func(EventState) {
+	callbacks := make([]func(EventState), len(e.callbacks))
+	copy(callbacks, e.callbacks)
+	return callbacks
+}
+
+// run queues the tallies of sub for follow, so that the subscription never waits on an OnStatus callback. Following
+// stops once the subscription is closed.
+func (e *Executor) run(sub *oxtel.Subscription) {
+	var (
+		mu    sync.Mutex
+		queue []interface{}
+	)
+	wake := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-wake:
+			}
+
+			for {
+				mu.Lock()
+				if len(queue) == 0 {
+					mu.Unlock()
+					break
+				}
+				tally := queue[0]
+				queue = queue[1:]
+				mu.Unlock()
+
+				e.follow(tally)
+			}
+		}
+	}()
+
+	for tally := range sub.C {
+		mu.Lock()
+		queue = append(queue, tally)
+		mu.Unlock()
+
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// follow updates the status of the event a tally reports on.
+func (e *Executor) follow(tally interface{}) {
+	e.mu.Lock()
+	ev, status := e.match(tally)
+	e.mu.Unlock()
+
+	if ev != nil {
+		e.setStatus(ev, status, nil)
+	}
+}
+
+// match returns the event a tally reports on and its new status. e.mu must be held.
+func (e *Executor) match(tally interface{}) (*event, EventStatus) {
+	switch t := tally.(type) {
+	case oxtel.ImagePreloadTally:
+		ev := e.first(t.Layer, func(ev *event) bool {
+			return ev.Status == RUNDOWN_EVENT_SCHEDULED && ev.Event.Template == t.Template
+		})
+		return ev, RUNDOWN_EVENT_PRELOADED
+	case oxtel.ImageLoadTally:
+		ev := e.first(t.Layer, func(ev *event) bool {
+			return (ev.Status == RUNDOWN_EVENT_SCHEDULED || ev.Status == RUNDOWN_EVENT_PRELOADED) &&
+				ev.Event.Template != "" && ev.Event.Template == t.Template
+		})
+		if ev != nil && ev.Event.Keyer != nil {
+			return ev, RUNDOWN_EVENT_LOADED
+		}
+		return ev, RUNDOWN_EVENT_DONE
+	case oxtel.KeyerPositionTally:
+		position := oxtel.OxtelKeyerPositionTally(t.Direction)
+		if position == oxtel.OXTEL_KEYER_TALLY_IN_TRANSITION {
+			return nil, 0
+		}
+		ev := e.first(t.Layer, func(ev *event) bool {
+			if ev.Event.Keyer == nil {
+				return false
+			}
+			if *ev.Event.Keyer != oxtel.OXTEL_DIR_TOGGLE && *ev.Event.Keyer != t.Direction {
+				return false
+			}
+			if ev.Event.Template != "" {
+				return ev.Status == RUNDOWN_EVENT_LOADED
+			}
+			return ev.Status == RUNDOWN_EVENT_SCHEDULED
+		})
+		return ev, RUNDOWN_EVENT_DONE
+	}
+
+	return nil, 0
+}
+
+// first returns the first event on a layer that matches. e.mu must be held.
+func (e *Executor) first(layer oxtel.OxtelLayer, matches func(ev *event) bool) *event {
+	for _, ev := range e.events {
+		if ev.Event.Layer == layer && matches(ev) {
+			return ev
+		}
+	}
+	return nil
+}
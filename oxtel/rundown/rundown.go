@@ -0,0 +1,307 @@
+// Package rundown runs a list of timed graphics events on a Spectrum engine through the Oxtel scheduler.
+package rundown
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryansavara/go-oxtel/oxtel"
+)
+
+// MixerTake is the A/B mixer transition made by an event.
+type MixerTake uint8
+
+const (
+	RUNDOWN_MIXER_NONE MixerTake = 0
+	RUNDOWN_MIXER_CUT  MixerTake = 1
+	RUNDOWN_MIXER_FADE MixerTake = 2
+)
+
+// Event is a graphics event in a rundown. At Time, the template is loaded onto the layer, the keyer is faded and the
+// mixer takes. The template is preloaded, with its text fields filled in, a preroll earlier.
+type Event struct {
+	// ID identifies the event to the Executor. Events without one are numbered from 1 in the order they are added.
+	ID   string
+	Time oxtel.Timecode
+
+	Layer    oxtel.OxtelLayer
+	Template string
+
+	// Fields are the text fields of the template, by field number. Without a template they update the template
+	// already loaded on the layer.
+	Fields map[uint8]string
+
+	// Keyer fades the keyer of the layer in the given direction, at KeyerRate if it is set.
+	Keyer     *oxtel.OxtelDirection
+	KeyerRate *uint16
+
+	Mixer         MixerTake
+	MixerDuration uint16
+}
+
+// jsonEvent is the format of an event in a JSON rundown.
+type jsonEvent struct {
+	ID            string            `json:"id"`
+	Time          string            `json:"time"`
+	Layer         uint8             `json:"layer"`
+	Template      string            `json:"template"`
+	Fields        map[string]string `json:"fields"`
+	Keyer         string            `json:"keyer"`
+	KeyerRate     *uint16           `json:"keyer_rate"`
+	Mixer         string            `json:"mixer"`
+	MixerDuration uint16            `json:"mixer_duration"`
+}
+
+// Load reads a rundown from a .json or .csv file.
+func Load(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadJSON(f)
+	case ".csv":
+		return ReadCSV(f)
+	}
+
+	return nil, invalidRundown("Rundown %s is neither a .json nor a .csv file", path)
+}
+
+// ReadJSON reads a JSON rundown: an array of events, or an object with the array in "events". Each event has a
+// "time" formatted as HH:MM:SS:FF and any of "id", "layer", "template", "fields" (an object from field number to
+// text), "keyer" ("up", "down" or "toggle"), "keyer_rate", "mixer" ("cut" or "fade") and "mixer_duration".
+func ReadJSON(r io.Reader) ([]Event, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []jsonEvent
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		var doc struct {
+			Events []jsonEvent `json:"events"`
+		}
+		err = json.Unmarshal(data, &doc)
+		raw = doc.Events
+	}
+	if err != nil {
+		return nil, invalidRundown("Rundown is not valid JSON: %v", err)
+	}
+
+	events := make([]Event, 0, len(raw))
+	for i, j := range raw {
+		ev, err := newEvent(j.ID, j.Time, j.Layer, j.Template, j.Fields, j.Keyer, j.KeyerRate, j.Mixer, j.MixerDuration)
+		if err != nil {
+			return nil, invalidRundown("Event %d: %s", i+1, message(err))
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// ReadCSV reads a CSV rundown. The first row names the columns: time, and any of id, layer, template, keyer,
+// keyer_rate, mixer, mixer_duration and field1, field2 and so on for the text fields. Empty cells are left unset.
+func ReadCSV(r io.Reader) ([]Event, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, invalidRundown("Rundown is not valid CSV: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	for _, column := range header {
+		switch column {
+		case "id", "time", "layer", "template", "keyer", "keyer_rate", "mixer", "mixer_duration":
+		default:
+			if _, ok := fieldColumn(column); !ok {
+				return nil, invalidRundown("Unknown rundown column %q", column)
+			}
+		}
+	}
+
+	events := make([]Event, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		cells := make(map[string]string, len(header))
+		fields := make(map[string]string)
+		for c, column := range header {
+			if row[c] == "" {
+				continue
+			}
+			if n, ok := fieldColumn(column); ok {
+				fields[strconv.Itoa(int(n))] = row[c]
+			} else {
+				cells[column] = row[c]
+			}
+		}
+
+		ev, err := parseCSVEvent(cells, fields)
+		if err != nil {
+			return nil, invalidRundown("Row %d: %s", i+2, message(err))
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func parseCSVEvent(cells map[string]string, fields map[string]string) (Event, error) {
+	var layer uint64
+	var err error
+	if s, ok := cells["layer"]; ok {
+		if layer, err = strconv.ParseUint(s, 10, 8); err != nil {
+			return Event{}, fmt.Errorf("layer %q is not a number", s)
+		}
+	}
+
+	var keyerRate *uint16
+	if s, ok := cells["keyer_rate"]; ok {
+		rate, err := strconv.ParseUint(s, 10, 16)
+		if err != nil {
+			return Event{}, fmt.Errorf("keyer_rate %q is not a number", s)
+		}
+		r := uint16(rate)
+		keyerRate = &r
+	}
+
+	var mixerDuration uint64
+	if s, ok := cells["mixer_duration"]; ok {
+		if mixerDuration, err = strconv.ParseUint(s, 10, 16); err != nil {
+			return Event{}, fmt.Errorf("mixer_duration %q is not a number", s)
+		}
+	}
+
+	return newEvent(cells["id"], cells["time"], uint8(layer), cells["template"], fields, cells["keyer"], keyerRate,
+		cells["mixer"], uint16(mixerDuration))
+}
+
+func fieldColumn(column string) (uint8, bool) {
+	if !strings.HasPrefix(column, "field") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(column[len("field"):], 10, 8)
+	return uint8(n), err == nil
+}
+
+func newEvent(id string, tc string, layer uint8, template string, fields map[string]string, keyer string,
+	keyerRate *uint16, mixer string, mixerDuration uint16) (Event, error) {
+	ev := Event{
+		ID:            id,
+		Layer:         oxtel.OxtelLayer(layer),
+		Template:      template,
+		KeyerRate:     keyerRate,
+		MixerDuration: mixerDuration,
+	}
+
+	var err error
+	if ev.Time, err = oxtel.ParseTimecode(tc, 0, false); err != nil {
+		return Event{}, err
+	}
+
+	if len(fields) > 0 {
+		ev.Fields = make(map[uint8]string, len(fields))
+		for k, v := range fields {
+			n, err := strconv.ParseUint(k, 10, 8)
+			if err != nil {
+				return Event{}, fmt.Errorf("field %q is not a number", k)
+			}
+			ev.Fields[uint8(n)] = v
+		}
+	}
+
+	switch strings.ToLower(keyer) {
+	case "":
+	case "up":
+		ev.Keyer = direction(oxtel.OXTEL_DIR_UP)
+	case "down":
+		ev.Keyer = direction(oxtel.OXTEL_DIR_DOWN)
+	case "toggle":
+		ev.Keyer = direction(oxtel.OXTEL_DIR_TOGGLE)
+	default:
+		return Event{}, fmt.Errorf("keyer %q is not up, down or toggle", keyer)
+	}
+
+	switch strings.ToLower(mixer) {
+	case "":
+	case "cut":
+		ev.Mixer = RUNDOWN_MIXER_CUT
+	case "fade":
+		ev.Mixer = RUNDOWN_MIXER_FADE
+	default:
+		return Event{}, fmt.Errorf("mixer %q is not cut or fade", mixer)
+	}
+
+	return ev, ev.Validate()
+}
+
+func direction(d oxtel.OxtelDirection) *oxtel.OxtelDirection {
+	return &d
+}
+
+// Validate reports whether the event does anything and every command it sends is valid.
+func (ev Event) Validate() error {
+	if ev.Template == "" && len(ev.Fields) == 0 && ev.Keyer == nil && ev.Mixer == RUNDOWN_MIXER_NONE {
+		return invalidRundown("Event %s at %s does nothing", ev.ID, ev.Time)
+	}
+	if ev.Layer > oxtel.OXTEL_LAYER_7 {
+		return invalidRundown("Event %s at %s is on layer %d", ev.ID, ev.Time, ev.Layer)
+	}
+	if err := ev.Time.Validate(); err != nil {
+		return err
+	}
+
+	preload, take := ev.commands()
+	for _, cmd := range append(preload, take...) {
+		if err := cmd.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commands returns the commands sent a preroll before the event and at the event.
+func (ev Event) commands() (preload []oxtel.Command, take []oxtel.Command) {
+	fields := make([]uint8, 0, len(ev.Fields))
+	for n := range ev.Fields {
+		fields = append(fields, n)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i] < fields[j] })
+
+	if ev.Template != "" {
+		preload = append(preload, oxtel.PreloadImageCommand{Layer: ev.Layer, TemplateName: ev.Template})
+		for _, n := range fields {
+			preload = append(preload, oxtel.UpdatePreloadedTextFieldCommand{Layer: ev.Layer, Field: n, Text: ev.Fields[n]})
+		}
+		take = append(take, oxtel.LoadImageCommand{Layer: ev.Layer, TemplateName: ev.Template})
+	} else {
+		for _, n := range fields {
+			take = append(take, oxtel.UpdateTextFieldCommand{Layer: ev.Layer, Field: n, Text: ev.Fields[n]})
+		}
+	}
+
+	if ev.Keyer != nil {
+		take = append(take, oxtel.FadeKeyerCommand{Layer: ev.Layer, Direction: *ev.Keyer, Rate: ev.KeyerRate})
+	}
+
+	switch ev.Mixer {
+	case RUNDOWN_MIXER_CUT:
+		take = append(take, oxtel.CutABCommand{})
+	case RUNDOWN_MIXER_FADE:
+		take = append(take, oxtel.FadeABCommand{Duration: ev.MixerDuration})
+	}
+
+	return preload, take
+}
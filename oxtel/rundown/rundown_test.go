@@ -0,0 +1,262 @@
+package rundown
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel"
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+const jsonRundown = `{"events": [
+	{"id": "open", "time": "10:00:10:00", "layer": 1, "template": "lower.html", "fields": {"1": "Jane", "2": "Host"}, "keyer": "up", "keyer_rate": 5},
+	{"time": "10:00:12:00", "layer": 2, "template": "bug.html", "mixer": "fade", "mixer_duration": 25}
+]}`
+
+const csvRundown = `id,time,layer,template,field1,field2,keyer,keyer_rate,mixer,mixer_duration
+open,10:00:10:00,1,lower.html,Jane,Host,up,5,,
+,10:00:12:00,2,bug.html,,,,,fade,25
+`
+
+func TestReadRundown(t *testing.T) {
+	fromJSON, err := ReadJSON(strings.NewReader(jsonRundown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := ReadCSV(strings.NewReader(csvRundown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromJSON, fromCSV) {
+		t.Fatalf("JSON rundown %#v differs from CSV rundown %#v", fromJSON, fromCSV)
+	}
+
+	rate := uint16(5)
+	want := Event{
+		ID:        "open",
+		Time:      oxtel.Timecode{Hours: 10, Seconds: 10},
+		Layer:     oxtel.OXTEL_LAYER_1,
+		Template:  "lower.html",
+		Fields:    map[uint8]string{1: "Jane", 2: "Host"},
+		Keyer:     direction(oxtel.OXTEL_DIR_UP),
+		KeyerRate: &rate,
+	}
+	if !reflect.DeepEqual(fromJSON[0], want) {
+		t.Fatalf("got %#v, want %#v", fromJSON[0], want)
+	}
+
+	for _, bad := range []string{
+		`[{"time": "10:00"}]`,
+		`[{"time": "10:00:00:00"}]`,
+		`[{"time": "10:00:00:00", "layer": 8, "template": "a.html"}]`,
+		`[{"time": "10:00:00:00", "keyer": "sideways"}]`,
+		`[{"time": "10:00:00:00", "fields": {"one": "a"}}]`,
+	} {
+		if _, err := ReadJSON(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		} else if _, ok := err.(*InvalidRundownError); !ok {
+			t.Errorf("%s: expected an InvalidRundownError, got %T", bad, err)
+		}
+	}
+	if _, err := ReadCSV(strings.NewReader("time,colour\n")); err == nil {
+		t.Error("expected an unknown column to be rejected")
+	}
+}
+
+func TestExecutor(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	for _, name := range []string{"lower.html", "bug.html", "held.html", "skipped.html", "late.html"} {
+		srv.AddFile(name)
+	}
+
+	client := oxtel.NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	events, err := ReadJSON(strings.NewReader(jsonRundown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events = append(events,
+		Event{ID: "held", Time: oxtel.Timecode{Hours: 10, Seconds: 15}, Layer: 3, Template: "held.html"},
+		Event{ID: "skipped", Time: oxtel.Timecode{Hours: 10, Seconds: 15}, Layer: 4, Template: "skipped.html"},
+		Event{ID: "missed", Time: oxtel.Timecode{Hours: 9}, Layer: 4, Template: "skipped.html"},
+	)
+
+	executor, err := NewExecutor(client, events, Options{Preroll: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.SetTime(10, 0, 0, 0)
+	if err := executor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer executor.Stop()
+
+	if err := executor.Hold("held"); err != nil {
+		t.Fatal(err)
+	}
+	if err := executor.Skip("skipped"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executor.Insert(Event{ID: "late", Time: oxtel.Timecode{Hours: 10, Seconds: 20}, Layer: 5, Template: "late.html"}); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor := func(id string, want EventStatus) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			state, err := executor.Status(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Status == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("event %s is %d, want %d", id, state.Status, want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitFor("missed", RUNDOWN_EVENT_MISSED)
+	waitFor("open", RUNDOWN_EVENT_DONE)
+	waitFor("1", RUNDOWN_EVENT_DONE)
+	waitFor("late", RUNDOWN_EVENT_DONE)
+
+	if got := srv.TextField(1, 2); got != "Host" {
+		t.Errorf("field 2 on layer 1 is %q", got)
+	}
+	if srv.FaderAngle(1) != 512 {
+		t.Errorf("layer 1 is not keyed")
+	}
+	if loaded := srv.LoadedTemplate(3); loaded == "held.html" {
+		t.Error("held event was loaded")
+	}
+	if loaded := srv.LoadedTemplate(4); loaded == "skipped.html" {
+		t.Error("skipped event was loaded")
+	}
+
+	if err := executor.Skip("open"); err == nil {
+		t.Error("expected an event that is done to be impossible to skip")
+	} else if _, ok := err.(*EventStateError); !ok {
+		t.Errorf("expected an EventStateError, got %T", err)
+	}
+
+	if err := executor.Take("held"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("held", RUNDOWN_EVENT_DONE)
+}
+
+func TestExecutorKeepsLedger(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.AddFile("lower.html")
+
+	client := oxtel.NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ledger, err := oxtel.NewScheduleLedger(client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	srv.SetTime(10, 0, 0, 0)
+	if _, err := ledger.Add(oxtel.Timecode{Hours: 11}, oxtel.CutToBCommand{}); err != nil {
+		t.Fatal(err)
+	}
+
+	events := []Event{{ID: "held", Time: oxtel.Timecode{Hours: 10, Seconds: 15}, Layer: 1, Template: "lower.html"}}
+	executor, err := NewExecutor(client, events, Options{Preroll: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.ScheduleLedger() != ledger {
+		t.Fatal("NewExecutor replaced the ledger of the Oxtel")
+	}
+
+	if err := executor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer executor.Stop()
+
+	// Holding the event deletes every scheduled command and schedules the others again, including the cut.
+	if err := executor.Hold("held"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !reflect.DeepEqual(srv.ScheduledCommands(), []string{"11000000;U1"}) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := srv.ScheduledCommands(); !reflect.DeepEqual(got, []string{"11000000;U1"}) {
+		t.Fatalf("engine has %q scheduled", got)
+	}
+}
+
+func TestExecutorSlowCallback(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+	srv.AddFile("lower.html")
+
+	client := oxtel.NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	events := []Event{{ID: "1", Time: oxtel.Timecode{Hours: 10, Frames: 10}, Layer: 1, Template: "lower.html"}}
+	executor, err := NewExecutor(client, events, Options{Preroll: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	executor.OnStatus(func(state EventState) {
+		if state.Status == RUNDOWN_EVENT_DONE {
+			close(blocked)
+			<-release
+		}
+	})
+	defer close(release)
+
+	srv.SetTime(10, 0, 0, 0)
+	if err := executor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer executor.Stop()
+
+	select {
+	case <-blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not taken")
+	}
+
+	// More tallies than a subscription queues arrive while the callback is blocked.
+	loads := make([]oxtel.Command, 0, 2*oxtel.DefaultSubscriptionBufferSize)
+	for i := 0; i < cap(loads); i++ {
+		loads = append(loads, oxtel.LoadImageCommand{Layer: 2, TemplateName: "lower.html"})
+	}
+	if err := client.SendBatch(loads...); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.EnquireMixModeContext(ctx); err != nil {
+		t.Fatalf("a blocked OnStatus callback held up the connection: %v", err)
+	}
+}
@@ -0,0 +1,443 @@
+package oxtel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ScheduledEntry is a scheduled command recorded by a ScheduleLedger.
+type ScheduledEntry struct {
+	ID      uint64
+	Time    Timecode
+	Command Command
+
+	// added is the engine time when the command was scheduled and recorded the local time, so that an entry can be
+	// recognised as passed even after the time of day has come round again. For a command sent outside the ledger,
+	// added has a zero FieldRate until the ledger next reads the engine clock.
+	added    Timecode
+	recorded time.Time
+}
+
+// ScheduleLedger keeps a record of the commands waiting in the engine scheduler. The protocol can only add scheduled
+// commands and delete all of them, so the ledger cancels or moves a single command by deleting everything and
+// scheduling the rest again.
+//
+// Once created, the ledger records every scheduled command sent through its Oxtel, including those sent with
+// AddScheduledCommand, Schedule and ScheduleOnAir, and is emptied by DeleteAllScheduledCommands. A command sent
+// outside the ledger is recorded without reading the engine clock; when it was scheduled is worked out from the local
+// clock the next time the ledger reads the engine clock. Commands scheduled by other clients are not known to the
+// ledger and are deleted by Cancel and Reschedule.
+type ScheduleLedger struct {
+	o    *Oxtel
+	path string
+
+	mu      sync.Mutex
+	entries []ScheduledEntry
+	nextID  uint64
+}
+
+// ledgerFile is the format of the file a ScheduleLedger is persisted to.
+type ledgerFile struct {
+	NextID  uint64            `json:"next_id"`
+	Entries []ledgerFileEntry `json:"entries"`
+}
+
+type ledgerFileEntry struct {
+	ID          uint64         `json:"id"`
+	Time        string         `json:"time"`
+	FieldRate   OxtelFieldRate `json:"field_rate"`
+	Progressive bool           `json:"progressive,omitempty"`
+	Command     string         `json:"command"`
+	Added       string         `json:"added,omitempty"`
+	Recorded    time.Time      `json:"recorded"`
+}
+
+// NewScheduleLedger returns a ScheduleLedger recording the commands scheduled through o. If path is not empty, the
+// ledger is loaded from that file, if it exists, and saved to it after every change.
+func NewScheduleLedger(o *Oxtel, path string) (*ScheduleLedger, error) {
+	l := &ScheduleLedger{
+		o:      o,
+		path:   path,
+		nextID: 1,
+	}
+	if path != "" {
+		if err := l.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	o.mu.Lock()
+	o.ledger = l
+	o.mu.Unlock()
+	return l, nil
+}
+
+// Close stops the ledger from recording commands scheduled through its Oxtel.
+func (l *ScheduleLedger) Close() {
+	l.o.mu.Lock()
+	defer l.o.mu.Unlock()
+
+	if l.o.ledger == l {
+		l.o.ledger = nil
+	}
+}
+
+// ScheduleLedger returns the ledger recording the commands scheduled through o, or nil if there is none.
+func (o *Oxtel) ScheduleLedger() *ScheduleLedger {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.ledger
+}
+
+// Add schedules a command and returns the ID of its entry in the ledger.
+//
+// See AddScheduledCommand for how the time relates to the output.
+func (l *ScheduleLedger) Add(tc Timecode, cmd Command) (uint64, error) {
+	return l.AddContext(context.Background(), tc, cmd)
+}
+
+// AddContext is like Add but uses ctx for cancellation and deadlines.
+func (l *ScheduleLedger) AddContext(ctx context.Context, tc Timecode, cmd Command) (uint64, error) {
+	ids, err := l.AddBatchContext(ctx, tc, cmd)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// AddBatch schedules several commands at the same time in a single write, like ScheduleBatch, and returns the IDs of
+// their entries in the ledger.
+func (l *ScheduleLedger) AddBatch(tc Timecode, cmds ...Command) ([]uint64, error) {
+	return l.AddBatchContext(context.Background(), tc, cmds...)
+}
+
+// AddBatchContext is like AddBatch but uses ctx for cancellation and deadlines.
+func (l *ScheduleLedger) AddBatchContext(ctx context.Context, tc Timecode, cmds ...Command) ([]uint64, error) {
+	scheduled := make([]Command, len(cmds))
+	for i, cmd := range cmds {
+		scheduled[i] = ScheduledCommand{Time: tc, Command: cmd}
+		if err := scheduled[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	now, err := l.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.o.sendBatch(ctx, scheduled...); err != nil {
+		return nil, err
+	}
+	ids := make([]uint64, len(cmds))
+	for i, cmd := range cmds {
+		ids[i] = l.record(now, tc, cmd)
+	}
+	return ids, l.save()
+}
+
+// List returns the commands still waiting in the scheduler, in the order they were scheduled. Commands whose time has
+// passed are dropped from the ledger first.
+func (l *ScheduleLedger) List() ([]ScheduledEntry, error) {
+	return l.ListContext(context.Background())
+}
+
+// ListContext is like List but uses ctx for cancellation and deadlines.
+func (l *ScheduleLedger) ListContext(ctx context.Context) ([]ScheduledEntry, error) {
+	now, err := l.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.prune(now) {
+		if err := l.save(); err != nil {
+			return nil, err
+		}
+	}
+	return append([]ScheduledEntry(nil), l.entries...), nil
+}
+
+// Cancel removes scheduled commands. It deletes every scheduled command and schedules the others again in a single
+// write.
+//
+// A command due in the moment between the ledger checking the time and the engine receiving the write is executed
+// twice.
+func (l *ScheduleLedger) Cancel(ids ...uint64) error {
+	return l.CancelContext(context.Background(), ids...)
+}
+
+// CancelContext is like Cancel but uses ctx for cancellation and deadlines.
+func (l *ScheduleLedger) CancelContext(ctx context.Context, ids ...uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cancelled := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		cancelled[id] = true
+	}
+
+	return l.replace(ctx, ids, func(entries []ScheduledEntry, now Timecode) []ScheduledEntry {
+		var kept []ScheduledEntry
+		for _, entry := range entries {
+			if !cancelled[entry.ID] {
+				kept = append(kept, entry)
+			}
+		}
+		return kept
+	})
+}
+
+// Reschedule moves a single scheduled command to a new time, the same way as Cancel.
+func (l *ScheduleLedger) Reschedule(id uint64, tc Timecode) error {
+	return l.RescheduleContext(context.Background(), id, tc)
+}
+
+// RescheduleContext is like Reschedule but uses ctx for cancellation and deadlines.
+func (l *ScheduleLedger) RescheduleContext(ctx context.Context, id uint64, tc Timecode) error {
+	if err := tc.Validate(); err != nil {
+		return err
+	}
+
+	return l.replace(ctx, []uint64{id}, func(entries []ScheduledEntry, now Timecode) []ScheduledEntry {
+		entries = append([]ScheduledEntry(nil), entries...)
+		i := l.index(id)
+		entries[i].Time = tc
+		if tc.FieldRate == 0 {
+			entries[i].Time.FieldRate, entries[i].Time.Progressive = now.FieldRate, now.Progressive
+		}
+		entries[i].added = now
+		entries[i].recorded = time.Now()
+		return entries
+	})
+}
+
+// replace deletes every scheduled command and schedules the entries returned by change in their place. Every one of
+// ids must be waiting.
+func (l *ScheduleLedger) replace(ctx context.Context, ids []uint64,
+	change func(entries []ScheduledEntry, now Timecode) []ScheduledEntry) error {
+	now, err := l.o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pruned := l.prune(now)
+	for _, id := range ids {
+		if l.index(id) < 0 {
+			if pruned {
+				_ = l.save()
+			}
+			return &UnknownScheduledCommandError{
+				BaseError: BaseError{
+					Message: fmt.Sprintf("No scheduled command with ID %d is waiting", id),
+				},
+			}
+		}
+	}
+
+	entries := change(l.entries, now)
+	cmds := make([]Command, 0, len(entries)+1)
+	cmds = append(cmds, DeleteAllScheduledCommandsCommand{})
+	for _, entry := range entries {
+		cmds = append(cmds, ScheduledCommand{Time: entry.Time, Command: entry.Command})
+	}
+	if err := l.o.sendBatch(ctx, cmds...); err != nil {
+		return err
+	}
+
+	l.entries = entries
+	return l.save()
+}
+
+func (l *ScheduleLedger) index(id uint64) int {
+	for i, entry := range l.entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// record adds an entry for a command scheduled at now and returns its ID. l.mu must be held.
+func (l *ScheduleLedger) record(now Timecode, tc Timecode, cmd Command) uint64 {
+	if tc.FieldRate == 0 {
+		tc.FieldRate, tc.Progressive = now.FieldRate, now.Progressive
+	}
+
+	id := l.nextID
+	l.nextID++
+	l.entries = append(l.entries, ScheduledEntry{
+		ID:       id,
+		Time:     tc,
+		Command:  cmd,
+		added:    now,
+		recorded: time.Now(),
+	})
+	return id
+}
+
+// prune drops the entries whose time has passed and reports whether there were any. l.mu must be held.
+func (l *ScheduleLedger) prune(now Timecode) bool {
+	kept := l.entries[:0]
+	for _, entry := range l.entries {
+		if entry.added.FieldRate == 0 {
+			entry.added = now.Add(-time.Since(entry.recorded))
+			if entry.Time.FieldRate == 0 {
+				entry.Time.FieldRate, entry.Time.Progressive = now.FieldRate, now.Progressive
+			}
+		}
+		if time.Since(entry.recorded) < 24*time.Hour && entry.added.Until(now) < entry.added.Until(entry.Time) {
+			kept = append(kept, entry)
+		}
+	}
+
+	pruned := len(kept) != len(l.entries)
+	l.entries = kept
+	return pruned
+}
+
+// recordScheduled updates the ledger, if there is one, after cmds were sent. It does not read the engine clock, so
+// that it can be used wherever a command can be sent.
+func (o *Oxtel) recordScheduled(cmds ...Command) {
+	o.mu.Lock()
+	l := o.ledger
+	o.mu.Unlock()
+	if l == nil {
+		return
+	}
+
+	var scheduled []ScheduledCommand
+	deleted := false
+	for _, cmd := range cmds {
+		switch c := cmd.(type) {
+		case ScheduledCommand:
+			scheduled = append(scheduled, c)
+		case DeleteAllScheduledCommandsCommand:
+			deleted = true
+			scheduled = nil
+		}
+	}
+	if !deleted && len(scheduled) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if deleted {
+		l.entries = nil
+	}
+	for _, c := range scheduled {
+		l.record(Timecode{}, c.Time, c.Command)
+	}
+	_ = l.save()
+}
+
+func (l *ScheduleLedger) load() error {
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var f ledgerFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+
+	for _, e := range f.Entries {
+		tc, err := ParseTimecode(e.Time, e.FieldRate, e.Progressive)
+		if err != nil {
+			return err
+		}
+		// An entry without an added time waits, as it did before it was saved, for the ledger to read the clock.
+		var added Timecode
+		if e.Added != "" {
+			if added, err = ParseTimecode(e.Added, e.FieldRate, e.Progressive); err != nil {
+				return err
+			}
+		}
+		cmd, err := ParseCommand(e.Command)
+		if err != nil {
+			cmd = RawCommand(e.Command)
+		}
+
+		l.entries = append(l.entries, ScheduledEntry{
+			ID:       e.ID,
+			Time:     tc,
+			Command:  cmd,
+			added:    added,
+			recorded: e.Recorded,
+		})
+	}
+	if f.NextID > l.nextID {
+		l.nextID = f.NextID
+	}
+	return nil
+}
+
+// save writes the ledger to its file, if it has one, replacing the old file only once the new one is complete. l.mu
+// must be held.
+func (l *ScheduleLedger) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	f := ledgerFile{
+		NextID:  l.nextID,
+		Entries: make([]ledgerFileEntry, 0, len(l.entries)),
+	}
+	for _, entry := range l.entries {
+		fe := ledgerFileEntry{
+			ID:          entry.ID,
+			Time:        entry.Time.String(),
+			FieldRate:   entry.Time.FieldRate,
+			Progressive: entry.Time.Progressive,
+			Command:     entry.Command.Encode(),
+			Recorded:    entry.recorded,
+		}
+		if entry.added.FieldRate != 0 {
+			fe.Added = entry.added.String()
+		}
+		f.Entries = append(f.Entries, fe)
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
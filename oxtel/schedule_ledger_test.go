@@ -0,0 +1,206 @@
+package oxtel
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestScheduleLedger(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	ledger, err := NewScheduleLedger(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	srv.SetTime(10, 0, 0, 0)
+	first, err := ledger.Add(Timecode{Hours: 11}, CutToBCommand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ledger.Add(Timecode{Hours: 11, Minutes: 30}, CutToACommand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Commands scheduled outside the ledger are recorded too.
+	if err := client.Schedule(Timecode{Hours: 12}, LoadImageCommand{Layer: 1, TemplateName: "a.html"}); err != nil {
+		t.Fatal(err)
+	}
+
+	listTimes := func(l *ScheduleLedger) []string {
+		t.Helper()
+		entries, err := l.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var times []string
+		for _, entry := range entries {
+			times = append(times, entry.Time.String())
+		}
+		return times
+	}
+	waitForSchedule := func(want ...string) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !reflect.DeepEqual(srv.ScheduledCommands(), want) && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := srv.ScheduledCommands(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("engine has %q scheduled, want %q", got, want)
+		}
+	}
+
+	if got, want := listTimes(ledger), []string{"11:00:00:00", "11:30:00:00", "12:00:00:00"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if err := ledger.Cancel(second); err != nil {
+		t.Fatal(err)
+	}
+	waitForSchedule("11000000;U1", "12000000;R01a.html")
+
+	if err := ledger.Reschedule(first, Timecode{Hours: 13}); err != nil {
+		t.Fatal(err)
+	}
+	waitForSchedule("13000000;U1", "12000000;R01a.html")
+
+	if err := ledger.Cancel(second); err == nil {
+		t.Fatal("expected a cancelled command to be unknown")
+	} else if _, ok := err.(*UnknownScheduledCommandError); !ok {
+		t.Fatalf("expected an UnknownScheduledCommandError, got %T", err)
+	}
+
+	// A new ledger picks up where the old one left off.
+	ledger.Close()
+	reloaded, err := NewScheduleLedger(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Close()
+
+	srv.SetTime(12, 30, 0, 0)
+	if got, want := listTimes(reloaded), []string{"13:00:00:00"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if entries, _ := reloaded.List(); entries[0].ID != first || entries[0].Command != (CutToBCommand{}) {
+		t.Fatalf("unexpected entry %#v", entries[0])
+	}
+
+	if err := client.DeleteAllScheduledCommands(); err != nil {
+		t.Fatal(err)
+	}
+	if got := listTimes(reloaded); len(got) != 0 {
+		t.Fatalf("%q still listed after deleting all scheduled commands", got)
+	}
+}
+
+func TestScheduleLedgerBatch(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ledger, err := NewScheduleLedger(client, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	srv.SetTime(10, 0, 0, 0)
+	ids, err := ledger.AddBatch(Timecode{Hours: 11}, CutToBCommand{}, CutToACommand{}, CutABCommand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("got %d IDs for 3 commands", len(ids))
+	}
+
+	// Several commands are cancelled with a single re-submission.
+	if err := ledger.Cancel(ids[0], ids[2]); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ledger.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != ids[1] {
+		t.Fatalf("unexpected entries %#v", entries)
+	}
+
+	if err := ledger.Cancel(ids[1], ids[0]); err == nil {
+		t.Fatal("expected a cancelled command to be unknown")
+	}
+	if entries, _ := ledger.List(); len(entries) != 1 {
+		t.Fatalf("a failed Cancel removed %d entries", 1-len(entries))
+	}
+}
+
+func TestScheduleLedgerRecordsWithoutReadingClock(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	ledger, err := NewScheduleLedger(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	// Sending a scheduled command must not wait on an enquiry, so that it can be done from a tally consumer.
+	srv.SetTime(10, 0, 0, 0)
+	if err := client.Schedule(Timecode{Hours: 10, Minutes: 30}, CutToBCommand{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireMixMode(); err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range srv.Commands() {
+		if cmd == "ix" {
+			t.Fatalf("the clock was read when the command was sent: %q", srv.Commands())
+		}
+	}
+
+	// The entry survives a reload before the ledger has read the clock.
+	ledger.Close()
+	reloaded, err := NewScheduleLedger(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Close()
+
+	entries, err := reloaded.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Time.String() != "10:30:00:00" || entries[0].Time.FieldRate != OXTEL_FIELD_RATE_5994 {
+		t.Fatalf("unexpected entries %#v", entries)
+	}
+
+	srv.SetTime(10, 31, 0, 0)
+	if entries, err := reloaded.List(); err != nil || len(entries) != 0 {
+		t.Fatalf("a passed command is still listed: %#v, %v", entries, err)
+	}
+}
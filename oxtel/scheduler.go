@@ -1,11 +1,37 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 )
 
-// AddScheduledCommand schedules an automation command at the specified time in hours, minutes, seconds, and frames.
+// ScheduleHorizon is how far ahead the engine schedules commands. Commands scheduled further ahead are issued
+// immediately.
+const ScheduleHorizon = 23*time.Hour + 59*time.Minute
+
+// ScheduledCommand is the Command sent by AddScheduledCommand and Schedule. It wraps another Command to be executed
+// at Time.
+type ScheduledCommand struct {
+	Time    Timecode
+	Command Command
+}
+
+func (c ScheduledCommand) Encode() string {
+	return fmt.Sprintf("i0%02d%02d%02d%02d;%s", c.Time.Hours, c.Time.Minutes, c.Time.Seconds, c.Time.Frames, c.Command.Encode())
+}
+
+func (c ScheduledCommand) Validate() error {
+	if err := c.Time.Validate(); err != nil {
+		return err
+	}
+
+	return c.Command.Validate()
+}
+
+// AddScheduledCommand schedules an automation command at the specified timecode.
 //
 // Scheduled commands specify a timecode value. This value should be specified as the time at which the command should be _recognized_.
 // i.e. Toxt + Tmcs before the command's results are seen on the SDI output. Toxt is the Oxtel latency in reference fields
@@ -17,74 +43,278 @@ import (
 // use with this command.
 //
 // Note: If a command is scheduled for more than 23 hours and 59 minutes in the future, then the command will be issued immediate.
-func (o *Oxtel) AddScheduledCommand(hours uint8, minutes uint8, seconds uint8, frames uint, command string) error {
-	return o.sendCommand(fmt.Sprintf("i0%02d%02d%02d%02d;%s", hours, minutes, seconds, frames, command))
+func (o *Oxtel) AddScheduledCommand(tc Timecode, command string) error {
+	return o.AddScheduledCommandContext(context.Background(), tc, command)
 }
 
-// AddScheduledCommand_AsString returns the command string used to schedule an automation command at the specified time
-// in hours, minutes, seconds, and frames.
+// AddScheduledCommandContext is like AddScheduledCommand but uses ctx for cancellation and deadlines.
+func (o *Oxtel) AddScheduledCommandContext(ctx context.Context, tc Timecode, command string) error {
+	return o.SendContext(ctx, ScheduledCommand{Time: tc, Command: RawCommand(command)})
+}
+
+// AddScheduledCommand_AsString returns the command string used to schedule an automation command at the specified
+// timecode.
 //
 // For use with scheduled commands.
-func AddScheduledCommand_AsString(hours uint8, minutes uint8, seconds uint8, frames uint, command string) string {
-	return fmt.Sprintf("i0%02d%02d%02d%02d;%s", hours, minutes, seconds, frames, command)
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a ScheduledCommand instead.
+func AddScheduledCommand_AsString(tc Timecode, command string) string {
+	return ScheduledCommand{Time: tc, Command: RawCommand(command)}.Encode()
+}
+
+// scheduleTiming caches what ScheduleOnAir and EnquireCurrentTime need to know about the engine. It is cleared on
+// every connect.
+type scheduleTiming struct {
+	mu            sync.Mutex
+	generation    uint64
+	latencyValid  bool
+	latency       int
+	standardValid bool
+	progressive   bool
+}
+
+func (t *scheduleTiming) clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.latencyValid, t.standardValid = false, false
+	t.generation++
+}
+
+// ScheduleOnAir schedules a command so that its effect is seen on the SDI output at the given timecode.
+//
+// AddScheduledCommand and Schedule take the time at which the command is recognized, which is Toxt + Tmcs reference
+// fields earlier. ScheduleOnAir enquires both latencies and the video standard of the engine once per connection and
+// subtracts the latency from the on-air time. At interlaced standards two fields make a frame, so the latency is
+// halved and rounded up; at progressive standards every field is a frame. A zero FieldRate in the timecode is set,
+// along with Progressive, from the engine clock.
+//
+// A ScheduleHorizonError is returned if the recognition time is more than ScheduleHorizon after the current time of
+// the engine, including when it has already passed, rather than letting the engine issue the command immediately.
+func (o *Oxtel) ScheduleOnAir(onAir Timecode, cmd Command) error {
+	return o.ScheduleOnAirContext(context.Background(), onAir, cmd)
+}
+
+// ScheduleOnAirContext is like ScheduleOnAir but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ScheduleOnAirContext(ctx context.Context, onAir Timecode, cmd Command) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	recognized, _, err := o.recognitionTimeContext(ctx, onAir)
+	if err != nil {
+		return err
+	}
+	return o.ScheduleContext(ctx, recognized, cmd)
+}
+
+// recognitionTimeContext returns the time at which a command must be recognized for its effect to be seen on the SDI
+// output at onAir, as described for ScheduleOnAir, and the current time of the engine.
+func (o *Oxtel) recognitionTimeContext(ctx context.Context, onAir Timecode) (Timecode, Timecode, error) {
+	latency, err := o.scheduleLatencyContext(ctx)
+	if err != nil {
+		return Timecode{}, Timecode{}, err
+	}
+	now, err := o.EnquireCurrentTimeContext(ctx)
+	if err != nil {
+		return Timecode{}, Timecode{}, err
+	}
+
+	if onAir.FieldRate == 0 {
+		onAir.FieldRate, onAir.Progressive = now.FieldRate, now.Progressive
+	} else if onAir.FieldRate != now.FieldRate || onAir.Progressive != now.Progressive {
+		return Timecode{}, Timecode{}, &InvalidTimecodeError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Timecode %s has field rate %d, progressive %t, but the engine runs at %d, progressive %t",
+					onAir, onAir.FieldRate, onAir.Progressive, now.FieldRate, now.Progressive),
+			},
+		}
+	}
+	if err := onAir.Validate(); err != nil {
+		return Timecode{}, Timecode{}, err
+	}
+
+	frames := latency
+	if !now.Progressive {
+		frames = (latency + 1) / 2
+	}
+	recognized := onAir.AddFrames(-frames)
+
+	if now.Until(recognized) > ScheduleHorizon {
+		return Timecode{}, Timecode{}, &ScheduleHorizonError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Timecode %s must be recognized at %s, which is not within %s of %s", onAir, recognized, ScheduleHorizon, now),
+			},
+		}
+	}
+
+	return recognized, now, nil
+}
+
+// scheduleLatencyContext returns Toxt + Tmcs in reference fields.
+func (o *Oxtel) scheduleLatencyContext(ctx context.Context) (int, error) {
+	o.timing.mu.Lock()
+	valid, generation, latency := o.timing.latencyValid, o.timing.generation, o.timing.latency
+	o.timing.mu.Unlock()
+
+	if valid {
+		return latency, nil
+	}
+
+	oxtel, err := o.EnquireLatencyContext(ctx, OXTEL_LATENCY_SOURCE_OXTEL)
+	if err != nil {
+		return 0, err
+	}
+	mcs, err := o.EnquireLatencyContext(ctx, OXTEL_LATENCY_SOURCE_MCS)
+	if err != nil {
+		return 0, err
+	}
+	latency = int(oxtel.Latency) + int(mcs.Latency)
+
+	// Results from before a reconnect are used once but not kept.
+	o.timing.mu.Lock()
+	if o.timing.generation == generation {
+		o.timing.latencyValid, o.timing.latency = true, latency
+	}
+	o.timing.mu.Unlock()
+
+	return latency, nil
+}
+
+// progressiveContext reports whether the video standard of the engine is progressive, so that its timecode counts
+// every frame.
+func (o *Oxtel) progressiveContext(ctx context.Context) (bool, error) {
+	o.timing.mu.Lock()
+	valid, generation, progressive := o.timing.standardValid, o.timing.generation, o.timing.progressive
+	o.timing.mu.Unlock()
+
+	if valid {
+		return progressive, nil
+	}
+
+	status, err := o.EnquireSystemStatusContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch status.VideoStandard {
+	case OXTEL_VIDEO_STANDARD_PAL, OXTEL_VIDEO_STANDARD_NTSC, OXTEL_VIDEO_STANDARD_1080I_5994, OXTEL_VIDEO_STANDARD_1080I_50:
+		progressive = false
+	default:
+		progressive = true
+	}
+
+	o.timing.mu.Lock()
+	if o.timing.generation == generation {
+		o.timing.standardValid, o.timing.progressive = true, progressive
+	}
+	o.timing.mu.Unlock()
+
+	return progressive, nil
+}
+
+// DeleteAllScheduledCommandsCommand is the Command sent by DeleteAllScheduledCommands.
+type DeleteAllScheduledCommandsCommand struct{}
+
+func (c DeleteAllScheduledCommandsCommand) Encode() string {
+	return "i2"
+}
+
+func (c DeleteAllScheduledCommandsCommand) Validate() error {
+	return nil
 }
 
 // DeleteAllScheduledCommands deletes all scheduled commands.
 func (o *Oxtel) DeleteAllScheduledCommands() error {
-	return o.sendCommand("i2")
+	return o.DeleteAllScheduledCommandsContext(context.Background())
+}
+
+// DeleteAllScheduledCommandsContext is like DeleteAllScheduledCommands but uses ctx for cancellation and deadlines.
+func (o *Oxtel) DeleteAllScheduledCommandsContext(ctx context.Context) error {
+	return o.SendContext(ctx, DeleteAllScheduledCommandsCommand{})
 }
 
 // DeleteAllScheduledCommands_AsString returns the command used to delete all scheduled commands.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a DeleteAllScheduledCommandsCommand instead.
 func DeleteAllScheduledCommands_AsString() string {
-	return "i2"
+	return DeleteAllScheduledCommandsCommand{}.Encode()
+}
+
+// EnquireCurrentTimeCommand is the Enquiry sent by EnquireCurrentTime.
+type EnquireCurrentTimeCommand struct{}
+
+func (c EnquireCurrentTimeCommand) Encode() string {
+	return "ix"
+}
+
+func (c EnquireCurrentTimeCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireCurrentTimeCommand) ResponsePrefix() string {
+	return "ix"
+}
+
+// EnquireCurrentTime queries the current time as referenced to VITC. The FieldRate of the returned timecode is set
+// from the response, and Progressive from the video standard, which is enquired once per connection.
+func (o *Oxtel) EnquireCurrentTime() (Timecode, error) {
+	return o.EnquireCurrentTimeContext(context.Background())
 }
 
-// EnquireCurrentTime queries the current time as referenced to VITC.
-func (o *Oxtel) EnquireCurrentTime() (CurrentTimeResponse, error) {
-	val, err := o.sendCommandExpectResponse("ix", "")
+// EnquireCurrentTimeContext is like EnquireCurrentTime but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireCurrentTimeContext(ctx context.Context) (Timecode, error) {
+	progressive, err := o.progressiveContext(ctx)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
+	}
+
+	val, err := o.EnquireContext(ctx, EnquireCurrentTimeCommand{})
+	if err != nil {
+		return Timecode{}, err
 	}
 
 	rate, err := strconv.ParseUint(string(val[0]), 10, 8)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
 	}
 
 	hours, err := strconv.ParseUint(string(val[1:3]), 10, 8)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
 	}
 
 	minutes, err := strconv.ParseUint(string(val[3:5]), 10, 8)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
 	}
 
 	seconds, err := strconv.ParseUint(string(val[5:7]), 10, 8)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
 	}
 
 	frames, err := strconv.ParseUint(string(val[7:9]), 10, 8)
 	if err != nil {
-		return CurrentTimeResponse{}, err
+		return Timecode{}, err
 	}
 
-	return CurrentTimeResponse{
-		FieldRate: OxtelFieldRate(rate),
-		Hours:     uint8(hours),
-		Minutes:   uint8(minutes),
-		Seconds:   uint8(seconds),
-		Frames:    uint8(frames),
+	return Timecode{
+		Hours:       uint8(hours),
+		Minutes:     uint8(minutes),
+		Seconds:     uint8(seconds),
+		Frames:      uint8(frames),
+		FieldRate:   OxtelFieldRate(rate),
+		Progressive: progressive,
 	}, nil
 }
 
 // EnquireCurrentTime_AsString returns the command string used to query the current time as referenced to VITC.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireCurrentTimeCommand instead.
 func EnquireCurrentTime_AsString() string {
-	return "ix"
+	return EnquireCurrentTimeCommand{}.Encode()
 }
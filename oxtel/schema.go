@@ -0,0 +1,237 @@
+package oxtel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldSchema describes a named field of a template.
+type FieldSchema struct {
+	Name  string
+	Field uint8
+	Type  OxtelFieldType
+
+	// MaxLength is the longest text, in characters, the field accepts. Zero means there is no limit.
+	MaxLength int
+}
+
+// TemplateSchema is the named fields of a template.
+type TemplateSchema struct {
+	Template string
+	Fields   map[string]FieldSchema
+}
+
+// SchemaRegistry holds the schemas used by SetFields to find fields by name.
+type SchemaRegistry struct {
+	mu        sync.Mutex
+	templates map[string]TemplateSchema
+}
+
+// schemaFile is the format of a schema registry file.
+type schemaFile struct {
+	Templates map[string]struct {
+		Fields map[string]struct {
+			Field     *uint8 `json:"field"`
+			Type      string `json:"type"`
+			MaxLength int    `json:"max_length"`
+		} `json:"fields"`
+	} `json:"templates"`
+}
+
+// NewSchemaRegistry returns an empty SchemaRegistry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{templates: make(map[string]TemplateSchema)}
+}
+
+// LoadSchemaRegistry reads a SchemaRegistry from a JSON file. See ReadSchemaRegistry for the format.
+func LoadSchemaRegistry(path string) (*SchemaRegistry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadSchemaRegistry(f)
+}
+
+// ReadSchemaRegistry reads a SchemaRegistry in JSON. The "templates" object maps a template name to an object whose
+// "fields" map a field name to its "field" number, its "type" ("text", the default, or "image") and optionally its
+// "max_length":
+//
+//	{"templates": {"lower.html": {"fields": {
+//		"name": {"field": 1, "type": "text", "max_length": 32},
+//		"logo": {"field": 3, "type": "image"}
+//	}}}}
+func ReadSchemaRegistry(r io.Reader) (*SchemaRegistry, error) {
+	var file schemaFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	reg := NewSchemaRegistry()
+	for template, t := range file.Templates {
+		schema := TemplateSchema{Template: template, Fields: make(map[string]FieldSchema, len(t.Fields))}
+		for name, f := range t.Fields {
+			if f.Field == nil {
+				return nil, invalidField("Field %s of template %s has no field number", name, template)
+			}
+			fieldType, err := parseFieldType(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			schema.Fields[name] = FieldSchema{Name: name, Field: *f.Field, Type: fieldType, MaxLength: f.MaxLength}
+		}
+		if err := reg.Register(schema); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+func parseFieldType(s string) (OxtelFieldType, error) {
+	switch s {
+	case "", "text":
+		return OXTEL_FIELD_TEXT, nil
+	case "image":
+		return OXTEL_FIELD_IMAGE, nil
+	}
+	return 0, invalidField("Field type %q is neither text nor image", s)
+}
+
+// Register adds or replaces the schema of a template. Field numbers must be unique and less than 255.
+func (r *SchemaRegistry) Register(schema TemplateSchema) error {
+	numbers := make(map[uint8]string, len(schema.Fields))
+	for name, f := range schema.Fields {
+		if f.Field > 254 {
+			return invalidField("Field %s of template %s must be less than 255", name, schema.Template)
+		}
+		if other, ok := numbers[f.Field]; ok {
+			return invalidField("Fields %s and %s of template %s are both field %d", other, name, schema.Template, f.Field)
+		}
+		numbers[f.Field] = name
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.templates[schema.Template] = schema
+	return nil
+}
+
+// Lookup returns the schema of a template.
+func (r *SchemaRegistry) Lookup(template string) (TemplateSchema, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	schema, ok := r.templates[template]
+	return schema, ok
+}
+
+// Commands validates values against the schema of a template and returns an UpdateTextField or ChangeImage command
+// for each of them, in field order, followed by a single RenderBox of all fields. Text fields take a string or a
+// fmt.Stringer and image fields take a file name.
+func (r *SchemaRegistry) Commands(template string, layer OxtelLayer, values map[string]any) ([]Command, error) {
+	schema, ok := r.Lookup(template)
+	if !ok {
+		return nil, &MissingSchemaError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("No schema for template %s", template),
+			},
+		}
+	}
+
+	fields := make([]FieldSchema, 0, len(values))
+	for name := range values {
+		f, ok := schema.Fields[name]
+		if !ok {
+			return nil, invalidField("Template %s has no field %s", template, name)
+		}
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+
+	cmds := make([]Command, 0, len(fields)+1)
+	for _, f := range fields {
+		var s string
+		switch v := values[f.Name].(type) {
+		case string:
+			s = v
+		case fmt.Stringer:
+			if f.Type != OXTEL_FIELD_TEXT {
+				return nil, invalidField("Image field %s must be set to a file name", f.Name)
+			}
+			s = v.String()
+		default:
+			return nil, invalidField("Field %s cannot be set to %T", f.Name, v)
+		}
+
+		if f.MaxLength > 0 && utf8.RuneCountInString(s) > f.MaxLength {
+			return nil, invalidField("Field %s is longer than %d characters", f.Name, f.MaxLength)
+		}
+
+		if f.Type == OXTEL_FIELD_IMAGE {
+			cmds = append(cmds, ChangeImageCommand{Layer: layer, Field: f.Field, FileName: s})
+		} else {
+			cmds = append(cmds, UpdateTextFieldCommand{Layer: layer, Field: f.Field, Text: s})
+		}
+	}
+
+	return append(cmds, RenderBoxCommand{Layer: layer, Field: 0xFF}), nil
+}
+
+func invalidField(format string, a ...interface{}) error {
+	return &InvalidFieldError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf(format, a...),
+		},
+	}
+}
+
+// SetSchemas sets the registry SetFields finds fields in.
+func (o *Oxtel) SetSchemas(registry *SchemaRegistry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.schemas = registry
+}
+
+// SetFields sets the fields of the template loaded on a layer by name, using the schema registered for the template
+// with SetSchemas. Every value is validated before anything is sent; the updates are then sent in one batch, without
+// rendering, followed by a single RenderBox of all fields. Long text is split as by UpdateTextField.
+//
+// A MissingSchemaError is returned if no registry is set or the template has no schema, and an InvalidFieldError if a
+// value does not fit the schema.
+func (o *Oxtel) SetFields(layer OxtelLayer, values map[string]any) error {
+	return o.SetFieldsContext(context.Background(), layer, values)
+}
+
+// SetFieldsContext is like SetFields but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetFieldsContext(ctx context.Context, layer OxtelLayer, values map[string]any) error {
+	o.mu.Lock()
+	registry := o.schemas
+	o.mu.Unlock()
+	if registry == nil {
+		return &MissingSchemaError{
+			BaseError: BaseError{
+				Message: "No schema registry set",
+			},
+		}
+	}
+
+	loaded, err := o.EnquireLoadImageContext(ctx, layer)
+	if err != nil {
+		return err
+	}
+
+	cmds, err := registry.Commands(loaded.Filename, layer, values)
+	if err != nil {
+		return err
+	}
+	return o.SendBatchContext(ctx, o.splitText(cmds...)...)
+}
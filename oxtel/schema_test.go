@@ -0,0 +1,72 @@
+package oxtel
+
+import (
+	"strings"
+	"testing"
+)
+
+const schemaJSON = `{"templates": {"lower.html": {"fields": {
+	"name": {"field": 1, "max_length": 8},
+	"title": {"field": 2, "type": "text"},
+	"logo": {"field": 3, "type": "image"}
+}}}}`
+
+func TestSetFields(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+
+	registry, err := ReadSchemaRegistry(strings.NewReader(schemaJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetFields(OXTEL_LAYER_1, map[string]any{"name": "Jane"}); err == nil {
+		t.Error("expected an error without a registry")
+	}
+	client.SetSchemas(registry)
+
+	if err := client.LoadImage(OXTEL_LAYER_1, "lower.html"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireLoadImage(OXTEL_LAYER_1); err != nil {
+		t.Fatal(err)
+	}
+	srv.ClearCommands()
+
+	if err := client.SetFields(OXTEL_LAYER_1, map[string]any{"logo": "acme.png", "name": "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.EnquireLoadImage(OXTEL_LAYER_1); err != nil {
+		t.Fatal(err)
+	}
+	commands := srv.Commands()
+	want := []string{"R01", "Z01010Jane", "Z4103acme.png", "Z01ff", "R01"}
+	if strings.Join(commands, " ") != strings.Join(want, " ") {
+		t.Errorf("got commands %q, want %q", commands, want)
+	}
+	if got := srv.TextField(1, 1); got != "Jane" {
+		t.Errorf("field 1 is %q", got)
+	}
+	if got := srv.Image(1, 3); got != "acme.png" {
+		t.Errorf("image 3 is %q", got)
+	}
+
+	for _, values := range []map[string]any{
+		{"subtitle": "x"},
+		{"name": "Jane Doe-Smith"},
+		{"name": 42},
+	} {
+		err := client.SetFields(OXTEL_LAYER_1, values)
+		if _, ok := err.(*InvalidFieldError); !ok {
+			t.Errorf("%v: expected an InvalidFieldError, got %v", values, err)
+		}
+	}
+
+	err = client.SetFields(OXTEL_LAYER_2, map[string]any{"name": "Jane"})
+	if _, ok := err.(*MissingSchemaError); !ok {
+		t.Errorf("expected a MissingSchemaError for an empty layer, got %v", err)
+	}
+
+	if _, err := ReadSchemaRegistry(strings.NewReader(`{"templates": {"a.html": {"fields": {"a": {"field": 1}, "b": {"field": 1}}}}}`)); err == nil {
+		t.Error("expected duplicate field numbers to be rejected")
+	}
+}
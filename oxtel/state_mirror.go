@@ -0,0 +1,367 @@
+package oxtel
+
+import (
+	"context"
+	"sync"
+)
+
+// stateMirrorBufferSize is the queue length of the subscription feeding a StateMirror.
+const stateMirrorBufferSize = 256
+
+// LayerState is the state of a graphic layer as seen by a StateMirror.
+type LayerState struct {
+	Layer      OxtelLayer
+	Loaded     string
+	Preloaded  string
+	FaderAngle uint16
+	Keyer      OxtelKeyerPositionTally
+	Playing    bool
+}
+
+// MixerState is the state of the A/B mixer as seen by a StateMirror.
+type MixerState struct {
+	// Position is OXTEL_MIXER_A or OXTEL_MIXER_B when the mixer is fully on that input and OXTEL_MIXER_IN_BETWEEN
+	// otherwise.
+	Position OxtelMixerInput
+	SourceA  OxtelVideoSource
+	SourceB  OxtelVideoSource
+	Mode     MixModeResponse
+}
+
+// EngineState is a snapshot of the engine taken by a StateMirror.
+type EngineState struct {
+	Layers         []LayerState
+	Mixer          MixerState
+	SessionLocks   LocksResponse
+	PermanentLocks LocksResponse
+}
+
+// StateMirror keeps a copy of what is loaded, preloaded and on air. It is filled from enquiries when it is created
+// and after every reconnect, and kept current from tallies in between.
+type StateMirror struct {
+	o   *Oxtel
+	sub *Subscription
+
+	mu         sync.Mutex
+	state      EngineState
+	callbacks  []func(state EngineState, cause interface{})
+	refreshing int
+	pending    []interface{}
+	closed     bool
+
+	done       chan struct{}
+	unregister func()
+}
+
+// NewStateMirror enables the video, play state and lock tallies, fills a new StateMirror from the engine and keeps it
+// current until Close or Disconnect is called.
+func NewStateMirror(o *Oxtel) (*StateMirror, error) {
+	return NewStateMirrorContext(context.Background(), o)
+}
+
+// NewStateMirrorContext is like NewStateMirror but uses ctx for cancellation and deadlines.
+func NewStateMirrorContext(ctx context.Context, o *Oxtel) (*StateMirror, error) {
+	m := &StateMirror{
+		o: o,
+		sub: o.Subscribe(SubscriptionOptions{
+			Types: []OxtelTallyType{
+				OXTEL_TALLY_KEYER_POSITION,
+				OXTEL_TALLY_IMAGE_LOAD,
+				OXTEL_TALLY_IMAGE_PRELOAD,
+				OXTEL_TALLY_PLAY_STATE,
+				OXTEL_TALLY_VIDEO,
+				OXTEL_TALLY_LOCK,
+			},
+			BufferSize: stateMirrorBufferSize,
+		}),
+		done: make(chan struct{}),
+	}
+	go m.run()
+
+	err := o.SendBatchContext(ctx,
+		EnableVideoTalliesCommand{Enable: true},
+		EnablePlayStateTallyCommand{Enable: true},
+		EnableOxtelLockTallyCommand{Enable: true},
+	)
+	if err == nil {
+		err = m.Refresh(ctx)
+	}
+	if err != nil {
+		m.Close()
+		return nil, err
+	}
+
+	m.unregister = o.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		if state != OXTEL_CONNECTION_STATE_CONNECTED {
+			return
+		}
+		go func() {
+			select {
+			case <-m.done:
+			default:
+				m.Refresh(context.Background())
+			}
+		}()
+	})
+
+	return m, nil
+}
+
+// Snapshot returns a copy of the current state.
+func (m *StateMirror) Snapshot() EngineState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state.clone()
+}
+
+// OnChange registers a callback that is invoked with a snapshot whenever the state changes. The cause is the tally
+// that changed the state, or nil after a refresh.
+//
+// Callbacks are invoked synchronously from the goroutine applying tallies and must not block. In particular they must
+// not wait on an enquiry, as the tallies queued behind them hold up every response from the engine.
+func (m *StateMirror) OnChange(callback func(state EngineState, cause interface{})) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.callbacks = append(m.callbacks, callback)
+}
+
+// Refresh replaces the state with the results of enquiries to the engine. Tallies received while the enquiries are in
+// flight are applied on top of the results.
+func (m *StateMirror) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	m.refreshing++
+	m.mu.Unlock()
+
+	state, err := m.enquire(ctx)
+
+	m.mu.Lock()
+	m.refreshing--
+	if err == nil {
+		// Play state has no enquiry; the tally sent when it is enabled is the only source.
+		for i := range state.Layers {
+			if i < len(m.state.Layers) {
+				state.Layers[i].Playing = m.state.Layers[i].Playing
+			}
+		}
+		m.state = state
+	}
+	if m.refreshing == 0 {
+		for _, tally := range m.pending {
+			m.state.apply(tally)
+		}
+		m.pending = nil
+	}
+	snapshot, callbacks := m.state.clone(), m.callbacksLocked()
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	for _, callback := range callbacks {
+		callback(snapshot, nil)
+	}
+	return nil
+}
+
+// Close stops the StateMirror from following the engine. The tallies it enabled are left enabled.
+func (m *StateMirror) Close() {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.done)
+	}
+	m.mu.Unlock()
+
+	if m.unregister != nil {
+		m.unregister()
+	}
+	m.sub.Close()
+}
+
+func (m *StateMirror) enquire(ctx context.Context) (EngineState, error) {
+	var state EngineState
+
+	n, err := m.o.EnquireNumberOfGraphicLayersContext(ctx)
+	if err != nil {
+		return state, err
+	}
+
+	state.Layers = make([]LayerState, n)
+	for i := range state.Layers {
+		layer := OxtelLayer(i)
+
+		loaded, err := m.o.EnquireLoadImageContext(ctx, layer)
+		if err != nil {
+			return state, err
+		}
+		preloaded, err := m.o.EnquirePreloadImageContext(ctx, layer)
+		if err != nil {
+			return state, err
+		}
+		status, err := m.o.EnquireVideoLayerStatusContext(ctx, layer)
+		if err != nil {
+			return state, err
+		}
+
+		state.Layers[i] = LayerState{
+			Layer:      layer,
+			Loaded:     loaded.Filename,
+			Preloaded:  preloaded.Filename,
+			FaderAngle: status.LayerFaderAngle,
+			Keyer:      keyerPositionOf(status.LayerFaderAngle),
+		}
+	}
+
+	a, err := m.o.EnquireMixerInputContext(ctx, OXTEL_MIXER_A)
+	if err != nil {
+		return state, err
+	}
+	b, err := m.o.EnquireMixerInputContext(ctx, OXTEL_MIXER_B)
+	if err != nil {
+		return state, err
+	}
+	mode, err := m.o.EnquireMixModeContext(ctx)
+	if err != nil {
+		return state, err
+	}
+	state.Mixer = MixerState{
+		Position: mixerPositionOf(mode.ABMixAngle),
+		SourceA:  a.Source,
+		SourceB:  b.Source,
+		Mode:     mode,
+	}
+
+	if state.SessionLocks, err = m.o.EnquireSessionLocksContext(ctx); err != nil {
+		return state, err
+	}
+	if state.PermanentLocks, err = m.o.EnquirePermanentLocksContext(ctx); err != nil {
+		return state, err
+	}
+
+	return state, nil
+}
+
+func (m *StateMirror) run() {
+	for tally := range m.sub.C {
+		m.mu.Lock()
+		if m.refreshing > 0 {
+			m.pending = append(m.pending, tally)
+			m.mu.Unlock()
+			continue
+		}
+		changed := m.state.apply(tally)
+		snapshot, callbacks := m.state.clone(), m.callbacksLocked()
+		m.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+		for _, callback := range callbacks {
+			callback(snapshot, tally)
+		}
+	}
+}
+
+func (m *StateMirror) callbacksLocked() []func(EngineState, interface{}) {
+	callbacks := make([]func(EngineState, interface{}), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	return callbacks
+}
+
+// apply updates the state from a tally and reports whether anything changed.
+func (s *EngineState) apply(tally interface{}) bool {
+	before := s.clone()
+
+	switch t := tally.(type) {
+	case KeyerPositionTally:
+		if l := s.layer(t.Layer); l != nil {
+			l.setKeyer(t.Direction)
+		}
+	case ImageLoadTally:
+		if l := s.layer(t.Layer); l != nil {
+			l.Loaded = t.Template
+		}
+	case ImagePreloadTally:
+		if l := s.layer(t.Layer); l != nil {
+			l.Preloaded = t.Template
+		}
+	case PlayStateTally:
+		if l := s.layer(t.Layer); l != nil {
+			l.Playing = t.State == OXTEL_PLAY_STATE_TALLY_PLAYING
+		}
+	case VideoTally:
+		s.Mixer.Position = OxtelMixerInput(t.MixerInput)
+		s.Mixer.SourceA = t.MixerASource
+		s.Mixer.SourceB = t.MixerBSource
+		if l := s.layer(OXTEL_LAYER_0); l != nil {
+			l.setKeyer(t.Layer0)
+		}
+		if l := s.layer(OXTEL_LAYER_1); l != nil {
+			l.setKeyer(t.Layer1)
+		}
+	case LockTally:
+		s.SessionLocks = t.SessionLocks
+		s.PermanentLocks = t.PermanentLocks
+	default:
+		return false
+	}
+
+	return !before.equal(*s)
+}
+
+func (s *EngineState) layer(layer OxtelLayer) *LayerState {
+	if int(layer) >= len(s.Layers) {
+		return nil
+	}
+	return &s.Layers[layer]
+}
+
+func (s EngineState) clone() EngineState {
+	s.Layers = append([]LayerState(nil), s.Layers...)
+	return s
+}
+
+func (s EngineState) equal(other EngineState) bool {
+	if len(s.Layers) != len(other.Layers) {
+		return false
+	}
+	for i := range s.Layers {
+		if s.Layers[i] != other.Layers[i] {
+			return false
+		}
+	}
+	return s.Mixer == other.Mixer && s.SessionLocks == other.SessionLocks && s.PermanentLocks == other.PermanentLocks
+}
+
+// setKeyer records the keyer position reported by a tally. The fader angle is only known once a transition is over.
+func (l *LayerState) setKeyer(direction OxtelDirection) {
+	l.Keyer = OxtelKeyerPositionTally(direction)
+	switch l.Keyer {
+	case OXTEL_KEYER_TALLY_DOWN:
+		l.FaderAngle = 0
+	case OXTEL_KEYER_TALLY_UP:
+		l.FaderAngle = 512
+	}
+}
+
+func keyerPositionOf(angle uint16) OxtelKeyerPositionTally {
+	switch {
+	case angle == 0:
+		return OXTEL_KEYER_TALLY_DOWN
+	case angle >= 512:
+		return OXTEL_KEYER_TALLY_UP
+	}
+	return OXTEL_KEYER_TALLY_IN_TRANSITION
+}
+
+func mixerPositionOf(angle uint16) OxtelMixerInput {
+	switch {
+	case angle == 0:
+		return OXTEL_MIXER_A
+	case angle >= 512:
+		return OXTEL_MIXER_B
+	}
+	return OXTEL_MIXER_IN_BETWEEN
+}
@@ -0,0 +1,84 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStateMirror(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.AddFile("lower.html")
+	srv.AddFile("bug.html")
+
+	if err := client.LoadImage(OXTEL_LAYER_2, "lower.html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SelectMixerInput(OXTEL_MIXER_B, OXTEL_VIDEO_SOURCE_EXT_IN_2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mirror, err := NewStateMirror(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mirror.Close()
+
+	state := mirror.Snapshot()
+	if len(state.Layers) == 0 {
+		t.Fatal("no layers")
+	}
+	if state.Layers[2].Loaded != "lower.html" {
+		t.Errorf("layer 2 has %q loaded", state.Layers[2].Loaded)
+	}
+	if state.Mixer.SourceB != OXTEL_VIDEO_SOURCE_EXT_IN_2 || state.Mixer.Position != OXTEL_MIXER_A {
+		t.Errorf("unexpected mixer state %#v", state.Mixer)
+	}
+
+	changes := make(chan EngineState, 16)
+	mirror.OnChange(func(state EngineState, cause interface{}) {
+		changes <- state
+	})
+
+	// Snapshots are copies.
+	state.Layers[2].Loaded = ""
+
+	if err := client.PreloadImage(OXTEL_LAYER_2, "bug.html"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CutKeyer(OXTEL_LAYER_2, OXTEL_DIR_UP); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case state := <-changes:
+			layer := state.Layers[2]
+			if layer.Loaded == "lower.html" && layer.Preloaded == "bug.html" && layer.Keyer == OXTEL_KEYER_TALLY_UP {
+				if snapshot := mirror.Snapshot(); snapshot.Layers[2] != layer {
+					t.Fatalf("snapshot %#v does not match notification %#v", snapshot.Layers[2], layer)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out; state is %#v", mirror.Snapshot().Layers[2])
+		}
+	}
+}
+
+func TestStateMirrorCloseUnregisters(t *testing.T) {
+	_, client := connectTallyClient(t)
+
+	mirror, err := NewStateMirror(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := stateCallbackCount(client); n != 1 {
+		t.Fatalf("%d connection state callbacks registered", n)
+	}
+
+	mirror.Close()
+	if n := stateCallbackCount(client); n != 0 {
+		t.Fatalf("%d connection state callbacks left after Close", n)
+	}
+}
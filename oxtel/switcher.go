@@ -0,0 +1,319 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// switcherBufferSize is the queue length of the subscription feeding a Switcher.
+const switcherBufferSize = 16
+
+// SwitcherSource is a named video source of a Switcher. ARC, if set, overrides the aspect ratio conversion while the
+// source is on air.
+type SwitcherSource struct {
+	Name   string
+	Source OxtelVideoSource
+	ARC    *OxtelARC
+}
+
+// SwitcherTransition is the transition used by Take. Duration is in fields (interlaced) or frames (progressive) and is
+// ignored for OXTEL_TRANSITION_TYPE_CUT.
+type SwitcherTransition struct {
+	Type     OxtelTransitionType
+	Duration uint16
+}
+
+// Switcher drives the A/B mixer as a program and a preset bus. Whichever of the A and B inputs is on air is the
+// program and the other is the preset, so the inputs swap roles after every take. The Switcher follows the swap from
+// the VideoTally, so takes made by other clients are accounted for.
+//
+// Sources are named with the names reported by EnquireExternalInputs. Other sources, such as the players, can be named
+// with AddSource.
+type Switcher struct {
+	o   *Oxtel
+	sub *Subscription
+
+	mu      sync.Mutex
+	sources map[string]SwitcherSource
+	program OxtelMixerInput
+	routed  [2]OxtelVideoSource
+	closed  bool
+
+	done       chan struct{}
+	unregister func()
+}
+
+// NewSwitcher enables the video tallies, names the external inputs and reads the mixer state into a new Switcher,
+// which follows the mixer until Close or Disconnect is called.
+func NewSwitcher(o *Oxtel) (*Switcher, error) {
+	return NewSwitcherContext(context.Background(), o)
+}
+
+// NewSwitcherContext is like NewSwitcher but uses ctx for cancellation and deadlines.
+func NewSwitcherContext(ctx context.Context, o *Oxtel) (*Switcher, error) {
+	s := &Switcher{
+		o: o,
+		sub: o.Subscribe(SubscriptionOptions{
+			Types:      []OxtelTallyType{OXTEL_TALLY_VIDEO},
+			BufferSize: switcherBufferSize,
+			Overflow:   OXTEL_OVERFLOW_DROP_OLDEST,
+		}),
+		sources: make(map[string]SwitcherSource),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+
+	err := o.enableVideoTalliesContext(ctx)
+	if err == nil {
+		err = s.Refresh(ctx)
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	s.unregister = o.OnConnectionStateChange(func(state OxtelConnectionState, err error) {
+		if state != OXTEL_CONNECTION_STATE_CONNECTED {
+			return
+		}
+		go func() {
+			select {
+			case <-s.done:
+			default:
+				s.Refresh(context.Background())
+			}
+		}()
+	})
+
+	return s, nil
+}
+
+// Refresh names the external inputs again and reads which input is on air and the sources routed to A and B. Sources
+// added with AddSource and the ARC of every source are kept.
+func (s *Switcher) Refresh(ctx context.Context) error {
+	inputs, err := s.o.EnquireExternalInputsContext(ctx)
+	if err != nil {
+		return err
+	}
+	a, err := s.o.EnquireMixerInputContext(ctx, OXTEL_MIXER_A)
+	if err != nil {
+		return err
+	}
+	b, err := s.o.EnquireMixerInputContext(ctx, OXTEL_MIXER_B)
+	if err != nil {
+		return err
+	}
+	mode, err := s.o.EnquireMixModeContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, in := range inputs.ExternalInputs {
+		source := SwitcherSource{Name: in.Name, Source: OxtelVideoSource(in.VideoSourceId)}
+		if old, ok := s.sources[in.Name]; ok && old.Source == source.Source {
+			source.ARC = old.ARC
+		}
+		s.sources[in.Name] = source
+	}
+	s.routed = [2]OxtelVideoSource{a.Source, b.Source}
+	// Between the inputs, the one mostly visible is taken as the program.
+	s.program = OXTEL_MIXER_A
+	if mode.ABMixAngle >= 256 {
+		s.program = OXTEL_MIXER_B
+	}
+	return nil
+}
+
+// AddSource names a source that is not an external input, such as OXTEL_VIDEO_SOURCE_PLAYER_A. An existing source of
+// the same name is replaced.
+func (s *Switcher) AddSource(name string, source OxtelVideoSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources[name] = SwitcherSource{Name: name, Source: source}
+}
+
+// SetSourceARC sets the aspect ratio conversion applied when the named source is routed by SetPreset. A nil ARC uses
+// the default conversion.
+func (s *Switcher) SetSourceARC(name string, arc *OxtelARC) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.sources[name]
+	if !ok {
+		return unknownSource(name)
+	}
+	source.ARC = arc
+	s.sources[name] = source
+	return nil
+}
+
+// Sources returns the named sources, sorted by name.
+func (s *Switcher) Sources() []SwitcherSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SwitcherSource, 0, len(s.sources))
+	for _, source := range s.sources {
+		out = append(out, source)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Program returns the source on air. A source without a name is returned with an empty Name.
+func (s *Switcher) Program() SwitcherSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sourceLocked(s.routed[s.program])
+}
+
+// Preset returns the source that the next Take or Cut puts on air.
+func (s *Switcher) Preset() SwitcherSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sourceLocked(s.routed[1-s.program])
+}
+
+// SetPreset routes the named source to the preset input of the mixer with its ARC.
+func (s *Switcher) SetPreset(name string) error {
+	return s.SetPresetContext(context.Background(), name)
+}
+
+// SetPresetContext is like SetPreset but uses ctx for cancellation and deadlines.
+func (s *Switcher) SetPresetContext(ctx context.Context, name string) error {
+	s.mu.Lock()
+	source, ok := s.sources[name]
+	preset := 1 - s.program
+	s.mu.Unlock()
+	if !ok {
+		return unknownSource(name)
+	}
+
+	if err := s.o.SelectMixerInputContext(ctx, preset, source.Source, source.ARC); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.routed[preset] = source.Source
+	s.mu.Unlock()
+	return nil
+}
+
+// Take puts the preset on air with the given transition. The preset becomes the program when the returned
+// MixerTransition completes.
+func (s *Switcher) Take(transition SwitcherTransition) (*MixerTransition, error) {
+	return s.TakeContext(context.Background(), transition)
+}
+
+// TakeContext is like Take but uses ctx for cancellation and deadlines while the transition is started.
+func (s *Switcher) TakeContext(ctx context.Context, transition SwitcherTransition) (*MixerTransition, error) {
+	s.mu.Lock()
+	preset := 1 - s.program
+	s.mu.Unlock()
+
+	cmd, target := Command(FadeToACommand{Duration: transition.Duration}), 0
+	if preset == OXTEL_MIXER_B {
+		cmd, target = FadeToBCommand{Duration: transition.Duration}, 512
+	}
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.o.SetTransitionTypeContext(ctx, transition.Type); err != nil {
+		return nil, err
+	}
+	return s.o.startMixerTransition(ctx, target, cmd, func(position uint16, err error) {
+		if err == nil {
+			s.setProgram(preset)
+		}
+	})
+}
+
+// Cut puts the preset on air immediately.
+func (s *Switcher) Cut() error {
+	return s.CutContext(context.Background())
+}
+
+// CutContext is like Cut but uses ctx for cancellation and deadlines.
+func (s *Switcher) CutContext(ctx context.Context) error {
+	s.mu.Lock()
+	preset := 1 - s.program
+	s.mu.Unlock()
+
+	cmd := Command(CutToACommand{})
+	if preset == OXTEL_MIXER_B {
+		cmd = CutToBCommand{}
+	}
+	if err := s.o.SendContext(ctx, cmd); err != nil {
+		return err
+	}
+
+	s.setProgram(preset)
+	return nil
+}
+
+// Close stops the Switcher from following the mixer. The video tallies are left enabled.
+func (s *Switcher) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+
+	if s.unregister != nil {
+		s.unregister()
+	}
+	s.sub.Close()
+}
+
+func (s *Switcher) setProgram(input OxtelMixerInput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.program = input
+}
+
+// sourceLocked returns the source with the given id, taking the first name in order if it has more than one.
+func (s *Switcher) sourceLocked(id OxtelVideoSource) SwitcherSource {
+	found := SwitcherSource{Source: id}
+	for _, source := range s.sources {
+		if source.Source == id && (found.Name == "" || source.Name < found.Name) {
+			found = source
+		}
+	}
+	return found
+}
+
+func (s *Switcher) run() {
+	for tally := range s.sub.C {
+		v, ok := tally.(VideoTally)
+		if !ok {
+			continue
+		}
+
+		s.mu.Lock()
+		s.routed = [2]OxtelVideoSource{v.MixerASource, v.MixerBSource}
+		// The program only changes once a transition is over.
+		if input := OxtelMixerInput(v.MixerInput); input == OXTEL_MIXER_A || input == OXTEL_MIXER_B {
+			s.program = input
+		}
+		s.mu.Unlock()
+	}
+}
+
+func unknownSource(name string) error {
+	return &InvalidParametersError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf("No source named %s", name),
+		},
+	}
+}
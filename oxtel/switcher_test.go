@@ -0,0 +1,86 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSwitcher(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	sw, err := NewSwitcher(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sw.Close()
+	sw.AddSource("Player A", OXTEL_VIDEO_SOURCE_PLAYER_A)
+
+	if got := len(sw.Sources()); got != 5 {
+		t.Errorf("got %d sources, want 4 external inputs and Player A", got)
+	}
+	if p := sw.Program(); p.Name != "Player A" {
+		t.Errorf("program is %+v, want Player A", p)
+	}
+	if err := sw.SetPreset("Ext In 9"); err == nil {
+		t.Error("expected an unknown source to be rejected")
+	}
+
+	letter := OXTEL_ARC_LETTER
+	if err := sw.SetSourceARC("Ext In 2", &letter); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.SetPreset("Ext In 2"); err != nil {
+		t.Fatal(err)
+	}
+	take, err := sw.Take(SwitcherTransition{Type: OXTEL_TRANSITION_TYPE_X_FADE, Duration: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position, err := take.Wait(); err != nil || position != 512 {
+		t.Fatalf("take ended at %d, %v", position, err)
+	}
+	if source, arc := srv.MixerSource(1); source != 0x7 || arc != uint8(OXTEL_ARC_LETTER) {
+		t.Errorf("B input is source %x with ARC %x", source, arc)
+	}
+	if p := sw.Program(); p.Name != "Ext In 2" {
+		t.Errorf("program is %+v after the take, want Ext In 2", p)
+	}
+	if p := sw.Preset(); p.Name != "Player A" {
+		t.Errorf("preset is %+v after the take, want Player A", p)
+	}
+
+	// A is the preset bus now.
+	if err := sw.SetPreset("Ext In 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sw.Cut(); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for srv.MixerPosition() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if source, _ := srv.MixerSource(0); source != 0x1 || srv.MixerPosition() != 0 {
+		t.Errorf("A input is source %x at mixer position %d", source, srv.MixerPosition())
+	}
+	if p := sw.Program(); p.Name != "Ext In 1" {
+		t.Errorf("program is %+v after the cut, want Ext In 1", p)
+	}
+}
+
+func TestSwitcherCloseUnregisters(t *testing.T) {
+	_, client := connectTallyClient(t)
+
+	switcher, err := NewSwitcher(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := stateCallbackCount(client); n != 1 {
+		t.Fatalf("%d connection state callbacks registered", n)
+	}
+
+	switcher.Close()
+	if n := stateCallbackCount(client); n != 0 {
+		t.Fatalf("%d connection state callbacks left after Close", n)
+	}
+}
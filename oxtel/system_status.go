@@ -1,18 +1,41 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
+// EnquireLatencyCommand is the Enquiry sent by EnquireLatency.
+type EnquireLatencyCommand struct {
+	Source OxtelLatencySource
+}
+
+func (c EnquireLatencyCommand) Encode() string {
+	return fmt.Sprintf("hLAT%x", c.Source)
+}
+
+func (c EnquireLatencyCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireLatencyCommand) ResponsePrefix() string {
+	return "hLAT"
+}
+
 // EnquireLatency returns the latency, in reference frames, of the specified source to the SDI output.
 //
 // Refer to the Timing Model section for more details.
 //
 // Response is a LatencyResponse.
 func (o *Oxtel) EnquireLatency(source OxtelLatencySource) (LatencyResponse, error) {
-	val, err := o.sendCommandExpectResponse("hLAT", fmt.Sprintf("%x", source))
+	return o.EnquireLatencyContext(context.Background(), source)
+}
+
+// EnquireLatencyContext is like EnquireLatency but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireLatencyContext(ctx context.Context, source OxtelLatencySource) (LatencyResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireLatencyCommand{Source: source})
 	if err != nil {
 		return LatencyResponse{}, err
 	}
@@ -36,15 +59,37 @@ func (o *Oxtel) EnquireLatency(source OxtelLatencySource) (LatencyResponse, erro
 // to the SDI output.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireLatencyCommand instead.
 func EnquireLatency_AsString(source OxtelLatencySource) string {
-	return fmt.Sprintf("hLAT%x", source)
+	return EnquireLatencyCommand{Source: source}.Encode()
+}
+
+// EnquireNumberOfGraphicLayersCommand is the Enquiry sent by EnquireNumberOfGraphicLayers.
+type EnquireNumberOfGraphicLayersCommand struct{}
+
+func (c EnquireNumberOfGraphicLayersCommand) Encode() string {
+	return "hNGL"
+}
+
+func (c EnquireNumberOfGraphicLayersCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireNumberOfGraphicLayersCommand) ResponsePrefix() string {
+	return "hNGL"
 }
 
 // EnquireNumberOfGraphicLayers returns the number of graphic layers license for this channel.
 //
 // Response is an integer representing the number of licensed graphic layers.
 func (o *Oxtel) EnquireNumberOfGraphicLayers() (int, error) {
-	val, err := o.sendCommandExpectResponse("hNGL", "")
+	return o.EnquireNumberOfGraphicLayersContext(context.Background())
+}
+
+// EnquireNumberOfGraphicLayersContext is like EnquireNumberOfGraphicLayers but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireNumberOfGraphicLayersContext(ctx context.Context) (int, error) {
+	val, err := o.EnquireContext(ctx, EnquireNumberOfGraphicLayersCommand{})
 	if err != nil {
 		return 0, err
 	}
@@ -55,8 +100,25 @@ func (o *Oxtel) EnquireNumberOfGraphicLayers() (int, error) {
 // for this channel.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireNumberOfGraphicLayersCommand instead.
 func EnquireNumberOfGraphicLayers_AsString() string {
-	return "hNGL"
+	return EnquireNumberOfGraphicLayersCommand{}.Encode()
+}
+
+// EnquireSystemStatusCommand is the Enquiry sent by EnquireSystemStatus.
+type EnquireSystemStatusCommand struct{}
+
+func (c EnquireSystemStatusCommand) Encode() string {
+	return "M"
+}
+
+func (c EnquireSystemStatusCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireSystemStatusCommand) ResponsePrefix() string {
+	return "M"
 }
 
 // EnquireSystemStatus queries information about the system status.
@@ -66,7 +128,12 @@ func EnquireNumberOfGraphicLayers_AsString() string {
 //
 // Response is a SystemStatusResponse.
 func (o *Oxtel) EnquireSystemStatus() (SystemStatusResponse, error) {
-	val, err := o.sendCommandExpectResponse("M", "")
+	return o.EnquireSystemStatusContext(context.Background())
+}
+
+// EnquireSystemStatusContext is like EnquireSystemStatus but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireSystemStatusContext(ctx context.Context) (SystemStatusResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireSystemStatusCommand{})
 	if err != nil {
 		return SystemStatusResponse{}, err
 	}
@@ -139,8 +206,27 @@ func (o *Oxtel) EnquireSystemStatus() (SystemStatusResponse, error) {
 // EnquireSystemStatus_AsString returns the command string used to query information about the system status.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireSystemStatusCommand instead.
 func EnquireSystemStatus_AsString() string {
-	return "M"
+	return EnquireSystemStatusCommand{}.Encode()
+}
+
+// EnquireVideoLayerStatusCommand is the Enquiry sent by EnquireVideoLayerStatus.
+type EnquireVideoLayerStatusCommand struct {
+	Layer OxtelLayer
+}
+
+func (c EnquireVideoLayerStatusCommand) Encode() string {
+	return fmt.Sprintf("N%x", c.Layer)
+}
+
+func (c EnquireVideoLayerStatusCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireVideoLayerStatusCommand) ResponsePrefix() string {
+	return "N"
 }
 
 // EnquireVideoLayerStatus returns status information about the specified layer.
@@ -149,7 +235,12 @@ func EnquireSystemStatus_AsString() string {
 //
 // Response is a VideoLayerStatusResponse.
 func (o *Oxtel) EnquireVideoLayerStatus(layer OxtelLayer) (VideoLayerStatusResponse, error) {
-	val, err := o.sendCommandExpectResponse("N", fmt.Sprintf("%x", layer))
+	return o.EnquireVideoLayerStatusContext(context.Background(), layer)
+}
+
+// EnquireVideoLayerStatusContext is like EnquireVideoLayerStatus but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireVideoLayerStatusContext(ctx context.Context, layer OxtelLayer) (VideoLayerStatusResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireVideoLayerStatusCommand{Layer: layer})
 	if err != nil {
 		return VideoLayerStatusResponse{}, err
 	}
@@ -191,8 +282,28 @@ func (o *Oxtel) EnquireVideoLayerStatus(layer OxtelLayer) (VideoLayerStatusRespo
 // EnquireVideoLayerStatus_AsString returns the command string used to return status information about the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireVideoLayerStatusCommand instead.
 func EnquireVideoLayerStatus_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("N%x", layer)
+	return EnquireVideoLayerStatusCommand{Layer: layer}.Encode()
+}
+
+// EnquireCommandAvailabilityCommand is the Enquiry sent by EnquireCommandAvailability.
+type EnquireCommandAvailabilityCommand struct {
+	Byte1 byte
+	Byte2 byte
+}
+
+func (c EnquireCommandAvailabilityCommand) Encode() string {
+	return fmt.Sprintf("X3%c%c", c.Byte1, c.Byte2)
+}
+
+func (c EnquireCommandAvailabilityCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireCommandAvailabilityCommand) ResponsePrefix() string {
+	return "X3"
 }
 
 // EnquireCommandAvailability is used to determine if a particular Oxtel command is currently available on the Harmonic
@@ -202,7 +313,12 @@ func EnquireVideoLayerStatus_AsString(layer OxtelLayer) string {
 //
 // Response is a CommandAvailabilityResponse.
 func (o *Oxtel) EnquireCommandAvailability(byte1 byte, byte2 byte) (CommandAvailabilityResponse, error) {
-	val, err := o.sendCommandExpectResponse("X3", fmt.Sprintf("%c%c", byte1, byte2))
+	return o.EnquireCommandAvailabilityContext(context.Background(), byte1, byte2)
+}
+
+// EnquireCommandAvailabilityContext is like EnquireCommandAvailability but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireCommandAvailabilityContext(ctx context.Context, byte1 byte, byte2 byte) (CommandAvailabilityResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireCommandAvailabilityCommand{Byte1: byte1, Byte2: byte2})
 	if err != nil {
 		return CommandAvailabilityResponse{}, err
 	}
@@ -225,8 +341,25 @@ func (o *Oxtel) EnquireCommandAvailability(byte1 byte, byte2 byte) (CommandAvail
 // currently available on the Harmonic product for automation to use.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireCommandAvailabilityCommand instead.
 func EnquireCommandAvailability_AsString(byte1 byte, byte2 byte) string {
-	return fmt.Sprintf("X3%c%c", byte1, byte2)
+	return EnquireCommandAvailabilityCommand{Byte1: byte1, Byte2: byte2}.Encode()
+}
+
+// EnquireSlaveLayerStatusCommand is the Enquiry sent by EnquireSlaveLayerStatus.
+type EnquireSlaveLayerStatusCommand struct{}
+
+func (c EnquireSlaveLayerStatusCommand) Encode() string {
+	return "XA"
+}
+
+func (c EnquireSlaveLayerStatusCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireSlaveLayerStatusCommand) ResponsePrefix() string {
+	return "XA"
 }
 
 // EnquireSlaveLayerStatus is different than the Miranda implementation. It queries the current stat of the keyer for
@@ -234,7 +367,12 @@ func EnquireCommandAvailability_AsString(byte1 byte, byte2 byte) string {
 //
 // Response is a SlaveLayerStatusResponse
 func (o *Oxtel) EnquireSlaveLayerStatus() (SlaveLayerStatusResponse, error) {
-	val, err := o.sendCommandExpectResponse("XA", "")
+	return o.EnquireSlaveLayerStatusContext(context.Background())
+}
+
+// EnquireSlaveLayerStatusContext is like EnquireSlaveLayerStatus but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireSlaveLayerStatusContext(ctx context.Context) (SlaveLayerStatusResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireSlaveLayerStatusCommand{})
 	if err != nil {
 		return SlaveLayerStatusResponse{}, err
 	}
@@ -301,8 +439,25 @@ func (o *Oxtel) EnquireSlaveLayerStatus() (SlaveLayerStatusResponse, error) {
 // layer (0x1 = fader angle is 0x200, 0x0 = fader angle is not 0x200).
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireSlaveLayerStatusCommand instead.
 func EnquireSlaveLayerStatus_AsString() string {
-	return "XA"
+	return EnquireSlaveLayerStatusCommand{}.Encode()
+}
+
+// EnquireFullVersionNumberCommand is the Enquiry sent by EnquireFullVersionNumber.
+type EnquireFullVersionNumberCommand struct{}
+
+func (c EnquireFullVersionNumberCommand) Encode() string {
+	return "Xb"
+}
+
+func (c EnquireFullVersionNumberCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireFullVersionNumberCommand) ResponsePrefix() string {
+	return "Xb"
 }
 
 // EnquireFullVersionNumber returns the full software version number.
@@ -310,7 +465,12 @@ func EnquireSlaveLayerStatus_AsString() string {
 // Response is a FullVersionNumberResponse.
 // In order, the integers represent Major, Minor, Patch, Branch, and Build Number.
 func (o *Oxtel) EnquireFullVersionNumber() (FullVersionNumberResponse, error) {
-	val, err := o.sendCommandExpectResponse("Xb", "")
+	return o.EnquireFullVersionNumberContext(context.Background())
+}
+
+// EnquireFullVersionNumberContext is like EnquireFullVersionNumber but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireFullVersionNumberContext(ctx context.Context) (FullVersionNumberResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireFullVersionNumberCommand{})
 	if err != nil {
 		return FullVersionNumberResponse{}, err
 	}
@@ -350,42 +510,93 @@ func (o *Oxtel) EnquireFullVersionNumber() (FullVersionNumberResponse, error) {
 // EnquireFullVersionNumber_AsString returns the command string used to return the full software version number.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireFullVersionNumberCommand instead.
 func EnquireFullVersionNumber_AsString() string {
-	return "Xb"
+	return EnquireFullVersionNumberCommand{}.Encode()
+}
+
+// EnquireProductNameCommand is the Enquiry sent by EnquireProductName.
+type EnquireProductNameCommand struct{}
+
+func (c EnquireProductNameCommand) Encode() string {
+	return "Xn"
+}
+
+func (c EnquireProductNameCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireProductNameCommand) ResponsePrefix() string {
+	return "Xn"
 }
 
 // EnquireProductName returns the product name.
 //
 // Response is a string representing the product name. Valid responses will be "ChannelPort", "Spectrum X", or "Electra X".
 func (o *Oxtel) EnquireProductName() (string, error) {
-	return o.sendCommandExpectResponse("Xn", "")
+	return o.EnquireProductNameContext(context.Background())
+}
+
+// EnquireProductNameContext is like EnquireProductName but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireProductNameContext(ctx context.Context) (string, error) {
+	return o.EnquireContext(ctx, EnquireProductNameCommand{})
 }
 
 // EnquireProductName_AsString returns the command string used to return the product name.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireProductNameCommand instead.
 func EnquireProductName_AsString() string {
-	return "Xn"
+	return EnquireProductNameCommand{}.Encode()
 }
 
 // GetMediaPortName returns the globally unique name of the media port or channel of a Spectrum-X media deck.
 //
 // Response is a string of the globally unique name of the media port.
 func (o *Oxtel) GetMediaPortName() (string, error) {
-	return o.EnquireMediaPortName()
+	return o.GetMediaPortNameContext(context.Background())
+}
+
+// GetMediaPortNameContext is like GetMediaPortName but uses ctx for cancellation and deadlines.
+func (o *Oxtel) GetMediaPortNameContext(ctx context.Context) (string, error) {
+	return o.EnquireMediaPortNameContext(ctx)
+}
+
+// EnquireMediaPortNameCommand is the Enquiry sent by EnquireMediaPortName.
+type EnquireMediaPortNameCommand struct{}
+
+func (c EnquireMediaPortNameCommand) Encode() string {
+	return "hTN"
+}
+
+func (c EnquireMediaPortNameCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireMediaPortNameCommand) ResponsePrefix() string {
+	return "hTN"
 }
 
 // EnquireMediaPortName returns the globally unique name of the media port or channel of a Spectrum-X media deck.
 //
 // Response is a string of the globally unique name of the media port.
 func (o *Oxtel) EnquireMediaPortName() (string, error) {
-	return o.sendCommandExpectResponse("hTN", "")
+	return o.EnquireMediaPortNameContext(context.Background())
+}
+
+// EnquireMediaPortNameContext is like EnquireMediaPortName but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireMediaPortNameContext(ctx context.Context) (string, error) {
+	return o.EnquireContext(ctx, EnquireMediaPortNameCommand{})
 }
 
 // EnquireMediaPortName_AsString returns the command string used to return the globally unique name of the media port
 // or channel of a Spectrum-X media deck.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireMediaPortNameCommand instead.
 func EnquireMediaPortName_AsString() string {
-	return "hTN"
+	return EnquireMediaPortNameCommand{}.Encode()
 }
@@ -0,0 +1,228 @@
+package oxtel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriptionBufferSize is the queue length of a Subscription whose options do not set BufferSize.
+const DefaultSubscriptionBufferSize = 64
+
+// SubscriptionOptions configures a Subscription.
+type SubscriptionOptions struct {
+	// Types selects the tallies delivered to the subscription. Every tally is delivered if Types is empty.
+	Types []OxtelTallyType
+
+	// BufferSize is the number of tallies queued for the subscriber. DefaultSubscriptionBufferSize is used if it is
+	// zero or negative.
+	BufferSize int
+
+	// Overflow decides what happens to a tally that arrives while the queue is full. The default,
+	// OXTEL_OVERFLOW_BLOCK, loses nothing but holds up every response and tally from the engine until the subscriber
+	// catches up, so a subscriber using it must not wait on an enquiry while its queue is full.
+	Overflow OxtelOverflowPolicy
+}
+
+// Subscription is a queue of tallies from the engine. Tallies are received from C, which is closed by Close and by
+// Disconnect. Subscriptions are kept across reconnects.
+type Subscription struct {
+	C <-chan interface{}
+
+	c         chan interface{}
+	types     map[OxtelTallyType]bool
+	overflow  OxtelOverflowPolicy
+	dropped   atomic.Uint64
+	done      chan struct{}
+	mu        sync.Mutex
+	closed    bool
+	closeOnce sync.Once
+	bus       *tallyBus
+}
+
+// tallyBus delivers every tally to the subscriptions that asked for it.
+type tallyBus struct {
+	mu     sync.Mutex
+	subs   []*Subscription
+	closed bool
+}
+
+// Subscribe returns a new Subscription to the tallies received from the engine. Unlike the Unsolicited channel,
+// which drops every tally that arrives while nobody is receiving, each subscription has its own queue and counts the
+// tallies it drops.
+//
+// Tallies still have to be enabled on the engine, e.g. with EnableVideoTallies.
+func (o *Oxtel) Subscribe(opts SubscriptionOptions) *Subscription {
+	size := opts.BufferSize
+	if size <= 0 {
+		size = DefaultSubscriptionBufferSize
+	}
+
+	s := &Subscription{
+		c:        make(chan interface{}, size),
+		overflow: opts.Overflow,
+		done:     make(chan struct{}),
+		bus:      &o.tallies,
+	}
+	s.C = s.c
+	if len(opts.Types) > 0 {
+		s.types = make(map[OxtelTallyType]bool, len(opts.Types))
+		for _, t := range opts.Types {
+			s.types[t] = true
+		}
+	}
+
+	o.tallies.mu.Lock()
+	closed := o.tallies.closed
+	if !closed {
+		o.tallies.subs = append(o.tallies.subs, s)
+	}
+	o.tallies.mu.Unlock()
+
+	if closed {
+		s.Close()
+	}
+	return s
+}
+
+// Dropped returns the number of tallies dropped because the queue was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops delivery to the subscription and closes C. Tallies already queued can still be received.
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.bus.remove(s)
+
+		s.mu.Lock()
+		s.closed = true
+		close(s.c)
+		s.mu.Unlock()
+	})
+}
+
+func (s *Subscription) wants(t OxtelTallyType) bool {
+	return s.types == nil || s.types[t]
+}
+
+func (s *Subscription) deliver(tally interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	switch s.overflow {
+	case OXTEL_OVERFLOW_DROP_NEWEST:
+		select {
+		case s.c <- tally:
+		default:
+			s.dropped.Add(1)
+		}
+	case OXTEL_OVERFLOW_DROP_OLDEST:
+		for {
+			select {
+			case s.c <- tally:
+				return
+			default:
+			}
+
+			select {
+			case <-s.c:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.c <- tally:
+		case <-s.done:
+		}
+	}
+}
+
+func (b *tallyBus) publish(tally interface{}) {
+	t := TallyTypeOf(tally)
+
+	b.mu.Lock()
+	subs := make([]*Subscription, 0, len(b.subs))
+	for _, s := range b.subs {
+		if s.wants(t) {
+			subs = append(subs, s)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.deliver(tally)
+	}
+}
+
+func (b *tallyBus) remove(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// close closes every subscription and every later one.
+func (b *tallyBus) close() {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = nil
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.Close()
+	}
+}
+
+// enableVideoTalliesContext enables the video tallies unless they were already enabled through this client.
+func (o *Oxtel) enableVideoTalliesContext(ctx context.Context) error {
+	o.mu.Lock()
+	enabled := o.armed.videoTallies
+	o.mu.Unlock()
+	if enabled {
+		return nil
+	}
+	return o.EnableVideoTalliesContext(ctx, true)
+}
+
+// TallyTypeOf returns the type of a tally received from a Subscription or the Unsolicited channel.
+func TallyTypeOf(tally interface{}) OxtelTallyType {
+	switch tally.(type) {
+	case KeyerPositionTally:
+		return OXTEL_TALLY_KEYER_POSITION
+	case ImageLoadTally:
+		return OXTEL_TALLY_IMAGE_LOAD
+	case ImagePreloadTally:
+		return OXTEL_TALLY_IMAGE_PRELOAD
+	case MediaTally:
+		return OXTEL_TALLY_MEDIA
+	case PlayStateTally:
+		return OXTEL_TALLY_PLAY_STATE
+	case VideoTally:
+		return OXTEL_TALLY_VIDEO
+	case AudioProfileTally:
+		return OXTEL_TALLY_AUDIO_PROFILE
+	case LockTally:
+		return OXTEL_TALLY_LOCK
+	case ExternalIOSourceChangedTally:
+		return OXTEL_TALLY_EXTERNAL_IO_SOURCE_CHANGED
+	case ExternalIODynamicConfigChangedTally:
+		return OXTEL_TALLY_EXTERNAL_IO_DYNAMIC_CONFIG
+	case MalformedTally:
+		return OXTEL_TALLY_MALFORMED
+	}
+
+	return OXTEL_TALLY_UNKNOWN
+}
@@ -0,0 +1,157 @@
+package oxtel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func connectTallyClient(t *testing.T) (*oxteltest.Server, *Oxtel) {
+	t.Helper()
+
+	srv := oxteltest.NewServer()
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	for srv.NumConnections() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	t.Cleanup(func() {
+		client.Disconnect()
+		srv.Close()
+	})
+	return srv, client
+}
+
+func receiveTally(t *testing.T, sub *Subscription) interface{} {
+	t.Helper()
+
+	select {
+	case tally, ok := <-sub.C:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return tally
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for tally")
+	}
+	return nil
+}
+
+func broadcastImageLoads(srv *oxteltest.Server, n int) {
+	for i := 0; i < n; i++ {
+		srv.Broadcast(fmt.Sprintf("Y90template%d.html", i))
+	}
+}
+
+func TestSubscribeFiltersByType(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	keyers := client.Subscribe(SubscriptionOptions{Types: []OxtelTallyType{OXTEL_TALLY_KEYER_POSITION}})
+	all := client.Subscribe(SubscriptionOptions{})
+
+	srv.Broadcast("Y92a.html")
+	srv.Broadcast("31 1")
+
+	if _, ok := receiveTally(t, all).(ImageLoadTally); !ok {
+		t.Fatal("expected the image load tally first")
+	}
+	if _, ok := receiveTally(t, all).(KeyerPositionTally); !ok {
+		t.Fatal("expected the keyer position tally second")
+	}
+
+	tally, ok := receiveTally(t, keyers).(KeyerPositionTally)
+	if !ok || tally.Layer != OXTEL_LAYER_1 {
+		t.Fatalf("unexpected tally %#v", tally)
+	}
+	if len(keyers.C) != 0 {
+		t.Fatalf("%d unexpected tallies queued", len(keyers.C))
+	}
+}
+
+func TestSubscriptionOverflow(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	oldest := client.Subscribe(SubscriptionOptions{BufferSize: 2, Overflow: OXTEL_OVERFLOW_DROP_OLDEST})
+	newest := client.Subscribe(SubscriptionOptions{BufferSize: 2, Overflow: OXTEL_OVERFLOW_DROP_NEWEST})
+	all := client.Subscribe(SubscriptionOptions{BufferSize: 10})
+
+	broadcastImageLoads(srv, 5)
+	for i := 0; i < 5; i++ {
+		receiveTally(t, all)
+	}
+
+	for _, c := range []struct {
+		sub       *Subscription
+		templates []string
+	}{
+		{oldest, []string{"template3.html", "template4.html"}},
+		{newest, []string{"template0.html", "template1.html"}},
+	} {
+		if c.sub.Dropped() != 3 {
+			t.Errorf("expected 3 dropped tallies, got %d", c.sub.Dropped())
+		}
+		for _, want := range c.templates {
+			if tally := receiveTally(t, c.sub).(ImageLoadTally); tally.Template != want {
+				t.Errorf("got %s, want %s", tally.Template, want)
+			}
+		}
+	}
+}
+
+func TestBlockingSubscriptionLosesNothing(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	sub := client.Subscribe(SubscriptionOptions{BufferSize: 1})
+	broadcastImageLoads(srv, 20)
+
+	for i := 0; i < 20; i++ {
+		time.Sleep(time.Millisecond)
+		tally := receiveTally(t, sub).(ImageLoadTally)
+		if want := fmt.Sprintf("template%d.html", i); tally.Template != want {
+			t.Fatalf("got %s, want %s", tally.Template, want)
+		}
+	}
+	if sub.Dropped() != 0 {
+		t.Fatalf("%d tallies dropped", sub.Dropped())
+	}
+}
+
+func TestDisconnectClosesSubscriptions(t *testing.T) {
+	srv, client := connectTallyClient(t)
+
+	sub := client.Subscribe(SubscriptionOptions{BufferSize: 1})
+	broadcastImageLoads(srv, 2)
+	receiveTally(t, sub)
+
+	// The second tally is blocked waiting for room in the queue.
+	client.Disconnect()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-sub.C:
+			if !ok {
+				if late := client.Subscribe(SubscriptionOptions{}); !isClosed(late) {
+					t.Fatal("subscription made after Disconnect is open")
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription was not closed by Disconnect")
+		}
+	}
+}
+
+func isClosed(sub *Subscription) bool {
+	select {
+	case _, ok := <-sub.C:
+		return !ok
+	default:
+		return false
+	}
+}
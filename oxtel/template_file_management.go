@@ -1,17 +1,40 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
+// EnquireFileInfoCommand is the Enquiry sent by EnquireFileInfo.
+type EnquireFileInfoCommand struct {
+	FileName string
+}
+
+func (c EnquireFileInfoCommand) Encode() string {
+	return fmt.Sprintf("R3%s", c.FileName)
+}
+
+func (c EnquireFileInfoCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireFileInfoCommand) ResponsePrefix() string {
+	return "R3"
+}
+
 // EnquireFileInfo queries information about the existence of the template on the file system. The folder location searched
 // is defined by the Effects configuration section in SystemManager.
 //
 // Response is a FileInfoResponse.
 func (o *Oxtel) EnquireFileInfo(fileName string) (FileInfoResponse, error) {
-	val, err := o.sendCommandExpectResponse("R3", fileName)
+	return o.EnquireFileInfoContext(context.Background(), fileName)
+}
+
+// EnquireFileInfoContext is like EnquireFileInfo but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireFileInfoContext(ctx context.Context, fileName string) (FileInfoResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireFileInfoCommand{FileName: fileName})
 	if err != nil {
 		return FileInfoResponse{}, err
 	}
@@ -31,8 +54,30 @@ func (o *Oxtel) EnquireFileInfo(fileName string) (FileInfoResponse, error) {
 // the file system. The folder location searched is defined by the Effects configuration section in SystemManager.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireFileInfoCommand instead.
 func EnquireFileInfo_AsString(fileName string) string {
-	return fmt.Sprintf("R3%s", fileName)
+	return EnquireFileInfoCommand{FileName: fileName}.Encode()
+}
+
+// QueryFirstFileCommand is the Enquiry sent by QueryFirstFile. A nil FolderName uses the template folder.
+type QueryFirstFileCommand struct {
+	FolderName *string
+}
+
+func (c QueryFirstFileCommand) Encode() string {
+	if c.FolderName == nil {
+		return "R4$VIDEO"
+	}
+	return fmt.Sprintf("R4%s", *c.FolderName)
+}
+
+func (c QueryFirstFileCommand) Validate() error {
+	return nil
+}
+
+func (c QueryFirstFileCommand) ResponsePrefix() string {
+	return "R4"
 }
 
 // QueryFirstFile queries the name of the first file within the specified folder name alias.
@@ -43,11 +88,12 @@ func EnquireFileInfo_AsString(fileName string) string {
 // The order of filenames returned is "older file first".
 // Response is a FileQueryResponse.
 func (o *Oxtel) QueryFirstFile(folderName *string) (FileQueryResponse, error) {
-	if folderName == nil {
-		defaultVal := "$VIDEO"
-		folderName = &defaultVal
-	}
-	val, err := o.sendCommandExpectResponse("R4", *folderName)
+	return o.QueryFirstFileContext(context.Background(), folderName)
+}
+
+// QueryFirstFileContext is like QueryFirstFile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) QueryFirstFileContext(ctx context.Context, folderName *string) (FileQueryResponse, error) {
+	val, err := o.EnquireContext(ctx, QueryFirstFileCommand{FolderName: folderName})
 	if err != nil {
 		return FileQueryResponse{}, err
 	}
@@ -66,12 +112,30 @@ func (o *Oxtel) QueryFirstFile(folderName *string) (FileQueryResponse, error) {
 // QueryFirstFile_AsString returns the command string used to query the name of the first file within the specified folder name alias.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a QueryFirstFileCommand instead.
 func QueryFirstFile_AsString(folderName *string) string {
-	if folderName == nil {
-		defaultVal := "$VIDEO"
-		folderName = &defaultVal
+	return QueryFirstFileCommand{FolderName: folderName}.Encode()
+}
+
+// QuerySubsequentFileCommand is the Enquiry sent by QuerySubsequentFile. A nil FolderName uses the template folder.
+type QuerySubsequentFileCommand struct {
+	FolderName *string
+}
+
+func (c QuerySubsequentFileCommand) Encode() string {
+	if c.FolderName == nil {
+		return "R5$VIDEO"
 	}
-	return fmt.Sprintf("R4%s", *folderName)
+	return fmt.Sprintf("R5%s", *c.FolderName)
+}
+
+func (c QuerySubsequentFileCommand) Validate() error {
+	return nil
+}
+
+func (c QuerySubsequentFileCommand) ResponsePrefix() string {
+	return "R5"
 }
 
 // QuerySubsequentFile is used in conjunction with QueryFirstFile to enumerate the names of all files within the media folder.
@@ -84,11 +148,12 @@ func QueryFirstFile_AsString(folderName *string) string {
 //
 // Response is a FileQueryResponse.
 func (o *Oxtel) QuerySubsequentFile(folderName *string) (FileQueryResponse, error) {
-	if folderName == nil {
-		defaultVal := "$VIDEO"
-		folderName = &defaultVal
-	}
-	val, err := o.sendCommandExpectResponse("R5", *folderName)
+	return o.QuerySubsequentFileContext(context.Background(), folderName)
+}
+
+// QuerySubsequentFileContext is like QuerySubsequentFile but uses ctx for cancellation and deadlines.
+func (o *Oxtel) QuerySubsequentFileContext(ctx context.Context, folderName *string) (FileQueryResponse, error) {
+	val, err := o.EnquireContext(ctx, QuerySubsequentFileCommand{FolderName: folderName})
 	if err != nil {
 		return FileQueryResponse{}, err
 	}
@@ -108,12 +173,55 @@ func (o *Oxtel) QuerySubsequentFile(folderName *string) (FileQueryResponse, erro
 // QuerySubsequentFile_AsString returns the command string used to enumerate the names of all files within the media folder.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a QuerySubsequentFileCommand instead.
 func QuerySubsequentFile_AsString(folderName *string) string {
-	if folderName == nil {
-		defaultVal := "$VIDEO"
-		folderName = &defaultVal
-	}
-	return fmt.Sprintf("R5%s", *folderName)
+	return QuerySubsequentFileCommand{FolderName: folderName}.Encode()
+}
+
+// ListFiles returns the names of every file within the specified folder name alias by issuing QueryFirstFile and then
+// QuerySubsequentFile until the end of the directory is reached. If folderName is nil, the template folder is used.
+//
+// The engine keeps one enumeration per connection, so concurrent calls to ListFiles are serialized. Calls to
+// QueryFirstFile and QuerySubsequentFile made by hand at the same time will still disturb the enumeration.
+func (o *Oxtel) ListFiles(folderName *string) ([]string, error) {
+	return o.ListFilesContext(context.Background(), folderName)
+}
+
+// ListFilesContext is like ListFiles but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ListFilesContext(ctx context.Context, folderName *string) ([]string, error) {
+	o.listMu.Lock()
+	defer o.listMu.Unlock()
+
+	res, err := o.QueryFirstFileContext(ctx, folderName)
+	var names []string
+	for err == nil {
+		if res.Filename != "" {
+			names = append(names, res.Filename)
+		}
+		if res.EndOfDir {
+			return names, nil
+		}
+		res, err = o.QuerySubsequentFileContext(ctx, folderName)
+	}
+	return nil, err
+}
+
+// EnquireExtendedFileInformationCommand is the Enquiry sent by EnquireExtendedFileInformation.
+type EnquireExtendedFileInformationCommand struct {
+	FileName string
+}
+
+func (c EnquireExtendedFileInformationCommand) Encode() string {
+	return fmt.Sprintf("R6%s", c.FileName)
+}
+
+func (c EnquireExtendedFileInformationCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireExtendedFileInformationCommand) ResponsePrefix() string {
+	return "R6"
 }
 
 // EnquireExtendedFileInformation queries for information about the specified template.
@@ -122,7 +230,12 @@ func QuerySubsequentFile_AsString(folderName *string) string {
 //
 // Response is a ExtendedFileInfoResponse. Only "File exists" and "Filename" are valid.
 func (o *Oxtel) EnquireExtendedFileInformation(fileName string) (ExtendedFileInfoResponse, error) {
-	val, err := o.sendCommandExpectResponse("R6", fileName)
+	return o.EnquireExtendedFileInformationContext(context.Background(), fileName)
+}
+
+// EnquireExtendedFileInformationContext is like EnquireExtendedFileInformation but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireExtendedFileInformationContext(ctx context.Context, fileName string) (ExtendedFileInfoResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireExtendedFileInformationCommand{FileName: fileName})
 
 	if err != nil {
 		return ExtendedFileInfoResponse{}, err
@@ -220,8 +333,27 @@ func (o *Oxtel) EnquireExtendedFileInformation(fileName string) (ExtendedFileInf
 // EnquireExtendedFileInformation_AsString returns the command string used to query for information about the specified template.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireExtendedFileInformationCommand instead.
 func EnquireExtendedFileInformation_AsString(fileName string) string {
-	return fmt.Sprintf("R6%s", fileName)
+	return EnquireExtendedFileInformationCommand{FileName: fileName}.Encode()
+}
+
+// ValidateTemplateCommand is the Enquiry sent by ValidateTemplate.
+type ValidateTemplateCommand struct {
+	FileName string
+}
+
+func (c ValidateTemplateCommand) Encode() string {
+	return fmt.Sprintf("RA%s", c.FileName)
+}
+
+func (c ValidateTemplateCommand) Validate() error {
+	return nil
+}
+
+func (c ValidateTemplateCommand) ResponsePrefix() string {
+	return "RA"
 }
 
 // ValidateTemplate validates the presence/absence of the specified template.
@@ -229,7 +361,12 @@ func EnquireExtendedFileInformation_AsString(fileName string) string {
 //
 // Response is a ValidateTemplateResponse. MissingAssets is not supported.
 func (o *Oxtel) ValidateTemplate(fileName string) (ValidateTemplateResponse, error) {
-	val, err := o.sendCommandExpectResponse("RA", fileName)
+	return o.ValidateTemplateContext(context.Background(), fileName)
+}
+
+// ValidateTemplateContext is like ValidateTemplate but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ValidateTemplateContext(ctx context.Context, fileName string) (ValidateTemplateResponse, error) {
+	val, err := o.EnquireContext(ctx, ValidateTemplateCommand{FileName: fileName})
 	if err != nil {
 		return ValidateTemplateResponse{}, err
 	}
@@ -256,35 +393,77 @@ func (o *Oxtel) ValidateTemplate(fileName string) (ValidateTemplateResponse, err
 // ValidateTemplate_AsString returns the command string used to validates the presence/absence of the specified template.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a ValidateTemplateCommand instead.
 func ValidateTemplate_AsString(fileName string) string {
-	return fmt.Sprintf("RA%s", fileName)
+	return ValidateTemplateCommand{FileName: fileName}.Encode()
+}
+
+// EnableMediaTalliesCommand is the Command sent by EnableMediaTallies.
+type EnableMediaTalliesCommand struct {
+	Data MediaTallies
+}
+
+func (c EnableMediaTalliesCommand) Encode() string {
+	return fmt.Sprintf("YB%s", buildMediaTallies(c.Data))
+}
+
+func (c EnableMediaTalliesCommand) Validate() error {
+	return nil
 }
 
 // EnableMediaTallies enables or disables media tallies for the connection on which the command was received.
 //
 // Media tallies are used to track media management as files are added, deleted, or modified on the file system.
 func (o *Oxtel) EnableMediaTallies(data MediaTallies) error {
+	return o.EnableMediaTalliesContext(context.Background(), data)
+}
 
-	mask := buildMediaTallies(data)
-
-	return o.sendCommand(fmt.Sprintf("YB%x", mask))
+// EnableMediaTalliesContext is like EnableMediaTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnableMediaTalliesContext(ctx context.Context, data MediaTallies) error {
+	return o.SendContext(ctx, EnableMediaTalliesCommand{Data: data})
 }
 
 // EnableMediaTallies_AsString returns the command string used to enable or disable media tallies for the connection on which the
 // command was received.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnableMediaTalliesCommand instead.
 func EnableMediaTallies_AsString(data MediaTallies) string {
-	mask := buildMediaTallies(data)
+	return EnableMediaTalliesCommand{Data: data}.Encode()
+}
+
+// EnquireMediaTalliesCommand is the Enquiry sent by EnquireMediaTallies.
+type EnquireMediaTalliesCommand struct{}
 
-	return fmt.Sprintf("YB%x", mask)
+func (c EnquireMediaTalliesCommand) Encode() string {
+	return "YB"
+}
+
+func (c EnquireMediaTalliesCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireMediaTalliesCommand) ResponsePrefix() string {
+	return "YB"
+}
+
+// The response is the six tally flags, which a MediaTally follows with an action and a file name.
+func (c EnquireMediaTalliesCommand) matchesResponse(data string) bool {
+	return len(data) == 6
 }
 
 // EnquireMediaTallies queries the enable/disable state of the media tallies for the connection.
 //
 // Response is MediaTallies. Only Images is supported.
 func (o *Oxtel) EnquireMediaTallies() (MediaTallies, error) {
-	val, err := o.sendCommandExpectResponse("YB", "")
+	return o.EnquireMediaTalliesContext(context.Background())
+}
+
+// EnquireMediaTalliesContext is like EnquireMediaTallies but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireMediaTalliesContext(ctx context.Context) (MediaTallies, error) {
+	val, err := o.EnquireContext(ctx, EnquireMediaTalliesCommand{})
 	if err != nil {
 		return MediaTallies{}, err
 	}
@@ -332,6 +511,8 @@ func (o *Oxtel) EnquireMediaTallies() (MediaTallies, error) {
 // the connection.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireMediaTalliesCommand instead.
 func EnquireMediaTallies_AsString() string {
-	return "YB"
+	return EnquireMediaTalliesCommand{}.Encode()
 }
@@ -1,11 +1,26 @@
 package oxtel
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 )
 
+// LoadImageCommand is the Command sent by LoadImage.
+type LoadImageCommand struct {
+	Layer        OxtelLayer
+	TemplateName string
+}
+
+func (c LoadImageCommand) Encode() string {
+	return fmt.Sprintf("R0%x%s", c.Layer, c.TemplateName)
+}
+
+func (c LoadImageCommand) Validate() error {
+	return nil
+}
+
 // LoadImage loads a template onto the specific layer. If another template is loaded on the specified layer, then the
 // current template will be unloaded before the new template is loaded.
 // If a 'non-existent' filename is specified in the templateName parameter, the layer is unloaded, however the preloaded
@@ -18,17 +33,39 @@ import (
 //
 // An unsolicited response will be sent if tallies are enabled with the format "Y9%l %s" where %l is the layer and %s is the filename.
 func (o *Oxtel) LoadImage(layer OxtelLayer, templateName string) error {
-	msg := fmt.Sprintf("R0%x%s", layer, templateName)
+	return o.LoadImageContext(context.Background(), layer, templateName)
+}
 
-	return o.sendCommand(msg)
+// LoadImageContext is like LoadImage but uses ctx for cancellation and deadlines.
+func (o *Oxtel) LoadImageContext(ctx context.Context, layer OxtelLayer, templateName string) error {
+	return o.SendContext(ctx, LoadImageCommand{Layer: layer, TemplateName: templateName})
 }
 
 // LoadImage_AsString returns the command string used to load a template onto the specific layer. If another template is
 // loaded on the specified layer, then the current template will be unloaded before the new template is loaded.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a LoadImageCommand instead.
 func LoadImage_AsString(layer OxtelLayer, templateName string) string {
-	return fmt.Sprintf("R0%x%s", layer, templateName)
+	return LoadImageCommand{Layer: layer, TemplateName: templateName}.Encode()
+}
+
+// EnquireLoadImageCommand is the Enquiry sent by EnquireLoadImage.
+type EnquireLoadImageCommand struct {
+	Layer OxtelLayer
+}
+
+func (c EnquireLoadImageCommand) Encode() string {
+	return fmt.Sprintf("R0%x", c.Layer)
+}
+
+func (c EnquireLoadImageCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireLoadImageCommand) ResponsePrefix() string {
+	return "R0"
 }
 
 // EnquireLoadImage queries the template file that is currently loaded into the specified layer.
@@ -38,7 +75,12 @@ func LoadImage_AsString(layer OxtelLayer, templateName string) string {
 //
 // Response is a LayerTemplateResponse.
 func (o *Oxtel) EnquireLoadImage(layer OxtelLayer) (LayerTemplateResponse, error) {
-	val, err := o.sendCommandExpectResponse("R0", fmt.Sprintf("%x", layer))
+	return o.EnquireLoadImageContext(context.Background(), layer)
+}
+
+// EnquireLoadImageContext is like EnquireLoadImage but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireLoadImageContext(ctx context.Context, layer OxtelLayer) (LayerTemplateResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireLoadImageCommand{Layer: layer})
 	if err != nil {
 		return LayerTemplateResponse{}, err
 	}
@@ -58,8 +100,24 @@ func (o *Oxtel) EnquireLoadImage(layer OxtelLayer) (LayerTemplateResponse, error
 // the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireLoadImageCommand instead.
 func EnquireLoadImage_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("R0%x", layer)
+	return EnquireLoadImageCommand{Layer: layer}.Encode()
+}
+
+// PreloadImageCommand is the Command sent by PreloadImage.
+type PreloadImageCommand struct {
+	Layer        OxtelLayer
+	TemplateName string
+}
+
+func (c PreloadImageCommand) Encode() string {
+	return fmt.Sprintf("R7%x%s", c.Layer, c.TemplateName)
+}
+
+func (c PreloadImageCommand) Validate() error {
+	return nil
 }
 
 // PreloadImage preloads a template on the specified layer. Once the preload is complete, the template can be swapped with
@@ -75,24 +133,51 @@ func EnquireLoadImage_AsString(layer OxtelLayer) string {
 //
 // An unsolicited response will be sent if tallies are enabled with the format "YA%l %s" where %l is the layer and %s is the filename.
 func (o *Oxtel) PreloadImage(layer OxtelLayer, templateName string) error {
-	msg := fmt.Sprintf("R7%x%s", layer, templateName)
+	return o.PreloadImageContext(context.Background(), layer, templateName)
+}
 
-	return o.sendCommand(msg)
+// PreloadImageContext is like PreloadImage but uses ctx for cancellation and deadlines.
+func (o *Oxtel) PreloadImageContext(ctx context.Context, layer OxtelLayer, templateName string) error {
+	return o.SendContext(ctx, PreloadImageCommand{Layer: layer, TemplateName: templateName})
 }
 
 // PreloadImage_AsString returns the command string used to preload a template on the specified layer. Once the preload is
 // complete, the template can be swapped with the on-air template in a frame-accurate manner.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a PreloadImageCommand instead.
 func PreloadImage_AsString(layer OxtelLayer, templateName string) string {
-	return fmt.Sprintf("R7%x%s", layer, templateName)
+	return PreloadImageCommand{Layer: layer, TemplateName: templateName}.Encode()
+}
+
+// EnquirePreloadImageCommand is the Enquiry sent by EnquirePreloadImage.
+type EnquirePreloadImageCommand struct {
+	Layer OxtelLayer
+}
+
+func (c EnquirePreloadImageCommand) Encode() string {
+	return fmt.Sprintf("R7%x", c.Layer)
+}
+
+func (c EnquirePreloadImageCommand) Validate() error {
+	return nil
+}
+
+func (c EnquirePreloadImageCommand) ResponsePrefix() string {
+	return "R7"
 }
 
 // EnquirePreloadImage queries the template file that is currently preloaded into the specified layer.
 //
 // Response is a LayerTemplateResponse.
 func (o *Oxtel) EnquirePreloadImage(layer OxtelLayer) (LayerTemplateResponse, error) {
-	val, err := o.sendCommandExpectResponse("R7", fmt.Sprintf("%x", layer))
+	return o.EnquirePreloadImageContext(context.Background(), layer)
+}
+
+// EnquirePreloadImageContext is like EnquirePreloadImage but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquirePreloadImageContext(ctx context.Context, layer OxtelLayer) (LayerTemplateResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquirePreloadImageCommand{Layer: layer})
 	if err != nil {
 		return LayerTemplateResponse{}, err
 	}
@@ -112,47 +197,110 @@ func (o *Oxtel) EnquirePreloadImage(layer OxtelLayer) (LayerTemplateResponse, er
 // into the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquirePreloadImageCommand instead.
 func EnquirePreloadImage_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("R7%x", layer)
+	return EnquirePreloadImageCommand{Layer: layer}.Encode()
+}
+
+// EraseStoreCommand is the Command sent by EraseStore.
+type EraseStoreCommand struct {
+	Layer OxtelLayer
+}
+
+func (c EraseStoreCommand) Encode() string {
+	return fmt.Sprintf("A%x", c.Layer)
+}
+
+func (c EraseStoreCommand) Validate() error {
+	return nil
 }
 
 // EraseStore unloads the template from the specified layer.
 func (o *Oxtel) EraseStore(layer OxtelLayer) error {
-	msg := fmt.Sprintf("A%x", layer)
+	return o.EraseStoreContext(context.Background(), layer)
+}
 
-	return o.sendCommand(msg)
+// EraseStoreContext is like EraseStore but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EraseStoreContext(ctx context.Context, layer OxtelLayer) error {
+	return o.SendContext(ctx, EraseStoreCommand{Layer: layer})
 }
 
 // EraseStore_AsString returns the command string used to unload the template from the specified layer.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EraseStoreCommand instead.
 func EraseStore_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("A%x", layer)
+	return EraseStoreCommand{Layer: layer}.Encode()
+}
+
+// SetImagePositionCommand is the Command sent by SetImagePosition.
+type SetImagePositionCommand struct {
+	Layer   OxtelLayer
+	XOffset int16
+	YOffset int16
+}
+
+func (c SetImagePositionCommand) Encode() string {
+	return fmt.Sprintf("G%x %x %x", c.Layer, c.XOffset, c.YOffset)
+}
+
+func (c SetImagePositionCommand) Validate() error {
+	return nil
 }
 
 // SetImagePosition sets the position of the loaded template relative to the origin. The origin (x=0, y=0) is defined in
 // the upper left-hand corner of the screen.
 //
-// Positive values move the template right and down. Negative values move the template left and up.
-func (o *Oxtel) SetImagePosition(layer OxtelLayer, xOffset uint16, yOffset uint16) error {
-	msg := fmt.Sprintf("G%x %x %x", layer, xOffset, yOffset)
+// Positive values move the template right and down. Negative values move the template left and up. Offsets are sent
+// in hexadecimal, with a leading '-' when negative.
+func (o *Oxtel) SetImagePosition(layer OxtelLayer, xOffset int16, yOffset int16) error {
+	return o.SetImagePositionContext(context.Background(), layer, xOffset, yOffset)
+}
 
-	return o.sendCommand(msg)
+// SetImagePositionContext is like SetImagePosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) SetImagePositionContext(ctx context.Context, layer OxtelLayer, xOffset int16, yOffset int16) error {
+	return o.SendContext(ctx, SetImagePositionCommand{Layer: layer, XOffset: xOffset, YOffset: yOffset})
 }
 
 // SetImagePosition_AsString returns the command string used to set the position of the loaded template relative to the origin.
 // The origin (x=0, y=0) is defined in the upper left-hand corner of the screen.
 //
 // For use with scheduled commands.
-func SetImagePosition_AsString(layer OxtelLayer, xOffset uint16, yOffset uint16) string {
-	return fmt.Sprintf("G%x %x %x", layer, xOffset, yOffset)
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a SetImagePositionCommand instead.
+func SetImagePosition_AsString(layer OxtelLayer, xOffset int16, yOffset int16) string {
+	return SetImagePositionCommand{Layer: layer, XOffset: xOffset, YOffset: yOffset}.Encode()
+}
+
+// EnquireImagePositionCommand is the Enquiry sent by EnquireImagePosition.
+type EnquireImagePositionCommand struct {
+	Layer OxtelLayer
+}
+
+func (c EnquireImagePositionCommand) Encode() string {
+	return fmt.Sprintf("G%x", c.Layer)
+}
+
+func (c EnquireImagePositionCommand) Validate() error {
+	return nil
+}
+
+func (c EnquireImagePositionCommand) ResponsePrefix() string {
+	return "G"
 }
 
 // EnquireImagePosition submits an inquiry on the position of the loaded template relative to the origin.
 //
 // Response is a ImagePositionResponse with units of pixels.
 func (o *Oxtel) EnquireImagePosition(layer OxtelLayer) (ImagePositionResponse, error) {
-	val, err := o.sendCommandExpectResponse("G", fmt.Sprintf("%x", layer))
+	return o.EnquireImagePositionContext(context.Background(), layer)
+}
+
+// EnquireImagePositionContext is like EnquireImagePosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) EnquireImagePositionContext(ctx context.Context, layer OxtelLayer) (ImagePositionResponse, error) {
+	val, err := o.EnquireContext(ctx, EnquireImagePositionCommand{Layer: layer})
 	if err != nil {
 		return ImagePositionResponse{}, err
 	}
@@ -186,6 +334,8 @@ func (o *Oxtel) EnquireImagePosition(layer OxtelLayer) (ImagePositionResponse, e
 // relative to the origin.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an EnquireImagePositionCommand instead.
 func EnquireImagePosition_AsString(layer OxtelLayer) string {
-	return fmt.Sprintf("G%x", layer)
+	return EnquireImagePositionCommand{Layer: layer}.Encode()
 }
@@ -0,0 +1,220 @@
+package oxtel
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Timecode is a time of day in hours, minutes, seconds and frames, as used by the scheduler.
+//
+// FieldRate sets the frame base: 25 frames per second at OXTEL_FIELD_RATE_50 and 30 at OXTEL_FIELD_RATE_5994 and
+// OXTEL_FIELD_RATE_60. At OXTEL_FIELD_RATE_5994 the timecode is drop-frame: frames 0 and 1 are skipped at the start of
+// every minute that is not a multiple of ten, so that the timecode keeps up with the clock. A zero FieldRate counts
+// frames like OXTEL_FIELD_RATE_60.
+//
+// Progressive is set for the progressive video standards, which have a frame for every field and so double the frame
+// base: 50 frames per second at OXTEL_FIELD_RATE_50 and 60 otherwise, with frames 0 to 3 skipped at
+// OXTEL_FIELD_RATE_5994.
+//
+// Timecodes are times of day, so arithmetic wraps around at midnight.
+type Timecode struct {
+	Hours       uint8
+	Minutes     uint8
+	Seconds     uint8
+	Frames      uint8
+	FieldRate   OxtelFieldRate
+	Progressive bool
+}
+
+// ParseTimecode parses a timecode formatted as HH:MM:SS:FF at the given field rate. A semicolon is also accepted before
+// the frames, as is usual for drop-frame timecode.
+func ParseTimecode(s string, rate OxtelFieldRate, progressive bool) (Timecode, error) {
+	invalid := &InvalidTimecodeError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf("Timecode %q is not formatted as HH:MM:SS:FF", s),
+		},
+	}
+	if len(s) != 11 || s[2] != ':' || s[5] != ':' || (s[8] != ':' && s[8] != ';') {
+		return Timecode{}, invalid
+	}
+
+	var fields [4]uint8
+	for i := range fields {
+		hi, lo := s[3*i], s[3*i+1]
+		if hi < '0' || hi > '9' || lo < '0' || lo > '9' {
+			return Timecode{}, invalid
+		}
+		fields[i] = (hi-'0')*10 + lo - '0'
+	}
+
+	tc := Timecode{Hours: fields[0], Minutes: fields[1], Seconds: fields[2], Frames: fields[3], FieldRate: rate, Progressive: progressive}
+	if err := tc.Validate(); err != nil {
+		return Timecode{}, err
+	}
+	return tc, nil
+}
+
+// TimecodeFromFrames returns the timecode n frames after midnight at the given field rate. n wraps around at midnight
+// and may be negative.
+func TimecodeFromFrames(n int, rate OxtelFieldRate, progressive bool) Timecode {
+	fps, drop := frameBase(rate, progressive)
+	day := framesPerDay(rate, progressive)
+	frame := n % day
+	if frame < 0 {
+		frame += day
+	}
+
+	// Put the skipped frame numbers back before splitting the count into fields.
+	if drop > 0 {
+		per10 := 600*fps - 9*drop
+		perMinute := 60*fps - drop
+		d, m := frame/per10, frame%per10
+		frame += 9 * drop * d
+		if m >= drop {
+			frame += drop * ((m - drop) / perMinute)
+		}
+	}
+
+	return Timecode{
+		Hours:       uint8(frame / fps / 3600),
+		Minutes:     uint8(frame / fps / 60 % 60),
+		Seconds:     uint8(frame / fps % 60),
+		Frames:      uint8(frame % fps),
+		FieldRate:   rate,
+		Progressive: progressive,
+	}
+}
+
+// String returns the timecode as HH:MM:SS:FF.
+func (tc Timecode) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", tc.Hours, tc.Minutes, tc.Seconds, tc.Frames)
+}
+
+// Validate reports whether every field of the timecode is in range. Frames are checked against the frame base of the
+// field rate, which is 30 frames per second if FieldRate is zero, or 60 if the timecode is also Progressive, as for the
+// arithmetic.
+func (tc Timecode) Validate() error {
+	fps, drop := frameBase(tc.FieldRate, tc.Progressive)
+	if tc.Hours > 23 || tc.Minutes > 59 || tc.Seconds > 59 || int(tc.Frames) >= fps {
+		return &InvalidTimecodeError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Timecode %s is out of range", tc),
+			},
+		}
+	}
+
+	if tc.Seconds == 0 && tc.Minutes%10 != 0 && int(tc.Frames) < drop {
+		return &InvalidTimecodeError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Timecode %s is skipped by drop-frame timecode", tc),
+			},
+		}
+	}
+
+	return nil
+}
+
+// FrameCount returns the number of frames since midnight.
+func (tc Timecode) FrameCount() int {
+	fps, drop := frameBase(tc.FieldRate, tc.Progressive)
+	minutes := 60*int(tc.Hours) + int(tc.Minutes)
+	return (minutes*60+int(tc.Seconds))*fps + int(tc.Frames) - drop*(minutes-minutes/10)
+}
+
+// AddFrames returns the timecode n frames later, or earlier if n is negative.
+func (tc Timecode) AddFrames(n int) Timecode {
+	return TimecodeFromFrames(tc.FrameCount()+n, tc.FieldRate, tc.Progressive)
+}
+
+// Add returns the timecode d later, or earlier if d is negative, rounded to the nearest frame.
+func (tc Timecode) Add(d time.Duration) Timecode {
+	return tc.AddFrames(framesIn(d, tc.FieldRate, tc.Progressive))
+}
+
+// Sub returns the duration tc-u of two timecodes on the same day.
+func (tc Timecode) Sub(u Timecode) time.Duration {
+	return durationOf(tc.FrameCount()-u.FrameCount(), tc.FieldRate, tc.Progressive)
+}
+
+// Until returns the time from tc to the next occurrence of u, which is on the following day if u is before tc.
+func (tc Timecode) Until(u Timecode) time.Duration {
+	day := framesPerDay(tc.FieldRate, tc.Progressive)
+	n := (u.FrameCount() - tc.FrameCount()) % day
+	if n < 0 {
+		n += day
+	}
+	return durationOf(n, tc.FieldRate, tc.Progressive)
+}
+
+// Compare returns -1 if tc is before u, 1 if tc is after u and 0 if they are the same time of day. The field rate is
+// ignored, so both timecodes should count frames the same way.
+func (tc Timecode) Compare(u Timecode) int {
+	a := [4]uint8{tc.Hours, tc.Minutes, tc.Seconds, tc.Frames}
+	b := [4]uint8{u.Hours, u.Minutes, u.Seconds, u.Frames}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Before reports whether tc is earlier in the day than u.
+func (tc Timecode) Before(u Timecode) bool {
+	return tc.Compare(u) < 0
+}
+
+// After reports whether tc is later in the day than u.
+func (tc Timecode) After(u Timecode) bool {
+	return tc.Compare(u) > 0
+}
+
+// frameBase returns the number of frames per second and the number of frame numbers dropped at the start of each
+// minute.
+func frameBase(rate OxtelFieldRate, progressive bool) (fps int, drop int) {
+	fps = 30
+	switch rate {
+	case OXTEL_FIELD_RATE_50:
+		fps = 25
+	case OXTEL_FIELD_RATE_5994:
+		drop = 2
+	}
+	if progressive {
+		return 2 * fps, 2 * drop
+	}
+	return fps, drop
+}
+
+func framesPerDay(rate OxtelFieldRate, progressive bool) int {
+	fps, drop := frameBase(rate, progressive)
+	return 144 * (600*fps - 9*drop)
+}
+
+// frameDuration returns the length of a frame as a fraction of a second.
+func frameDuration(rate OxtelFieldRate, progressive bool) (num int64, den int64) {
+	num, den = 1, 30
+	switch rate {
+	case OXTEL_FIELD_RATE_50:
+		den = 25
+	case OXTEL_FIELD_RATE_5994:
+		num, den = 1001, 30000
+	}
+	if progressive {
+		den *= 2
+	}
+	return num, den
+}
+
+func framesIn(d time.Duration, rate OxtelFieldRate, progressive bool) int {
+	num, den := frameDuration(rate, progressive)
+	return int(math.Round(d.Seconds() * float64(den) / float64(num)))
+}
+
+func durationOf(frames int, rate OxtelFieldRate, progressive bool) time.Duration {
+	num, den := frameDuration(rate, progressive)
+	return time.Duration(int64(frames) * int64(time.Second) * num / den)
+}
@@ -0,0 +1,307 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+func TestParseTimecode(t *testing.T) {
+	tc, err := ParseTimecode("10:09:00;02", OXTEL_FIELD_RATE_5994, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Timecode{Hours: 10, Minutes: 9, Frames: 2, FieldRate: OXTEL_FIELD_RATE_5994}); tc != want {
+		t.Fatalf("got %#v, want %#v", tc, want)
+	}
+	if tc.String() != "10:09:00:02" {
+		t.Fatalf("formatted as %s", tc)
+	}
+
+	for _, s := range []string{"", "10:09:00", "10:09:00:2", "10-09-00-02", "1a:09:00:02", "24:00:00:00", "10:09:00:25", "10:09:00:01"} {
+		_, err := ParseTimecode(s, OXTEL_FIELD_RATE_5994, false)
+		if s == "10:09:00:25" {
+			_, err = ParseTimecode(s, OXTEL_FIELD_RATE_50, false)
+		}
+		if _, ok := err.(*InvalidTimecodeError); !ok {
+			t.Errorf("%q: expected an InvalidTimecodeError, got %v", s, err)
+		}
+	}
+
+	// Progressive standards count every frame, and 59.94 Hz drops four frame numbers a minute.
+	for _, c := range []struct {
+		s    string
+		rate OxtelFieldRate
+		ok   bool
+	}{
+		{"10:09:00:49", OXTEL_FIELD_RATE_50, true},
+		{"10:09:00:50", OXTEL_FIELD_RATE_50, false},
+		{"10:09:00;59", OXTEL_FIELD_RATE_5994, true},
+		{"10:09:00;60", OXTEL_FIELD_RATE_5994, false},
+		{"10:09:00;03", OXTEL_FIELD_RATE_5994, false},
+		{"10:09:00;04", OXTEL_FIELD_RATE_5994, true},
+		{"10:10:00;00", OXTEL_FIELD_RATE_5994, true},
+	} {
+		_, err := ParseTimecode(c.s, c.rate, true)
+		if _, invalid := err.(*InvalidTimecodeError); invalid == c.ok {
+			t.Errorf("%q at rate %d progressive: got %v", c.s, c.rate, err)
+		}
+	}
+}
+
+func TestTimecodeFrames(t *testing.T) {
+	for _, rate := range []OxtelFieldRate{OXTEL_FIELD_RATE_50, OXTEL_FIELD_RATE_5994, OXTEL_FIELD_RATE_60} {
+		for _, progressive := range []bool{false, true} {
+			var prev Timecode
+			for n := 0; n < framesPerDay(rate, progressive); n++ {
+				tc := TimecodeFromFrames(n, rate, progressive)
+				if err := tc.Validate(); err != nil {
+					t.Fatalf("frame %d at rate %d, progressive %t: %v", n, rate, progressive, err)
+				}
+				if tc.FrameCount() != n {
+					t.Fatalf("frame %d at rate %d, progressive %t is %s, which counts as %d", n, rate, progressive, tc, tc.FrameCount())
+				}
+				if n > 0 && !prev.Before(tc) {
+					t.Fatalf("%s is not before %s", prev, tc)
+				}
+				prev = tc
+			}
+		}
+	}
+}
+
+func TestValidateZeroFieldRate(t *testing.T) {
+	// A zero FieldRate counts 30 frames a second, so frames 30 to 59 would not survive AddFrames.
+	for f := uint8(30); f < 60; f++ {
+		tc := Timecode{Hours: 10, Frames: f}
+		if _, ok := tc.Validate().(*InvalidTimecodeError); !ok {
+			t.Errorf("%s: expected an InvalidTimecodeError", tc)
+		}
+	}
+	if err := (Timecode{Hours: 10, Frames: 29}).Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := (Timecode{Hours: 10, Frames: 59, Progressive: true}).Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTimecodeArithmetic(t *testing.T) {
+	df := func(h, m, s, f uint8) Timecode {
+		return Timecode{Hours: h, Minutes: m, Seconds: s, Frames: f, FieldRate: OXTEL_FIELD_RATE_5994}
+	}
+	pdf := func(h, m, s, f uint8) Timecode {
+		return Timecode{Hours: h, Minutes: m, Seconds: s, Frames: f, FieldRate: OXTEL_FIELD_RATE_5994, Progressive: true}
+	}
+	p50 := func(h, m, s, f uint8) Timecode {
+		return Timecode{Hours: h, Minutes: m, Seconds: s, Frames: f, FieldRate: OXTEL_FIELD_RATE_50, Progressive: true}
+	}
+
+	for _, c := range []struct {
+		tc   Timecode
+		n    int
+		want Timecode
+	}{
+		{df(0, 0, 59, 29), 1, df(0, 1, 0, 2)},
+		{df(0, 9, 59, 29), 1, df(0, 10, 0, 0)},
+		{df(0, 1, 0, 2), -1, df(0, 0, 59, 29)},
+		{df(23, 59, 59, 29), 1, df(0, 0, 0, 0)},
+		{df(0, 0, 0, 0), -1, df(23, 59, 59, 29)},
+		{Timecode{Hours: 10, Seconds: 59, Frames: 24, FieldRate: OXTEL_FIELD_RATE_50}, 1, Timecode{Hours: 10, Minutes: 1, FieldRate: OXTEL_FIELD_RATE_50}},
+		{p50(10, 0, 59, 49), 1, p50(10, 1, 0, 0)},
+		{p50(10, 0, 0, 0), -1, p50(9, 59, 59, 49)},
+		{pdf(0, 0, 59, 59), 1, pdf(0, 1, 0, 4)},
+		{pdf(0, 9, 59, 59), 1, pdf(0, 10, 0, 0)},
+		{pdf(0, 1, 0, 4), -1, pdf(0, 0, 59, 59)},
+		{pdf(23, 59, 59, 59), 1, pdf(0, 0, 0, 0)},
+	} {
+		if got := c.tc.AddFrames(c.n); got != c.want {
+			t.Errorf("%s + %d frames = %s, want %s", c.tc, c.n, got, c.want)
+		}
+	}
+
+	if got := df(10, 0, 0, 0).Add(time.Hour); got != df(11, 0, 0, 0) {
+		t.Errorf("an hour after 10:00:00;00 is %s", got)
+	}
+	if got := df(10, 0, 0, 0).Add(-time.Hour); got != df(9, 0, 0, 0) {
+		t.Errorf("an hour before 10:00:00;00 is %s", got)
+	}
+	// Drop-frame timecode runs 3.6ms an hour slow.
+	if d := df(11, 0, 0, 0).Sub(df(10, 0, 0, 0)); d != time.Hour-3600*time.Microsecond {
+		t.Errorf("11:00:00;00 - 10:00:00;00 = %s", d)
+	}
+	if d := df(23, 0, 0, 0).Until(df(1, 0, 0, 0)); d != 2*time.Hour-7200*time.Microsecond {
+		t.Errorf("23:00:00;00 until 01:00:00;00 = %s", d)
+	}
+	if got := pdf(10, 0, 0, 0).Add(time.Hour); got != pdf(11, 0, 0, 0) {
+		t.Errorf("an hour after 10:00:00;00 progressive is %s", got)
+	}
+	if d := pdf(11, 0, 0, 0).Sub(pdf(10, 0, 0, 0)); d != time.Hour-3600*time.Microsecond {
+		t.Errorf("11:00:00;00 - 10:00:00;00 progressive = %s", d)
+	}
+	if d := p50(10, 0, 1, 0).Sub(p50(10, 0, 0, 25)); d != 500*time.Millisecond {
+		t.Errorf("10:00:01:00 - 10:00:00:25 progressive = %s", d)
+	}
+	if df(1, 0, 0, 0).Compare(Timecode{Hours: 1}) != 0 || !df(0, 59, 59, 29).Before(df(1, 0, 0, 0)) || !df(1, 0, 0, 1).After(df(1, 0, 0, 0)) {
+		t.Error("unexpected comparison")
+	}
+}
+
+func TestEnquireCurrentTime(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetTime(10, 0, 0, 0)
+	tc, err := client.EnquireCurrentTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc.FieldRate != OXTEL_FIELD_RATE_5994 || tc.Hours != 10 || tc.Minutes != 0 {
+		t.Fatalf("unexpected time %#v", tc)
+	}
+
+	if err := client.AddScheduledCommand(tc.Add(time.Hour), CutToB_AsString()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if scheduled := srv.ScheduledCommands(); len(scheduled) != 1 || scheduled[0][:6] != "110000" {
+		t.Fatalf("unexpected scheduled commands %q", scheduled)
+	}
+}
+
+func TestScheduleOnAir(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	srv.SetTime(10, 0, 0, 0)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+	// 7 fields round up to 4 frames.
+	if err := client.ScheduleOnAir(Timecode{Hours: 11}, CutToBCommand{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Latencies are cached until the next connect.
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 0)
+	if err := client.ScheduleOnAir(Timecode{Hours: 11, Minutes: 30}, CutToACommand{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Half a minute ago is more than 23:59 ahead.
+	err := client.ScheduleOnAir(Timecode{Hours: 9, Minutes: 59, Seconds: 30}, CutToACommand{})
+	if _, ok := err.(*ScheduleHorizonError); !ok {
+		t.Fatalf("expected a ScheduleHorizonError for a time that has just passed, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	scheduled := srv.ScheduledCommands()
+	if len(scheduled) != 2 || scheduled[0] != "10595926;U1" || scheduled[1] != "11295926;U0" {
+		t.Fatalf("unexpected scheduled commands %q", scheduled)
+	}
+}
+
+func TestScheduleOnAirFieldRate(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	// PAL counts 25 frames a second, so 7 fields are 4 frames before 11:00:00:00 at 10:59:59:21.
+	srv.SetVideoStandard(uint8(OXTEL_VIDEO_STANDARD_PAL))
+	srv.SetTime(10, 0, 0, 0)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+	if err := client.ScheduleOnAir(Timecode{Hours: 11}, CutToBCommand{}); err != nil {
+		t.Fatal(err)
+	}
+	err := client.ScheduleOnAir(Timecode{Hours: 11, FieldRate: OXTEL_FIELD_RATE_5994}, CutToBCommand{})
+	if _, ok := err.(*InvalidTimecodeError); !ok {
+		t.Fatalf("expected an InvalidTimecodeError for a 59.94 Hz timecode, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if scheduled := srv.ScheduledCommands(); len(scheduled) != 1 || scheduled[0] != "10595921;U1" {
+		t.Fatalf("unexpected scheduled commands %q", scheduled)
+	}
+}
+
+func TestScheduleOnAirProgressive(t *testing.T) {
+	for _, c := range []struct {
+		standard OxtelVideoStandard
+		rate     OxtelFieldRate
+		want     string
+	}{
+		// Every field is a frame, so 7 fields are 7 frames before 11:00:00:00.
+		{OXTEL_VIDEO_STANDARD_1080P_50, OXTEL_FIELD_RATE_50, "10595943;U1"},
+		{OXTEL_VIDEO_STANDARD_1080P_5994, OXTEL_FIELD_RATE_5994, "10595953;U1"},
+	} {
+		srv := oxteltest.NewServer()
+		defer srv.Close()
+
+		client := NewOxtel(srv.Host(), srv.Port())
+		if err := client.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		defer client.Disconnect()
+
+		srv.SetVideoStandard(uint8(c.standard))
+		srv.SetTime(10, 0, 0, 0)
+		srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+		srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+		now, err := client.EnquireCurrentTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if now.FieldRate != c.rate || !now.Progressive {
+			t.Fatalf("standard %d: unexpected time %#v", c.standard, now)
+		}
+		if err := now.Validate(); err != nil {
+			t.Fatalf("standard %d: %v", c.standard, err)
+		}
+
+		if err := client.ScheduleOnAir(Timecode{Hours: 11}, CutToBCommand{}); err != nil {
+			t.Fatal(err)
+		}
+		err = client.ScheduleOnAir(Timecode{Hours: 11, FieldRate: c.rate}, CutToBCommand{})
+		if _, ok := err.(*InvalidTimecodeError); !ok {
+			t.Fatalf("standard %d: expected an InvalidTimecodeError for an interlaced timecode, got %v", c.standard, err)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for len(srv.ScheduledCommands()) < 1 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if scheduled := srv.ScheduledCommands(); len(scheduled) != 1 || scheduled[0] != c.want {
+			t.Fatalf("standard %d: unexpected scheduled commands %q", c.standard, scheduled)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package oxtel
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// ImageTween moves the template on a layer from one position to another over a number of frames.
+type ImageTween struct {
+	Layer        OxtelLayer
+	FromX, FromY int16
+	ToX, ToY     int16
+
+	// Frames is the length of the move. The template is at the From position on the first frame and at the To
+	// position Frames frames later.
+	Frames int
+	Easing OxtelEasing
+}
+
+func (t ImageTween) Validate() error {
+	if t.Frames < 0 {
+		return &InvalidDurationError{
+			BaseError: BaseError{
+				Message: "Frames must not be negative",
+			},
+		}
+	}
+	if t.Easing > OXTEL_EASING_EASE_IN_OUT {
+		return &InvalidParametersError{
+			BaseError: BaseError{
+				Message: fmt.Sprintf("Unknown easing %d", t.Easing),
+			},
+		}
+	}
+	return nil
+}
+
+// Positions returns the SetImagePosition command for every frame of the move, Frames+1 in all.
+func (t ImageTween) Positions() []SetImagePositionCommand {
+	positions := make([]SetImagePositionCommand, 0, t.Frames+1)
+	for frame := 0; frame <= t.Frames; frame++ {
+		progress := 1.0
+		if t.Frames > 0 {
+			progress = ease(t.Easing, float64(frame)/float64(t.Frames))
+		}
+		positions = append(positions, SetImagePositionCommand{
+			Layer:   t.Layer,
+			XOffset: interpolate(t.FromX, t.ToX, progress),
+			YOffset: interpolate(t.FromY, t.ToY, progress),
+		})
+	}
+	return positions
+}
+
+// Commands returns the scheduled commands that make the move, the first recognized at start and each following one
+// a frame later.
+func (t ImageTween) Commands(start Timecode) []Command {
+	positions := t.Positions()
+	cmds := make([]Command, len(positions))
+	for i, position := range positions {
+		cmds[i] = ScheduledCommand{Time: start.AddFrames(i), Command: position}
+	}
+	return cmds
+}
+
+func ease(easing OxtelEasing, t float64) float64 {
+	switch easing {
+	case OXTEL_EASING_EASE_IN:
+		return t * t
+	case OXTEL_EASING_EASE_OUT:
+		return 1 - (1-t)*(1-t)
+	case OXTEL_EASING_EASE_IN_OUT:
+		if t < 0.5 {
+			return 2 * t * t
+		}
+		return 1 - 2*(1-t)*(1-t)
+	}
+	return t
+}
+
+func interpolate(from int16, to int16, progress float64) int16 {
+	return int16(math.Round(float64(from) + (float64(to)-float64(from))*progress))
+}
+
+// TweenImagePosition moves the template on a layer as described by tween, starting on air at start. A
+// SetImagePosition command is scheduled for every frame with AddScheduledCommand, so the move is timed by the engine
+// rather than the client.
+//
+// The latencies and the frame rate of the engine are taken into account as by ScheduleOnAir, and a zero FieldRate in
+// start is set from the engine clock. Every command is sent in a single write.
+func (o *Oxtel) TweenImagePosition(start Timecode, tween ImageTween) error {
+	return o.TweenImagePositionContext(context.Background(), start, tween)
+}
+
+// TweenImagePositionContext is like TweenImagePosition but uses ctx for cancellation and deadlines.
+func (o *Oxtel) TweenImagePositionContext(ctx context.Context, start Timecode, tween ImageTween) error {
+	if err := tween.Validate(); err != nil {
+		return err
+	}
+
+	recognized, _, err := o.recognitionTimeContext(ctx, start)
+	if err != nil {
+		return err
+	}
+	return o.SendBatchContext(ctx, tween.Commands(recognized)...)
+}
@@ -0,0 +1,65 @@
+package oxtel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImageTweenPositions(t *testing.T) {
+	linear := ImageTween{Layer: 1, FromX: 0, FromY: 100, ToX: -100, ToY: 0, Frames: 4}.Positions()
+	if len(linear) != 5 {
+		t.Fatalf("got %d positions, want 5", len(linear))
+	}
+	if p := linear[2]; p.XOffset != -50 || p.YOffset != 50 {
+		t.Errorf("halfway position is %+v", p)
+	}
+	if p := linear[4]; p.XOffset != -100 || p.YOffset != 0 {
+		t.Errorf("last position is %+v", p)
+	}
+
+	eased := ImageTween{ToX: 100, Frames: 4, Easing: OXTEL_EASING_EASE_IN_OUT}.Positions()
+	if eased[1].XOffset >= 25 || eased[2].XOffset != 50 || eased[3].XOffset <= 75 {
+		t.Errorf("eased positions are %+v", eased)
+	}
+
+	if jump := (ImageTween{ToX: 7, ToY: -7}).Positions(); len(jump) != 1 || jump[0].XOffset != 7 || jump[0].YOffset != -7 {
+		t.Errorf("a move over no frames is %+v", jump)
+	}
+	if err := (ImageTween{Frames: -1}).Validate(); err == nil {
+		t.Error("expected a negative length to be rejected")
+	}
+}
+
+func TestTweenImagePosition(t *testing.T) {
+	srv, client := connectTallyClient(t)
+	srv.SetTime(10, 0, 0, 0)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_OXTEL), 3)
+	srv.SetLatency(uint8(OXTEL_LATENCY_SOURCE_MCS), 4)
+
+	tween := ImageTween{Layer: 1, FromX: 0, FromY: 0, ToX: -100, ToY: 40, Frames: 10, Easing: OXTEL_EASING_EASE_OUT}
+	if err := client.TweenImagePosition(Timecode{Hours: 10, Seconds: 1}, tween); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.ScheduledCommands()) < 11 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	scheduled := srv.ScheduledCommands()
+	if len(scheduled) != 11 {
+		t.Fatalf("got %d scheduled commands, want 11", len(scheduled))
+	}
+	// 7 fields round up to 4 frames before the first frame on air.
+	if scheduled[0] != "10000026;G1 0 0" || scheduled[10] != "10000106;G1 -64 28" {
+		t.Errorf("unexpected scheduled commands %q", scheduled)
+	}
+
+	for time.Now().Before(deadline.Add(time.Second)) {
+		if x, y := srv.ImagePosition(1); x == -100 && y == 40 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	x, y := srv.ImagePosition(1)
+	t.Errorf("layer 1 ended at %d, %d", x, y)
+}
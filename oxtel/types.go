@@ -122,28 +122,9 @@ type AudioABFollowVideoABResponse struct {
 	Enabled bool
 }
 
-type ChannelMask struct {
-	Channel1  bool
-	Channel2  bool
-	Channel3  bool
-	Channel4  bool
-	Channel5  bool
-	Channel6  bool
-	Channel7  bool
-	Channel8  bool
-	Channel9  bool
-	Channel10 bool
-	Channel11 bool
-	Channel12 bool
-	Channel13 bool
-	Channel14 bool
-	Channel15 bool
-	Channel16 bool
-}
-
 type AudioGainResponse struct {
 	Source      OxtelAudioSource
-	ChannelMask string
+	ChannelMask ChannelMask
 	Gain        int8
 }
 
@@ -200,14 +181,6 @@ type FullVersionNumberResponse struct {
 	AsString    string
 }
 
-type CurrentTimeResponse struct {
-	FieldRate OxtelFieldRate
-	Hours     uint8
-	Minutes   uint8
-	Seconds   uint8
-	Frames    uint8
-}
-
 type LocksResponse struct {
 	Mixer  bool
 	Layer0 bool
@@ -0,0 +1,379 @@
+package oxtel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decodeUnsolicited decodes an unsolicited message, without its ':' terminator, into one of the tally types. It
+// returns nil and no error if the message is not a known tally.
+func decodeUnsolicited(message string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(message, "3"):
+		return decodeKeyerPositionTally(message)
+	case strings.HasPrefix(message, "Y9"):
+		return decodeImageLoadTally(message)
+	case strings.HasPrefix(message, "YA"):
+		return decodeImagePreloadTally(message)
+	case strings.HasPrefix(message, "YB"):
+		return decodeMediaTally(message)
+	case strings.HasPrefix(message, "YS"):
+		return decodePlayStateTally(message)
+	case strings.HasPrefix(message, "Y6"):
+		return decodeVideoTally(message)
+	case strings.HasPrefix(message, "jAY"):
+		return decodeAudioProfileTally(message)
+	case strings.HasPrefix(message, "hOLY"):
+		return decodeLockTally(message)
+	case strings.HasPrefix(message, "hXSY"):
+		return decodeExternalIOSourceChangedTally(message)
+	case strings.HasPrefix(message, "hXDCY"):
+		return decodeExternalIODynamicConfigChangedTally(message)
+	}
+
+	return nil, nil
+}
+
+func tallyError(format string, args ...interface{}) error {
+	return &InvalidTallyError{
+		BaseError: BaseError{
+			Message: fmt.Sprintf(format, args...),
+		},
+	}
+}
+
+// checkTallyLength returns an error if the data of a tally is shorter than n characters.
+func checkTallyLength(name string, data string, n int) error {
+	if len(data) < n {
+		return tallyError("%s is truncated: expected at least %d characters, got %q", name, n, data)
+	}
+	return nil
+}
+
+// parseTallyUint parses a number in a tally, naming the field in the error if it fails.
+func parseTallyUint(s string, base int, bitSize int, what string) (uint64, error) {
+	v, err := strconv.ParseUint(s, base, bitSize)
+	if err != nil {
+		return 0, tallyError("unable to parse %s: %v", what, err)
+	}
+	return v, nil
+}
+
+func decodeKeyerPositionTally(message string) (KeyerPositionTally, error) {
+	data := message[1:]
+	if err := checkTallyLength("KeyerPositionTally", data, 3); err != nil {
+		return KeyerPositionTally{}, err
+	}
+
+	layer, err := parseTallyUint(data[0:1], 16, 8, "layer from KeyerPositionTally")
+	if err != nil {
+		return KeyerPositionTally{}, err
+	}
+	direction, err := parseTallyUint(data[2:3], 10, 8, "direction from KeyerPositionTally")
+	if err != nil {
+		return KeyerPositionTally{}, err
+	}
+
+	return KeyerPositionTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		Layer:     OxtelLayer(layer),
+		Direction: OxtelDirection(direction),
+	}, nil
+}
+
+func decodeImageLoadTally(message string) (ImageLoadTally, error) {
+	data := message[2:]
+	if err := checkTallyLength("ImageLoadTally", data, 1); err != nil {
+		return ImageLoadTally{}, err
+	}
+
+	layer, err := parseTallyUint(data[0:1], 16, 8, "layer from ImageLoadTally")
+	if err != nil {
+		return ImageLoadTally{}, err
+	}
+
+	return ImageLoadTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		Layer:    OxtelLayer(layer),
+		Template: data[1:],
+	}, nil
+}
+
+func decodeImagePreloadTally(message string) (ImagePreloadTally, error) {
+	data := message[2:]
+	if err := checkTallyLength("ImagePreloadTally", data, 1); err != nil {
+		return ImagePreloadTally{}, err
+	}
+
+	layer, err := parseTallyUint(data[0:1], 16, 8, "layer from ImagePreloadTally")
+	if err != nil {
+		return ImagePreloadTally{}, err
+	}
+
+	return ImagePreloadTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		Layer:    OxtelLayer(layer),
+		Template: data[1:],
+	}, nil
+}
+
+func decodeMediaTally(message string) (MediaTally, error) {
+	data := message[2:]
+	if err := checkTallyLength("MediaTally", data, 7); err != nil {
+		return MediaTally{}, err
+	}
+
+	mediaType, err := parseMediaTallies(data[:6])
+	if err != nil {
+		return MediaTally{}, tallyError("unable to parse media type from MediaTally: %q", data[:6])
+	}
+	action, err := parseTallyUint(data[6:7], 16, 8, "action from MediaTally")
+	if err != nil {
+		return MediaTally{}, err
+	}
+
+	return MediaTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		MediaType: mediaType,
+		Action:    OxtelMediaTallies(action),
+		Filename:  data[7:],
+	}, nil
+}
+
+func decodePlayStateTally(message string) (PlayStateTally, error) {
+	data := message[2:]
+	if err := checkTallyLength("PlayStateTally", data, 2); err != nil {
+		return PlayStateTally{}, err
+	}
+
+	layer, err := parseTallyUint(data[0:1], 16, 8, "layer from PlayStateTally")
+	if err != nil {
+		return PlayStateTally{}, err
+	}
+	state, err := parseTallyUint(data[1:2], 16, 8, "state from PlayStateTally")
+	if err != nil {
+		return PlayStateTally{}, err
+	}
+
+	return PlayStateTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		Layer: OxtelLayer(layer),
+		State: OxtelPlayStateTally(state),
+	}, nil
+}
+
+func decodeVideoTally(message string) (VideoTally, error) {
+	data := message[2:]
+	if err := checkTallyLength("VideoTally", data, 9); err != nil {
+		return VideoTally{}, err
+	}
+
+	fields := []struct {
+		s    string
+		what string
+	}{
+		{data[0:1], "a/b mix"},
+		{data[1:2], "layer 0 keyer"},
+		{data[2:3], "layer 1 keyer"},
+		{data[3:4], "mixer A source"},
+		{data[4:5], "mixer B source"},
+		{data[5:7], "unused 1"},
+		{data[7:9], "unused 2"},
+	}
+	var values [7]uint8
+	for i, f := range fields {
+		v, err := parseTallyUint(f.s, 16, 8, f.what+" from VideoTally")
+		if err != nil {
+			return VideoTally{}, err
+		}
+		values[i] = uint8(v)
+	}
+
+	return VideoTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		MixerInput:   values[0],
+		Layer0:       OxtelDirection(values[1]),
+		Layer1:       OxtelDirection(values[2]),
+		MixerASource: OxtelVideoSource(values[3]),
+		MixerBSource: OxtelVideoSource(values[4]),
+		Unused1:      values[5],
+		Unused2:      values[6],
+	}, nil
+}
+
+func decodeAudioProfileTally(message string) (AudioProfileTally, error) {
+	data := message[3:]
+	if err := checkTallyLength("AudioProfileTally", data, 3); err != nil {
+		return AudioProfileTally{}, err
+	}
+
+	source, err := parseTallyUint(data[0:1], 16, 8, "source from AudioProfileTally")
+	if err != nil {
+		return AudioProfileTally{}, err
+	}
+	profile, err := parseTallyUint(data[1:3], 16, 8, "profile from AudioProfileTally")
+	if err != nil {
+		return AudioProfileTally{}, err
+	}
+
+	return AudioProfileTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		Source:  OxtelAudioSource(source),
+		Profile: uint8(profile),
+	}, nil
+}
+
+func decodeLockTally(message string) (LockTally, error) {
+	data := message[4:]
+	if err := checkTallyLength("LockTally", data, 16); err != nil {
+		return LockTally{}, err
+	}
+
+	session, err := parseTallyUint(data[:8], 16, 32, "session locks from LockTally")
+	if err != nil {
+		return LockTally{}, err
+	}
+	permanent, err := parseTallyUint(data[8:16], 16, 32, "permanent lock from LockTally")
+	if err != nil {
+		return LockTally{}, err
+	}
+
+	return LockTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		SessionLocks:   parseLocks(session),
+		PermanentLocks: parseLocks(permanent),
+	}, nil
+}
+
+func decodeExternalIOSourceChangedTally(message string) (ExternalIOSourceChangedTally, error) {
+	data := message[4:]
+	if err := checkTallyLength("ExternalIOSourceChangedTally", data, 10); err != nil {
+		return ExternalIOSourceChangedTally{}, err
+	}
+
+	fields := []struct {
+		s    string
+		what string
+	}{
+		{data[:2], "direction"},
+		{data[2:4], "io id"},
+		{data[4:6], "io type"},
+		{data[6:8], "config id"},
+		{data[8:10], "state"},
+	}
+	var values [5]uint8
+	for i, f := range fields {
+		v, err := parseTallyUint(f.s, 16, 8, f.what+" from ExternalIOSourceChangedTally")
+		if err != nil {
+			return ExternalIOSourceChangedTally{}, err
+		}
+		values[i] = uint8(v)
+	}
+
+	return ExternalIOSourceChangedTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		IODirection:     OxtelExternalIODirection(values[0]),
+		IOId:            OxtelExternalIOId(values[1]),
+		IOType:          OxtelExternalIOType(values[2]),
+		ConfigurationId: values[3],
+		State:           values[4],
+	}, nil
+}
+
+func decodeExternalIODynamicConfigChangedTally(message string) (ExternalIODynamicConfigChangedTally, error) {
+	data := message[5:]
+	if err := checkTallyLength("ExternalIODynamicConfigChangedTally", data, 6); err != nil {
+		return ExternalIODynamicConfigChangedTally{}, err
+	}
+
+	direction, err := parseTallyUint(data[:2], 16, 8, "direction from ExternalIODynamicConfigChangedTally")
+	if err != nil {
+		return ExternalIODynamicConfigChangedTally{}, err
+	}
+	ioId, err := parseTallyUint(data[2:4], 16, 8, "io id from ExternalIODynamicConfigChangedTally")
+	if err != nil {
+		return ExternalIODynamicConfigChangedTally{}, err
+	}
+	ioType, err := parseTallyUint(data[4:6], 16, 8, "io type from ExternalIODynamicConfigChangedTally")
+	if err != nil {
+		return ExternalIODynamicConfigChangedTally{}, err
+	}
+
+	parts := strings.Split(data, ",")
+	want := map[uint64]int{
+		OXTEL_EXT_IO_TYPE_SDI:           1,
+		OXTEL_EXT_IO_TYPE_2022_6:        4,
+		OXTEL_EXT_IO_TYPE_2022_6_2022_7: 6,
+		OXTEL_EXT_IO_TYPE_2110:          3,
+	}[ioType]
+	if len(parts) < want {
+		return ExternalIODynamicConfigChangedTally{}, tallyError("ExternalIODynamicConfigChangedTally for io type %d has %d fields, expected %d", ioType, len(parts)-1, want-1)
+	}
+
+	var localInterface *string
+	var ipAddress *string
+	var port *uint32
+	var ipAddress2 *string
+	var port2 *uint32
+	var sdfFileName *string
+
+	if ioType != OXTEL_EXT_IO_TYPE_SDI && len(parts) > 1 {
+		localInterface = &parts[1]
+	}
+	if ioType == OXTEL_EXT_IO_TYPE_2022_6 || ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
+		ipAddress = &parts[2]
+		tmpPort, err := parseTallyUint(parts[3], 10, 32, "port from ExternalIODynamicConfigChangedTally")
+		if err != nil {
+			return ExternalIODynamicConfigChangedTally{}, err
+		}
+		w := uint32(tmpPort)
+		port = &w
+	}
+	if ioType == OXTEL_EXT_IO_TYPE_2022_6_2022_7 {
+		ipAddress2 = &parts[4]
+		tmpPort, err := parseTallyUint(parts[5], 10, 32, "port2 from ExternalIODynamicConfigChangedTally")
+		if err != nil {
+			return ExternalIODynamicConfigChangedTally{}, err
+		}
+		w := uint32(tmpPort)
+		port2 = &w
+	}
+
+	if ioType == OXTEL_EXT_IO_TYPE_2110 {
+		sdfFileName = &parts[2]
+	}
+
+	return ExternalIODynamicConfigChangedTally{
+		UnsolicitedMessage: UnsolicitedMessage{
+			Raw: message,
+		},
+		IODirection:    OxtelExternalIODirection(direction),
+		IOId:           OxtelExternalIOId(ioId),
+		IOType:         OxtelExternalIOType(ioType),
+		LocalInterface: localInterface,
+		IPAddress:      ipAddress,
+		Port:           port,
+		IPAddress2:     ipAddress2,
+		Port2:          port2,
+		SDPFileName:    sdfFileName,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package oxtel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryansavara/go-oxtel/oxtel/oxteltest"
+)
+
+var tallySeeds = []string{
+	"31 1",
+	"Y92lower.html",
+	"YA3bug.html",
+	"YB0000011a.html",
+	"YS21",
+	"Y61100101000",
+	"jAY10f",
+	"hOLY0000010100000001",
+	"hXSY0102030405",
+	"hXDCY010201,eth0,239.0.0.1,5000",
+	"hXDCY010204,eth0,239.0.0.1,5000,239.0.0.2,5002",
+	"hXDCY010203,eth1,out.sdp",
+	"hXDCY010200",
+}
+
+func TestDecodeUnsolicited(t *testing.T) {
+	for _, seed := range tallySeeds {
+		tally, err := decodeUnsolicited(seed)
+		if err != nil {
+			t.Errorf("%q: %v", seed, err)
+			continue
+		}
+		if tally == nil {
+			t.Errorf("%q was not recognised as a tally", seed)
+		}
+	}
+
+	for _, malformed := range []string{"3", "3x 1", "Y9", "YBxx", "YS2", "Y6110", "jAY1", "hOLY0000", "hXSY01", "hXDCY0102", "hXDCY010201,eth0"} {
+		if _, err := decodeUnsolicited(malformed); err == nil {
+			t.Errorf("%q: expected an error", malformed)
+		} else if _, ok := err.(*InvalidTallyError); !ok {
+			t.Errorf("%q: expected an InvalidTallyError, got %T", malformed, err)
+		}
+	}
+}
+
+func TestMalformedTallyIsDelivered(t *testing.T) {
+	srv := oxteltest.NewServer()
+	defer srv.Close()
+
+	client := NewOxtel(srv.Host(), srv.Port())
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	received := make(chan interface{}, 1)
+	go func() {
+		for msg := range client.Unsolicited {
+			received <- msg
+		}
+	}()
+
+	// Unsolicited messages are dropped while nobody is receiving, so keep sending each line until it arrives.
+	for line, check := range map[string]func(msg interface{}) bool{
+		"3": func(msg interface{}) bool {
+			malformed, ok := msg.(MalformedTally)
+			return ok && malformed.Raw == "3" && malformed.Err != nil
+		},
+		"31 1": func(msg interface{}) bool {
+			tally, ok := msg.(KeyerPositionTally)
+			return ok && tally.Layer == OXTEL_LAYER_1 && tally.Direction == OXTEL_DIR_UP
+		},
+	} {
+		timeout := time.After(time.Second)
+	wait:
+		for {
+			srv.Broadcast(line)
+			select {
+			case msg := <-received:
+				// Earlier lines may be delivered more than once.
+				if check(msg) {
+					break wait
+				}
+			case <-time.After(10 * time.Millisecond):
+			case <-timeout:
+				t.Fatalf("timed out waiting for %q", line)
+			}
+		}
+	}
+}
+
+func FuzzDecodeUnsolicited(f *testing.F) {
+	for _, seed := range tallySeeds {
+		f.Add(seed)
+		for i := 1; i < len(seed); i++ {
+			f.Add(seed[:i])
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, message string) {
+		tally, err := decodeUnsolicited(message)
+		if err != nil {
+			if _, ok := err.(*InvalidTallyError); !ok {
+				t.Fatalf("%q: expected an InvalidTallyError, got %T", message, err)
+			}
+			return
+		}
+		if tally != nil && !strings.HasPrefix(message, "3") && !strings.HasPrefix(message, "Y") &&
+			!strings.HasPrefix(message, "jAY") && !strings.HasPrefix(message, "h") {
+			t.Fatalf("%q decoded as %#v", message, tally)
+		}
+	})
+}
+
+func FuzzHandleMessage(f *testing.F) {
+	for _, seed := range tallySeeds {
+		f.Add(seed + ":")
+	}
+	f.Add(":")
+
+	client := NewOxtel("", 0)
+	client.Unsolicited = make(chan interface{}, 1)
+
+	f.Fuzz(func(t *testing.T, message string) {
+		if !strings.HasSuffix(message, ":") {
+			message += ":"
+		}
+		client.handleMessage(message)
+		select {
+		case <-client.Unsolicited:
+		default:
+			t.Fatalf("%q was not delivered", message)
+		}
+	})
+}
@@ -79,3 +79,10 @@ type ExternalIODynamicConfigChangedTally struct {
 	Port2          *uint32
 	SDPFileName    *string
 }
+
+// MalformedTally is delivered on the Unsolicited channel in place of a tally that could not be decoded. Raw is the
+// message as received and Err describes why it could not be decoded.
+type MalformedTally struct {
+	UnsolicitedMessage
+	Err error
+}
@@ -1,6 +1,22 @@
 package oxtel
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+var commandEscaper = strings.NewReplacer("\\", "\\5C", "|", "\\7C", ";", "\\3B", ":", "\\3A")
+var commandUnescaper = strings.NewReplacer("\\5C", "\\", "\\7C", "|", "\\3B", ";", "\\3A", ":")
+
+// escapeCommand escapes the characters that have a special meaning in the protocol.
+func escapeCommand(cmd string) string {
+	return commandEscaper.Replace(cmd)
+}
+
+// unescapeCommand reverses escapeCommand.
+func unescapeCommand(cmd string) string {
+	return commandUnescaper.Replace(cmd)
+}
 
 func boolToInt(input bool) int16 {
 	if input {
@@ -65,194 +81,88 @@ func buildMediaTallies(data MediaTallies) string {
 	return msg
 }
 
-func buildAudioProgramCommand(program AudioProgram) (string, error) {
-	if program.Channel1 > 15 {
-		return "", &InvalidAudioProfileError{
-			BaseError: BaseError{
-				Message: "Channel 1 must be less than 16",
-			},
+func parseMediaTallies(msg string) (MediaTallies, error) {
+	if len(msg) != 6 || strings.Trim(msg, "01") != "" {
+		return MediaTallies{}, &BaseError{
+			Message: fmt.Sprintf("Invalid media tallies %q", msg),
 		}
 	}
+
+	return MediaTallies{
+		Unused1: msg[0] == '1',
+		Unused2: msg[1] == '1',
+		Unused3: msg[2] == '1',
+		Unused4: msg[3] == '1',
+		Unused5: msg[4] == '1',
+		Images:  msg[5] == '1',
+	}, nil
+}
+
+func buildAudioProgramCommand(program AudioProgram) string {
 	msg := fmt.Sprintf("jAL%02x%02x%02x%02x", program.Sdi, program.AudioProgram, program.JungerPreset, program.Channel1)
 
-	if program.Channel2 != nil {
-		if *program.Channel2 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 2 must be less than 16",
-				},
-			}
-		}
-		msg = fmt.Sprintf("%s%02x", msg, *program.Channel2)
-	}
-	if program.Channel3 != nil {
-		if *program.Channel3 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 3 must be less than 16",
-				},
-			}
-		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel3)
-	}
-	if program.Channel4 != nil {
-		if *program.Channel4 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 4 must be less than 16",
-				},
-			}
+	for _, channel := range audioProgramChannels(program)[1:] {
+		if channel != nil {
+			msg = fmt.Sprintf("%s%02x", msg, *channel)
 		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel4)
 	}
-	if program.Channel5 != nil {
-		if *program.Channel5 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 5 must be less than 16",
-				},
-			}
-		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel5)
-	}
-	if program.Channel6 != nil {
-		if *program.Channel6 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 6 must be less than 16",
-				},
-			}
-		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel6)
-	}
-	if program.Channel7 != nil {
-		if *program.Channel7 > 15 {
-			return "", &InvalidAudioProfileError{
-				BaseError: BaseError{
-					Message: "Channel 7 must be less than 16",
-				},
-			}
-		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel7)
-	}
-	if program.Channel8 != nil {
-		if *program.Channel8 > 15 {
-			return "", &InvalidAudioProfileError{
+	return msg
+}
+
+func validateAudioProgram(program AudioProgram) error {
+	for i, channel := range audioProgramChannels(program) {
+		if channel != nil && *channel > 15 {
+			return &InvalidAudioProfileError{
 				BaseError: BaseError{
-					Message: "Channel 8 must be less than 16",
+					Message: fmt.Sprintf("Channel %d must be less than 16", i+1),
 				},
 			}
 		}
-		msg = fmt.Sprintf("%s%02x", msg, program.Channel8)
 	}
-	return msg, nil
+	return nil
+}
 
+func audioProgramChannels(program AudioProgram) []*uint8 {
+	return []*uint8{
+		&program.Channel1,
+		program.Channel2,
+		program.Channel3,
+		program.Channel4,
+		program.Channel5,
+		program.Channel6,
+		program.Channel7,
+		program.Channel8,
+	}
 }
 
 func MakeTwoChannelMask() ChannelMask {
-	return ChannelMask{
-		Channel1:  true,
-		Channel2:  true,
-		Channel3:  false,
-		Channel4:  false,
-		Channel5:  false,
-		Channel6:  false,
-		Channel7:  false,
-		Channel8:  false,
-		Channel9:  false,
-		Channel10: false,
-		Channel11: false,
-		Channel12: false,
-		Channel13: false,
-		Channel14: false,
-		Channel15: false,
-		Channel16: false,
-	}
+	return 0x0003
 }
 
 func MakeFourChannelMask() ChannelMask {
-	return ChannelMask{
-		Channel1:  true,
-		Channel2:  true,
-		Channel3:  true,
-		Channel4:  true,
-		Channel5:  false,
-		Channel6:  false,
-		Channel7:  false,
-		Channel8:  false,
-		Channel9:  false,
-		Channel10: false,
-		Channel11: false,
-		Channel12: false,
-		Channel13: false,
-		Channel14: false,
-		Channel15: false,
-		Channel16: false,
-	}
+	return 0x000F
 }
 
 func MakeEightChannelMask() ChannelMask {
-	return ChannelMask{
-		Channel1:  true,
-		Channel2:  true,
-		Channel3:  true,
-		Channel4:  true,
-		Channel5:  true,
-		Channel6:  true,
-		Channel7:  true,
-		Channel8:  true,
-		Channel9:  false,
-		Channel10: false,
-		Channel11: false,
-		Channel12: false,
-		Channel13: false,
-		Channel14: false,
-		Channel15: false,
-		Channel16: false,
-	}
+	return 0x00FF
 }
 
 func MakeSixteenChannelMask() ChannelMask {
-	return ChannelMask{
-		Channel1:  true,
-		Channel2:  true,
-		Channel3:  true,
-		Channel4:  true,
-		Channel5:  true,
-		Channel6:  true,
-		Channel7:  true,
-		Channel8:  true,
-		Channel9:  true,
-		Channel10: true,
-		Channel11: true,
-		Channel12: true,
-		Channel13: true,
-		Channel14: true,
-		Channel15: true,
-		Channel16: true,
-	}
+	return AllChannels
 }
 
-func buildChannelMask(mask ChannelMask) string {
-	var outMask int16
-	outMask |= boolToInt(mask.Channel1)
-	outMask |= boolToInt(mask.Channel2) << 1
-	outMask |= boolToInt(mask.Channel3) << 2
-	outMask |= boolToInt(mask.Channel4) << 3
-	outMask |= boolToInt(mask.Channel5) << 4
-	outMask |= boolToInt(mask.Channel6) << 5
-	outMask |= boolToInt(mask.Channel7) << 6
-	outMask |= boolToInt(mask.Channel8) << 7
-	outMask |= boolToInt(mask.Channel9) << 8
-	outMask |= boolToInt(mask.Channel10) << 9
-	outMask |= boolToInt(mask.Channel11) << 10
-	outMask |= boolToInt(mask.Channel12) << 11
-	outMask |= boolToInt(mask.Channel13) << 12
-	outMask |= boolToInt(mask.Channel14) << 13
-	outMask |= boolToInt(mask.Channel15) << 14
-	outMask |= boolToInt(mask.Channel16) << 15
-
-	return fmt.Sprintf("%04X", outMask)
+func parseLocks(locks uint64) LocksResponse {
+	return LocksResponse{
+		Mixer:  (locks & 0x00000001) != 0,
+		Layer0: (locks & 0x00000100) != 0,
+		Layer1: (locks & 0x00000200) != 0,
+		Layer2: (locks & 0x00000400) != 0,
+		Layer3: (locks & 0x00000800) != 0,
+		Layer4: (locks & 0x00001000) != 0,
+		Layer5: (locks & 0x00002000) != 0,
+		Layer6: (locks & 0x00004000) != 0,
+		Layer7: (locks & 0x00008000) != 0,
+	}
 }
 
 // BuildSessionLocks takes in booleans for the mixer and each graphic layer to calculate the bitwise representation
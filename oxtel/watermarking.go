@@ -1,28 +1,59 @@
 package oxtel
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
+
+// OverrideSDIInputColorSpaceCommand is the Command sent by OverrideSDIInputColorSpace.
+type OverrideSDIInputColorSpaceCommand struct {
+	Input      OxtelMixerInput
+	ColorSpace OxtelColorSpace
+}
+
+func (c OverrideSDIInputColorSpaceCommand) Encode() string {
+	return fmt.Sprintf("hCSI%01x%01x", c.Input, c.ColorSpace)
+}
+
+func (c OverrideSDIInputColorSpaceCommand) Validate() error {
+	return nil
+}
 
 // OverrideSDIInputColorSpace overrides the color space for a given SDI input. Once the command is issued,
 // it will stick until another OverrideSDIInputColorSpace command or the MCS is reconfigured.
 //
 // If the MCS is reconfigured, it will revert back to the SystemManager settings.
 func (o *Oxtel) OverrideSDIInputColorSpace(input OxtelMixerInput, colorSpace OxtelColorSpace) error {
-	return o.sendCommand(fmt.Sprintf("hCSI%01x%01x", input, colorSpace))
+	return o.OverrideSDIInputColorSpaceContext(context.Background(), input, colorSpace)
+}
+
+// OverrideSDIInputColorSpaceContext is like OverrideSDIInputColorSpace but uses ctx for cancellation and deadlines.
+func (o *Oxtel) OverrideSDIInputColorSpaceContext(ctx context.Context, input OxtelMixerInput, colorSpace OxtelColorSpace) error {
+	return o.SendContext(ctx, OverrideSDIInputColorSpaceCommand{Input: input, ColorSpace: colorSpace})
 }
 
 // OverrideSDIInputColorSpace_AsString returns the command string used to override the color space for a given SDI input. Once the command is issued,
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with an OverrideSDIInputColorSpaceCommand instead.
 func OverrideSDIInputColorSpace_AsString(input OxtelMixerInput, colorSpace OxtelColorSpace) string {
-	return fmt.Sprintf("hCSI%01x%01x", input, colorSpace)
+	return OverrideSDIInputColorSpaceCommand{Input: input, ColorSpace: colorSpace}.Encode()
 }
 
-// ChangeKantarWatermarkingChannelName changes the Kantar watermarking channel name.
-// The name must be a valid name contained in the system's Kantar audience license.
-//
-// Note: If Kantar is set to Mirror, then any OxtelKantarOutput will select both.
-func (o *Oxtel) ChangeKantarWatermarkingChannelName(output OxtelKantarOutput, startingChannel uint8, audienceName string) error {
-	if startingChannel > 15 {
+// ChangeKantarWatermarkingChannelNameCommand is the Command sent by ChangeKantarWatermarkingChannelName.
+type ChangeKantarWatermarkingChannelNameCommand struct {
+	Output          OxtelKantarOutput
+	StartingChannel uint8
+	AudienceName    string
+}
+
+func (c ChangeKantarWatermarkingChannelNameCommand) Encode() string {
+	return fmt.Sprintf("hKWM%01x%02x%s", c.Output, c.StartingChannel, c.AudienceName)
+}
+
+func (c ChangeKantarWatermarkingChannelNameCommand) Validate() error {
+	if c.StartingChannel > 15 {
 		return &InvalidAudioChannelError{
 			BaseError: BaseError{
 				Message: "Starting Channel must be less than 16",
@@ -30,12 +61,35 @@ func (o *Oxtel) ChangeKantarWatermarkingChannelName(output OxtelKantarOutput, st
 		}
 	}
 
-	return o.sendCommand(fmt.Sprintf("hKWM%01x%02x%s", output, startingChannel, audienceName))
+	return nil
+}
+
+// ChangeKantarWatermarkingChannelName changes the Kantar watermarking channel name.
+// The name must be a valid name contained in the system's Kantar audience license.
+//
+// Note: If Kantar is set to Mirror, then any OxtelKantarOutput will select both.
+func (o *Oxtel) ChangeKantarWatermarkingChannelName(output OxtelKantarOutput, startingChannel uint8, audienceName string) error {
+	return o.ChangeKantarWatermarkingChannelNameContext(context.Background(), output, startingChannel, audienceName)
+}
+
+// ChangeKantarWatermarkingChannelNameContext is like ChangeKantarWatermarkingChannelName but uses ctx for cancellation and deadlines.
+func (o *Oxtel) ChangeKantarWatermarkingChannelNameContext(ctx context.Context, output OxtelKantarOutput, startingChannel uint8, audienceName string) error {
+	return o.SendContext(ctx, ChangeKantarWatermarkingChannelNameCommand{
+		Output:          output,
+		StartingChannel: startingChannel,
+		AudienceName:    audienceName,
+	})
 }
 
 // ChangeKantarWatermarkingChannelName_AsString returns the command string used to change the Kantar watermarking channel name.
 //
 // For use with scheduled commands.
+//
+// Deprecated: The command is not validated. Use EncodeCommand with a ChangeKantarWatermarkingChannelNameCommand instead.
 func ChangeKantarWatermarkingChannelName_AsString(output OxtelKantarOutput, startingChannel uint8, audienceName string) string {
-	return fmt.Sprintf("hKWM%01x%02x%s", output, startingChannel, audienceName)
+	return ChangeKantarWatermarkingChannelNameCommand{
+		Output:          output,
+		StartingChannel: startingChannel,
+		AudienceName:    audienceName,
+	}.Encode()
 }